@@ -38,6 +38,9 @@ const (
 	// ConfigureResponse is a JSON file that describes which library to change
 	// after initial configuration.
 	ConfigureResponse = "configure-response.json"
+	// DescribeResponse is a JSON file that describes the capabilities of a
+	// language container.
+	DescribeResponse = "describe-response.json"
 	// GeneratorInputDir is the default directory to store files that generator
 	// needs to regenerate libraries from an empty directory.
 	GeneratorInputDir = ".librarian/generator-input"
@@ -49,6 +52,17 @@ const (
 	// LibrarianDir is the default directory to store librarian state/config files,
 	// along with any additional configuration.
 	LibrarianDir = ".librarian"
+	// PublishRequest is a JSON file that describes which library to publish.
+	PublishRequest = "publish-request.json"
+	// PublishResponse is a JSON file that reports the result of publishing a
+	// library.
+	PublishResponse = "publish-response.json"
+	// PackageRequest is a JSON file that describes which library to package
+	// release artifacts for.
+	PackageRequest = "package-request.json"
+	// PackageResponse is a JSON file that reports the result of packaging the
+	// release artifacts of a library.
+	PackageResponse = "package-response.json"
 	// ReleaseInitRequest is a JSON file that describes which library to release.
 	ReleaseInitRequest = "release-init-request.json"
 	// ReleaseInitResponse is a JSON file that describes which library to change
@@ -96,7 +110,8 @@ type Config struct {
 	APISource string
 
 	// APISourceDepth controls the depth of the repository closing **IF**
-	// APISource is a GitHub repository, and it is cloned.
+	// APISource is a GitHub repository, and it is cloned. The history is
+	// deepened as needed to reach the last generated commit of each library.
 	APISourceDepth int
 
 	// Branch is the remote branch of the language repository to use.
@@ -127,6 +142,13 @@ type Config struct {
 	// This flag is ignored if Push is set to true.
 	Commit bool
 
+	// DryRun determines whether tag-and-release only checks that the released
+	// libraries can be published, without publishing them. A dry run does not
+	// write to GitHub.
+	//
+	// Requires the --publish flag to be specified.
+	DryRun bool
+
 	// GitHubAPIEndpoint is the GitHub API endpoint to use for all GitHub API
 	// operations.
 	//
@@ -136,7 +158,7 @@ type Config struct {
 	// GitHubToken is the access token to use for all operations involving
 	// GitHub.
 	//
-	// GitHubToken is used by to configure, update-apis and update-image-tag commands,
+	// GitHubToken is used by the generate, release and update-image commands,
 	// when Push is true.
 	//
 	// GitHubToken is not specified by a flag, as flags are logged and the
@@ -160,6 +182,9 @@ type Config struct {
 	// (potentially including a repository and/or tag). If the -image flag is not
 	// set, use an image configured in the `config.yaml`.
 	//
+	// Image is required by the update-image command, which records it in the
+	// state.yaml file.
+	//
 	// Image is specified with the -image flag.
 	Image string
 
@@ -167,6 +192,9 @@ type Config struct {
 	// This usually corresponds to a releasable language unit -- for Go this would
 	// be a Go module or for dotnet the name of a NuGet package. If neither this nor
 	// api is specified all currently managed libraries will be regenerated.
+	//
+	// For tag-and-release, Library restricts the releases of each pull request to
+	// a single library, for example to publish it again after a failure.
 	Library string
 
 	// LibraryVersion is the library version to release.
@@ -179,6 +207,20 @@ type Config struct {
 	// Requires the --library flag to be specified.
 	LibraryVersion string
 
+	// MaxFailurePercent is the percentage of libraries which may fail to
+	// generate with a new image before update-image rolls back the image in
+	// the state.yaml file and stops without creating a pull request.
+	//
+	// MaxFailurePercent is specified with the -max-failure-percent flag.
+	MaxFailurePercent int
+
+	// Publish determines whether tag-and-release asks the language container
+	// to publish each released library to its package registry. The result of
+	// publishing each library is commented on the release pull request.
+	//
+	// Publish is specified with the -publish flag.
+	Publish bool
+
 	// PullRequest to target and operate one in the context of a release.
 	//
 	// The pull request should be in the format `https://github.com/{owner}/{repo}/pull/{number}`.
@@ -203,6 +245,14 @@ type Config struct {
 	// Push is specified with the -push flag. No value is required.
 	Push bool
 
+	// ReleaseArtifacts determines whether tag-and-release asks the language
+	// container to package release artifacts for each released library, and
+	// attaches them, along with their SHA-256 checksums, to the GitHub
+	// releases.
+	//
+	// ReleaseArtifacts is specified with the -release-artifacts flag.
+	ReleaseArtifacts bool
+
 	// Repo specifies the language repository to use, as either a local root directory
 	// or a URL to clone from. If a local directory is specified, it can
 	// be relative to the current working directory. The repository must
@@ -320,6 +370,14 @@ func (c *Config) IsValid() (bool, error) {
 		return false, errors.New("specified library version without library id")
 	}
 
+	if c.DryRun && !c.Publish {
+		return false, errors.New("specified dry run without publish")
+	}
+
+	if c.MaxFailurePercent < 0 || c.MaxFailurePercent > 100 {
+		return false, fmt.Errorf("max failure percent must be between 0 and 100, got %d", c.MaxFailurePercent)
+	}
+
 	if c.PullRequest != "" {
 		matched := pullRequestRegexp.MatchString(c.PullRequest)
 		if !matched {
@@ -194,6 +194,23 @@ func TestIsValid(t *testing.T) {
 			wantErr:    true,
 			wantErrMsg: "specified library version without library id",
 		},
+		{
+			name: "Valid config - dry run with publish",
+			cfg: Config{
+				DryRun:  true,
+				Publish: true,
+				Repo:    "/tmp/some/repo",
+			},
+		},
+		{
+			name: "Invalid config - dry run without publish",
+			cfg: Config{
+				DryRun: true,
+				Repo:   "/tmp/some/repo",
+			},
+			wantErr:    true,
+			wantErrMsg: "specified dry run without publish",
+		},
 		{
 			name: "Invalid config - host mount invalid, missing local-dir",
 			cfg: Config{
@@ -221,6 +238,15 @@ func TestIsValid(t *testing.T) {
 			wantErr:    true,
 			wantErrMsg: "unable to parse host mount",
 		},
+		{
+			name: "Invalid config - max failure percent out of range",
+			cfg: Config{
+				MaxFailurePercent: 101,
+				Repo:              "/tmp/some/repo",
+			},
+			wantErr:    true,
+			wantErrMsg: "max failure percent must be between 0 and 100",
+		},
 		{
 			name: "Invalid config -  missing Repo",
 			cfg: Config{
@@ -16,6 +16,7 @@ package config
 
 import (
 	"fmt"
+	"regexp"
 )
 
 const (
@@ -24,10 +25,107 @@ const (
 	PermissionReadWrite = "read-write"
 )
 
+// DefaultAPISourceCommonPaths are the directories of the API source always
+// checked out in a sparse checkout, unless configured otherwise. They contain
+// the protos imported by most APIs.
+var DefaultAPISourceCommonPaths = []string{
+	"google/api",
+	"google/cloud/location",
+	"google/iam/v1",
+	"google/longrunning",
+	"google/rpc",
+	"google/type",
+}
+
 // LibrarianConfig defines the contract for the config.yaml file.
 type LibrarianConfig struct {
 	GlobalFilesAllowlist []*GlobalFile    `yaml:"global_files_allowlist"`
 	Libraries            []*LibraryConfig `yaml:"libraries"`
+	// APISourceSparseCheckout enables sparse and partial clones of the API
+	// sources, limited to the API paths in state.yaml and the common paths.
+	APISourceSparseCheckout bool `yaml:"api_source_sparse_checkout,omitempty"`
+	// APISourceCommonPaths are the directories always checked out in a
+	// sparse checkout of the API sources. Defaults to
+	// DefaultAPISourceCommonPaths.
+	APISourceCommonPaths []string `yaml:"api_source_common_paths,omitempty"`
+	// GenerationPullRequests configures how the changes from `generate` are
+	// split into pull requests. By default, a single pull request contains
+	// all the generated libraries.
+	GenerationPullRequests *GenerationPullRequests `yaml:"generation_pull_requests,omitempty"`
+}
+
+const (
+	// SplitByLibrary creates one generation pull request per library.
+	SplitByLibrary = "library"
+	// SplitByGroup creates one generation pull request per group of
+	// libraries. Libraries not in any group get a pull request each.
+	SplitByGroup = "group"
+)
+
+var pullRequestGroupNameRegex = regexp.MustCompile(`^[a-z0-9_-]+$`)
+
+// GenerationPullRequests configures how the changes from `generate` are
+// split into pull requests.
+type GenerationPullRequests struct {
+	// Split is either SplitByLibrary or SplitByGroup. If empty, a single
+	// pull request is created.
+	Split string `yaml:"split"`
+	// Groups are the groups of libraries sharing a pull request when Split
+	// is SplitByGroup.
+	Groups []*PullRequestGroup `yaml:"groups,omitempty"`
+}
+
+// PullRequestGroup is a named group of libraries whose generated changes are
+// proposed in the same pull request.
+type PullRequestGroup struct {
+	// Name identifies the group, and is used in the branch name and title of
+	// its pull requests.
+	Name string `yaml:"name"`
+	// Libraries are the IDs of the libraries in the group.
+	Libraries []string `yaml:"libraries"`
+	// Labels are added to the pull requests of the group.
+	Labels []string `yaml:"labels,omitempty"`
+}
+
+// Validate checks that the GenerationPullRequests is valid.
+func (g *GenerationPullRequests) Validate() error {
+	switch g.Split {
+	case "", SplitByLibrary:
+		if len(g.Groups) != 0 {
+			return fmt.Errorf("pull request groups require split %q, got %q", SplitByGroup, g.Split)
+		}
+		return nil
+	case SplitByGroup:
+	default:
+		return fmt.Errorf("invalid split %q, must be %q or %q", g.Split, SplitByLibrary, SplitByGroup)
+	}
+	names := map[string]bool{}
+	grouped := map[string]string{}
+	for i, group := range g.Groups {
+		if group == nil {
+			return fmt.Errorf("pull request group at index %d is nil", i)
+		}
+		if !pullRequestGroupNameRegex.MatchString(group.Name) {
+			return fmt.Errorf("invalid pull request group name at index %d: %q", i, group.Name)
+		}
+		if names[group.Name] {
+			return fmt.Errorf("duplicate pull request group %q", group.Name)
+		}
+		names[group.Name] = true
+		if len(group.Libraries) == 0 {
+			return fmt.Errorf("pull request group %q has no libraries", group.Name)
+		}
+		for _, id := range group.Libraries {
+			if !libraryIDRegex.MatchString(id) {
+				return fmt.Errorf("invalid library in pull request group %q: %q", group.Name, id)
+			}
+			if other, ok := grouped[id]; ok {
+				return fmt.Errorf("library %s is in pull request groups %q and %q", id, other, group.Name)
+			}
+			grouped[id] = group.Name
+		}
+	}
+	return nil
 }
 
 // LibraryConfig defines configuration for a single library, identified by its ID.
@@ -35,6 +133,10 @@ type LibraryConfig struct {
 	LibraryID       string `yaml:"id"`
 	NextVersion     string `yaml:"next_version"`
 	GenerateBlocked bool   `yaml:"generate_blocked"`
+	// Dependencies lists the IDs of other libraries in the same repository
+	// that this library depends on. When one of them is released, this
+	// library receives a patch release as well.
+	Dependencies []string `yaml:"dependencies,omitempty"`
 }
 
 // GlobalFile defines the global files in language repositories.
@@ -60,10 +162,39 @@ func (g *LibrarianConfig) Validate() error {
 			return fmt.Errorf("invalid global file permissions at index %d: %q", i, permissions)
 		}
 	}
+	for i, path := range g.APISourceCommonPaths {
+		if !isValidDirPath(path) {
+			return fmt.Errorf("invalid api source common path at index %d: %q", i, path)
+		}
+	}
+	if g.GenerationPullRequests != nil {
+		if err := g.GenerationPullRequests.Validate(); err != nil {
+			return fmt.Errorf("invalid generation pull requests: %w", err)
+		}
+	}
+	for i, library := range g.Libraries {
+		for j, dep := range library.Dependencies {
+			if !libraryIDRegex.MatchString(dep) {
+				return fmt.Errorf("invalid dependency at index %d of library at index %d: %q", j, i, dep)
+			}
+			if dep == library.LibraryID {
+				return fmt.Errorf("library %s cannot depend on itself", library.LibraryID)
+			}
+		}
+	}
 
 	return nil
 }
 
+// CommonAPISourcePaths returns the directories always checked out in a sparse
+// checkout of the API sources.
+func (g *LibrarianConfig) CommonAPISourcePaths() []string {
+	if len(g.APISourceCommonPaths) != 0 {
+		return g.APISourceCommonPaths
+	}
+	return DefaultAPISourceCommonPaths
+}
+
 // LibraryConfigFor finds the LibraryConfig entry for a given LibraryID.
 func (g *LibrarianConfig) LibraryConfigFor(LibraryID string) *LibraryConfig {
 	for _, lib := range g.Libraries {
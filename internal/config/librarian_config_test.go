@@ -81,6 +81,148 @@ func TestGlobalConfig_Validate(t *testing.T) {
 			wantErr:    true,
 			wantErrMsg: "invalid global file permissions",
 		},
+		{
+			name: "valid api source common paths",
+			config: &LibrarianConfig{
+				APISourceSparseCheckout: true,
+				APISourceCommonPaths:    []string{"google/api", "google/rpc"},
+			},
+		},
+		{
+			name: "invalid api source common path",
+			config: &LibrarianConfig{
+				APISourceCommonPaths: []string{"google/api", "/google/rpc"},
+			},
+			wantErr:    true,
+			wantErrMsg: "invalid api source common path",
+		},
+		{
+			name: "valid generation pull requests by library",
+			config: &LibrarianConfig{
+				GenerationPullRequests: &GenerationPullRequests{Split: SplitByLibrary},
+			},
+		},
+		{
+			name: "valid generation pull requests by group",
+			config: &LibrarianConfig{
+				GenerationPullRequests: &GenerationPullRequests{
+					Split: SplitByGroup,
+					Groups: []*PullRequestGroup{
+						{Name: "storage", Libraries: []string{"storage", "storage-control"}, Labels: []string{"api: storage"}},
+						{Name: "pubsub", Libraries: []string{"pubsub"}},
+					},
+				},
+			},
+		},
+		{
+			name: "invalid generation pull requests split",
+			config: &LibrarianConfig{
+				GenerationPullRequests: &GenerationPullRequests{Split: "api"},
+			},
+			wantErr:    true,
+			wantErrMsg: "invalid split",
+		},
+		{
+			name: "generation pull request groups without split by group",
+			config: &LibrarianConfig{
+				GenerationPullRequests: &GenerationPullRequests{
+					Split:  SplitByLibrary,
+					Groups: []*PullRequestGroup{{Name: "storage", Libraries: []string{"storage"}}},
+				},
+			},
+			wantErr:    true,
+			wantErrMsg: "pull request groups require split",
+		},
+		{
+			name: "invalid generation pull request group name",
+			config: &LibrarianConfig{
+				GenerationPullRequests: &GenerationPullRequests{
+					Split:  SplitByGroup,
+					Groups: []*PullRequestGroup{{Name: "Storage APIs", Libraries: []string{"storage"}}},
+				},
+			},
+			wantErr:    true,
+			wantErrMsg: "invalid pull request group name",
+		},
+		{
+			name: "duplicate generation pull request group",
+			config: &LibrarianConfig{
+				GenerationPullRequests: &GenerationPullRequests{
+					Split: SplitByGroup,
+					Groups: []*PullRequestGroup{
+						{Name: "storage", Libraries: []string{"storage"}},
+						{Name: "storage", Libraries: []string{"storage-control"}},
+					},
+				},
+			},
+			wantErr:    true,
+			wantErrMsg: "duplicate pull request group",
+		},
+		{
+			name: "empty generation pull request group",
+			config: &LibrarianConfig{
+				GenerationPullRequests: &GenerationPullRequests{
+					Split:  SplitByGroup,
+					Groups: []*PullRequestGroup{{Name: "storage"}},
+				},
+			},
+			wantErr:    true,
+			wantErrMsg: "has no libraries",
+		},
+		{
+			name: "invalid library in generation pull request group",
+			config: &LibrarianConfig{
+				GenerationPullRequests: &GenerationPullRequests{
+					Split:  SplitByGroup,
+					Groups: []*PullRequestGroup{{Name: "storage", Libraries: []string{"storage lib"}}},
+				},
+			},
+			wantErr:    true,
+			wantErrMsg: "invalid library in pull request group",
+		},
+		{
+			name: "library in two generation pull request groups",
+			config: &LibrarianConfig{
+				GenerationPullRequests: &GenerationPullRequests{
+					Split: SplitByGroup,
+					Groups: []*PullRequestGroup{
+						{Name: "storage", Libraries: []string{"storage"}},
+						{Name: "all", Libraries: []string{"pubsub", "storage"}},
+					},
+				},
+			},
+			wantErr:    true,
+			wantErrMsg: "is in pull request groups",
+		},
+		{
+			name: "valid dependencies",
+			config: &LibrarianConfig{
+				Libraries: []*LibraryConfig{
+					{LibraryID: "lib1"},
+					{LibraryID: "lib2", Dependencies: []string{"lib1"}},
+				},
+			},
+		},
+		{
+			name: "invalid dependency id",
+			config: &LibrarianConfig{
+				Libraries: []*LibraryConfig{
+					{LibraryID: "lib1", Dependencies: []string{"lib 2"}},
+				},
+			},
+			wantErr:    true,
+			wantErrMsg: "invalid dependency",
+		},
+		{
+			name: "library depends on itself",
+			config: &LibrarianConfig{
+				Libraries: []*LibraryConfig{
+					{LibraryID: "lib1", Dependencies: []string{"lib1"}},
+				},
+			},
+			wantErr:    true,
+			wantErrMsg: "cannot depend on itself",
+		},
 	} {
 		t.Run(test.name, func(t *testing.T) {
 			err := test.config.Validate()
@@ -103,6 +245,31 @@ func TestGlobalConfig_Validate(t *testing.T) {
 	}
 }
 
+func TestCommonAPISourcePaths(t *testing.T) {
+	for _, test := range []struct {
+		name   string
+		config *LibrarianConfig
+		want   []string
+	}{
+		{
+			name:   "defaults",
+			config: &LibrarianConfig{},
+			want:   DefaultAPISourceCommonPaths,
+		},
+		{
+			name:   "configured",
+			config: &LibrarianConfig{APISourceCommonPaths: []string{"google/api"}},
+			want:   []string{"google/api"},
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			if diff := cmp.Diff(test.want, test.config.CommonAPISourcePaths()); diff != "" {
+				t.Errorf("CommonAPISourcePaths() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
 func TestLibraryConfigFor(t *testing.T) {
 	cases := []struct {
 		name          string
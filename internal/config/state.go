@@ -34,6 +34,10 @@ type LibrarianState struct {
 	Image string `yaml:"image" json:"image"`
 	// A list of library configurations.
 	Libraries []*LibraryState `yaml:"libraries" json:"libraries"`
+	// Additional API definition repositories. APIs refer to them by name, APIs
+	// without a source are in the default API source, googleapis unless
+	// configured otherwise with the `-api-source` flag.
+	APISources []*APISource `yaml:"api_sources,omitempty" json:"api_sources,omitempty"`
 }
 
 // Validate checks that the LibrarianState is valid.
@@ -47,6 +51,19 @@ func (s *LibrarianState) Validate() error {
 	if len(s.Libraries) == 0 {
 		return fmt.Errorf("libraries cannot be empty")
 	}
+	seenSources := make(map[string]bool)
+	for i, source := range s.APISources {
+		if source == nil {
+			return fmt.Errorf("api source at index %d cannot be nil", i)
+		}
+		if err := source.Validate(); err != nil {
+			return fmt.Errorf("invalid api source at index %d: %w", i, err)
+		}
+		if seenSources[source.Name] {
+			return fmt.Errorf("duplicate api source name %s", source.Name)
+		}
+		seenSources[source.Name] = true
+	}
 	seenLibraryIDs := make(map[string]bool)
 	for i, l := range s.Libraries {
 		if l == nil {
@@ -59,6 +76,54 @@ func (s *LibrarianState) Validate() error {
 		if err := l.Validate(); err != nil {
 			return fmt.Errorf("invalid library at index %d: %w", i, err)
 		}
+		for _, a := range l.APIs {
+			if a.Source != "" && !seenSources[a.Source] {
+				return fmt.Errorf("library %s: api %s uses unknown api source %q", l.ID, a.Path, a.Source)
+			}
+		}
+		for name := range l.LastGeneratedCommits {
+			if !seenSources[name] {
+				return fmt.Errorf("library %s: last_generated_commits uses unknown api source %q", l.ID, name)
+			}
+		}
+	}
+	return nil
+}
+
+// APISourceByName returns the API source with the given name, or nil if not
+// found.
+func (s *LibrarianState) APISourceByName(name string) *APISource {
+	for _, source := range s.APISources {
+		if source.Name == name {
+			return source
+		}
+	}
+	return nil
+}
+
+// APISource is an API definition repository, in addition to the default API
+// source.
+type APISource struct {
+	// The name of the source, referenced by the `source` of each API.
+	// A valid name only contains lowercase letters, digits, underscores, and
+	// hyphens.
+	Name string `yaml:"name" json:"name"`
+	// The URL of the repository, or the path to a local clone.
+	URL string `yaml:"url" json:"url"`
+	// The branch to generate from. If not specified, the default branch of
+	// the repository.
+	Branch string `yaml:"branch,omitempty" json:"branch,omitempty"`
+}
+
+var apiSourceNameRegex = regexp.MustCompile(`^[a-z0-9_-]+$`)
+
+// Validate checks that the APISource is valid.
+func (a *APISource) Validate() error {
+	if !apiSourceNameRegex.MatchString(a.Name) {
+		return fmt.Errorf("invalid name: %q", a.Name)
+	}
+	if a.URL == "" {
+		return fmt.Errorf("url is required")
 	}
 	return nil
 }
@@ -111,6 +176,9 @@ type LibraryState struct {
 	Version string `yaml:"version" json:"version"`
 	// The commit hash from the API definition repository at which the library was last generated.
 	LastGeneratedCommit string `yaml:"last_generated_commit" json:"last_generated_commit"`
+	// The commit hash from each additional API source at which the library
+	// was last generated, keyed by the name of the API source.
+	LastGeneratedCommits map[string]string `yaml:"last_generated_commits,omitempty" json:"last_generated_commits,omitempty"`
 	// The changes from the language repository since the library was last released.
 	// This field is ignored when writing to state.yaml.
 	Changes []*conventionalcommits.ConventionalCommit `yaml:"-" json:"changes,omitempty"`
@@ -171,6 +239,11 @@ func (l *LibraryState) Validate() error {
 			return fmt.Errorf("last_generated_commit must be 40 characters")
 		}
 	}
+	for name, commit := range l.LastGeneratedCommits {
+		if !hexRegex.MatchString(commit) || len(commit) != 40 {
+			return fmt.Errorf("last_generated_commits for %s must be a 40 character hex string", name)
+		}
+	}
 	for i, a := range l.APIs {
 		if err := a.Validate(); err != nil {
 			return fmt.Errorf("invalid api at index %d: %w", i, err)
@@ -213,10 +286,47 @@ func (l *LibraryState) Validate() error {
 	return nil
 }
 
+// LastGeneratedCommitFor returns the commit at which the library was last
+// generated from the given API source. An empty source is the default API
+// source.
+func (l *LibraryState) LastGeneratedCommitFor(source string) string {
+	if source == "" {
+		return l.LastGeneratedCommit
+	}
+	return l.LastGeneratedCommits[source]
+}
+
+// SetLastGeneratedCommitFor records the commit at which the library was
+// generated from the given API source.
+func (l *LibraryState) SetLastGeneratedCommitFor(source, commit string) {
+	if source == "" {
+		l.LastGeneratedCommit = commit
+		return
+	}
+	if l.LastGeneratedCommits == nil {
+		l.LastGeneratedCommits = map[string]string{}
+	}
+	l.LastGeneratedCommits[source] = commit
+}
+
+// UsesAPISource reports whether any API in the library is in the given API
+// source.
+func (l *LibraryState) UsesAPISource(source string) bool {
+	for _, a := range l.APIs {
+		if a.Source == source {
+			return true
+		}
+	}
+	return false
+}
+
 // API represents an API that is part of a library.
 type API struct {
 	// The path to the API, relative to the root of the API definition repository (e.g., "google/storage/v1").
 	Path string `yaml:"path" json:"path"`
+	// The name of the API source containing the API. If not specified, the
+	// API is in the default API source.
+	Source string `yaml:"source,omitempty" json:"source,omitempty"`
 	// The name of the service config file, relative to the API `path`.
 	ServiceConfig string `yaml:"service_config" json:"service_config"`
 	// The status of the API, one of "new" or "existing".
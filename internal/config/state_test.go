@@ -89,6 +89,105 @@ func TestLibrarianState_Validate(t *testing.T) {
 			wantErr:    true,
 			wantErrMsg: "duplicate library ID",
 		},
+		{
+			name: "valid api sources",
+			state: &LibrarianState{
+				Image: "gcr.io/test/image:v1.2.3",
+				APISources: []*APISource{
+					{Name: "private", URL: "https://github.com/example/private-apis", Branch: "main"},
+				},
+				Libraries: []*LibraryState{
+					{
+						ID:                   "a/b",
+						SourceRoots:          []string{"src/a"},
+						LastGeneratedCommits: map[string]string{"private": strings.Repeat("a", 40)},
+						APIs: []*API{
+							{Path: "a/b/v1"},
+							{Path: "example/c/v1", Source: "private"},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "invalid api source name",
+			state: &LibrarianState{
+				Image:      "gcr.io/test/image:v1.2.3",
+				APISources: []*APISource{{Name: "Private Sources", URL: "https://github.com/example/private-apis"}},
+				Libraries:  []*LibraryState{{ID: "x", SourceRoots: []string{"src/x"}}},
+			},
+			wantErr:    true,
+			wantErrMsg: "invalid api source at index 0: invalid name",
+		},
+		{
+			name: "missing api source url",
+			state: &LibrarianState{
+				Image:      "gcr.io/test/image:v1.2.3",
+				APISources: []*APISource{{Name: "private"}},
+				Libraries:  []*LibraryState{{ID: "x", SourceRoots: []string{"src/x"}}},
+			},
+			wantErr:    true,
+			wantErrMsg: "url is required",
+		},
+		{
+			name: "duplicate api source",
+			state: &LibrarianState{
+				Image: "gcr.io/test/image:v1.2.3",
+				APISources: []*APISource{
+					{Name: "private", URL: "https://github.com/example/a"},
+					{Name: "private", URL: "https://github.com/example/b"},
+				},
+				Libraries: []*LibraryState{{ID: "x", SourceRoots: []string{"src/x"}}},
+			},
+			wantErr:    true,
+			wantErrMsg: "duplicate api source name",
+		},
+		{
+			name: "unknown api source",
+			state: &LibrarianState{
+				Image: "gcr.io/test/image:v1.2.3",
+				Libraries: []*LibraryState{
+					{
+						ID:          "x",
+						SourceRoots: []string{"src/x"},
+						APIs:        []*API{{Path: "example/c/v1", Source: "private"}},
+					},
+				},
+			},
+			wantErr:    true,
+			wantErrMsg: `uses unknown api source "private"`,
+		},
+		{
+			name: "unknown api source in last generated commits",
+			state: &LibrarianState{
+				Image: "gcr.io/test/image:v1.2.3",
+				Libraries: []*LibraryState{
+					{
+						ID:                   "x",
+						SourceRoots:          []string{"src/x"},
+						LastGeneratedCommits: map[string]string{"private": strings.Repeat("a", 40)},
+					},
+				},
+			},
+			wantErr:    true,
+			wantErrMsg: `last_generated_commits uses unknown api source "private"`,
+		},
+		{
+			name: "invalid last generated commits",
+			state: &LibrarianState{
+				Image:      "gcr.io/test/image:v1.2.3",
+				APISources: []*APISource{{Name: "private", URL: "https://github.com/example/a"}},
+				Libraries: []*LibraryState{
+					{
+						ID:                   "x",
+						SourceRoots:          []string{"src/x"},
+						LastGeneratedCommits: map[string]string{"private": "not-a-hash"},
+					},
+				},
+			},
+			wantErr:    true,
+			wantErrMsg: "last_generated_commits for private must be a 40 character hex string",
+		},
 	} {
 		t.Run(test.name, func(t *testing.T) {
 			err := test.state.Validate()
@@ -463,3 +562,48 @@ func TestLibraryState_LibraryByID(t *testing.T) {
 		})
 	}
 }
+
+func TestLibrarianState_APISourceByName(t *testing.T) {
+	state := &LibrarianState{
+		APISources: []*APISource{
+			{Name: "private", URL: "https://github.com/example/private-apis"},
+		},
+	}
+	if got := state.APISourceByName("private"); got == nil || got.URL != "https://github.com/example/private-apis" {
+		t.Errorf("APISourceByName(private) = %v", got)
+	}
+	if got := state.APISourceByName("missing"); got != nil {
+		t.Errorf("APISourceByName(missing) = %v, want nil", got)
+	}
+}
+
+func TestLibraryState_LastGeneratedCommitFor(t *testing.T) {
+	library := &LibraryState{
+		ID: "foo",
+		APIs: []*API{
+			{Path: "google/foo/v1"},
+			{Path: "example/bar/v1", Source: "private"},
+		},
+	}
+	library.SetLastGeneratedCommitFor("", "default-commit")
+	library.SetLastGeneratedCommitFor("private", "private-commit")
+	want := &LibraryState{
+		ID:                   "foo",
+		APIs:                 library.APIs,
+		LastGeneratedCommit:  "default-commit",
+		LastGeneratedCommits: map[string]string{"private": "private-commit"},
+	}
+	if diff := cmp.Diff(want, library); diff != "" {
+		t.Errorf("mismatch (-want +got):\n%s", diff)
+	}
+	for source, want := range map[string]string{"": "default-commit", "private": "private-commit", "other": ""} {
+		if got := library.LastGeneratedCommitFor(source); got != want {
+			t.Errorf("LastGeneratedCommitFor(%q) = %q, want %q", source, got, want)
+		}
+	}
+	for source, want := range map[string]bool{"": true, "private": true, "other": false} {
+		if got := library.UsesAPISource(source); got != want {
+			t.Errorf("UsesAPISource(%q) = %v, want %v", source, got, want)
+		}
+	}
+}
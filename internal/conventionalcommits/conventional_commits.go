@@ -40,8 +40,10 @@ var (
 	// A footer key consists of letters and hyphens, or is the "BREAKING CHANGE"
 	// literal. The key is followed by ": " and then the value.
 	// e.g., "Reviewed-by: G. Gemini" or "BREAKING CHANGE: an API was changed".
-	footerRegex     = regexp.MustCompile(`^([A-Za-z-]+|` + breakingChangeKey + `):\s(.*)`)
-	sourceLinkRegex = regexp.MustCompile(`^\[googleapis\/googleapis@(?P<shortSHA>.*)\]\(https:\/\/github\.com\/googleapis\/googleapis\/commit\/(?P<sha>.*)\)$`)
+	footerRegex = regexp.MustCompile(`^([A-Za-z-]+|` + breakingChangeKey + `):\s(.*)`)
+	// sourceLinkRegex matches links to a commit in the API definition
+	// repository, e.g. "[googleapis/googleapis@abc1234](https://github.com/googleapis/googleapis/commit/abc1234...)".
+	sourceLinkRegex = regexp.MustCompile(`^\[(?P<repo>[^@\]]+)@(?P<shortSHA>.*)\]\(https:\/\/github\.com\/[^)]+\/commit\/(?P<sha>.*)\)$`)
 )
 
 // ConventionalCommit represents a parsed conventional commit message.
@@ -306,12 +308,12 @@ func parseFooters(footerLines []string) (footers map[string]string, isBreaking b
 func processFooters(footers map[string]string) {
 	for key, value := range footers {
 		if key == sourceLinkKey {
-			// Exact commit sha from the googleapis commit.
+			// Exact commit sha from the API definition repository commit.
 			matches := sourceLinkRegex.FindStringSubmatch(value)
 			if len(matches) == 0 {
 				continue
 			}
-			footers[key] = matches[2]
+			footers[key] = matches[sourceLinkRegex.SubexpIndex("sha")]
 		}
 	}
 }
@@ -134,6 +134,22 @@ func TestParseCommits(t *testing.T) {
 				},
 			},
 		},
+		{
+			name:    "feat with source link to another api source",
+			message: "feat: [library-name] add new feature\n\nSource-Link: [example/private-apis@abcdefg](https://github.com/example/private-apis/commit/abcdefg1234567)",
+			want: []*ConventionalCommit{
+				{
+					Type:      "feat",
+					Subject:   "[library-name] add new feature",
+					LibraryID: "example-id",
+					Footers: map[string]string{
+						"Source-Link": "abcdefg1234567",
+					},
+					SHA:  sha.String(),
+					When: now,
+				},
+			},
+		},
 		{
 			name:    "feat with breaking change footer",
 			message: "feat: add new feature\n\nBREAKING CHANGE: this is a breaking change",
@@ -21,11 +21,14 @@ package docker
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log/slog"
+	"maps"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"slices"
 	"strings"
 
 	"github.com/googleapis/librarian/internal/config"
@@ -41,12 +44,62 @@ const (
 	CommandBuild Command = "build"
 	// CommandConfigure configures a new API as a library.
 	CommandConfigure Command = "configure"
+	// CommandDescribe reports the capabilities of the container.
+	CommandDescribe Command = "describe"
 	// CommandGenerate performs generation for a configured library.
 	CommandGenerate Command = "generate"
+	// CommandPackage packages the release artifacts of a released library.
+	CommandPackage Command = "package"
+	// CommandPublish publishes a released library to its package registry.
+	CommandPublish Command = "publish"
 	// CommandReleaseInit performs release for a library.
 	CommandReleaseInit Command = "release-init"
 )
 
+// ContractVersion is the version of the container contract implemented by
+// Librarian. Containers must implement the same version.
+const ContractVersion = 1
+
+// Capabilities describes the contract version and commands implemented by a
+// language container, as reported by the describe command.
+type Capabilities struct {
+	// ContractVersion is the version of the container contract implemented by
+	// the container.
+	ContractVersion int `json:"contract_version"`
+
+	// Commands is the list of commands implemented by the container.
+	Commands []Command `json:"commands"`
+}
+
+// LegacyCapabilities returns the capabilities assumed for containers which
+// do not implement the describe command.
+func LegacyCapabilities() *Capabilities {
+	return &Capabilities{
+		ContractVersion: ContractVersion,
+		Commands: []Command{
+			CommandBuild,
+			CommandConfigure,
+			CommandGenerate,
+			CommandReleaseInit,
+		},
+	}
+}
+
+// Supports reports whether the container implements the given command.
+func (c *Capabilities) Supports(command Command) bool {
+	return slices.Contains(c.Commands, command)
+}
+
+// Validate checks that the capabilities are compatible with this version of
+// Librarian.
+func (c *Capabilities) Validate() error {
+	if c.ContractVersion != ContractVersion {
+		return fmt.Errorf("container implements contract version %d, but librarian implements version %d",
+			c.ContractVersion, ContractVersion)
+	}
+	return nil
+}
+
 // Docker contains all the information required to run language-specific
 // Docker containers.
 type Docker struct {
@@ -102,12 +155,28 @@ type ConfigureRequest struct {
 	State *config.LibrarianState
 }
 
+// DescribeRequest contains all the information required for a language
+// container to run the describe command.
+type DescribeRequest struct {
+	// HostMount specifies a mount point from the Docker host into the Docker
+	// container. The format is "{host-dir}:{local-dir}".
+	HostMount string
+
+	// RepoDir is the local root directory of the language repository.
+	RepoDir string
+}
+
 // GenerateRequest contains all the information required for a language
 // container to run the generate command.
 type GenerateRequest struct {
 	// ApiRoot specifies the root directory of the API specification repo.
 	ApiRoot string
 
+	// APISources maps the name of each additional API source to its root
+	// directory. Each source is mounted in the container as
+	// `/sources/{name}`.
+	APISources map[string]string
+
 	// HostMount specifies a mount point from the Docker host into the Docker
 	// container. The format is "{host-dir}:{local-dir}".
 	HostMount string
@@ -127,6 +196,58 @@ type GenerateRequest struct {
 	State *config.LibrarianState
 }
 
+// PackageRequest contains all the information required for a language
+// container to run the package command.
+type PackageRequest struct {
+	// HostMount specifies a mount point from the Docker host into the Docker
+	// container. The format is "{host-dir}:{local-dir}".
+	HostMount string
+
+	// LibraryID specifies the ID of the library to package.
+	LibraryID string
+
+	// Output specifies the empty output directory into which the command should
+	// write the release artifacts.
+	Output string
+
+	// RepoDir is the local root directory of the language repository, checked
+	// out at the released commit.
+	RepoDir string
+
+	// State is a pointer to the [config.LibrarianState] struct, representing
+	// the overall state of the generation and release pipeline.
+	State *config.LibrarianState
+}
+
+// PublishRequest contains all the information required for a language
+// container to run the publish command.
+type PublishRequest struct {
+	// DryRun determines whether the container should only check that the
+	// library can be published, without publishing it.
+	DryRun bool
+
+	// HostMount specifies a mount point from the Docker host into the Docker
+	// container. The format is "{host-dir}:{local-dir}".
+	HostMount string
+
+	// LibraryID specifies the ID of the library to publish.
+	LibraryID string
+
+	// RepoDir is the local root directory of the language repository, checked
+	// out at the released commit.
+	RepoDir string
+
+	// State is a pointer to the [config.LibrarianState] struct, representing
+	// the overall state of the generation and release pipeline.
+	State *config.LibrarianState
+
+	// Tag is the name of the tag of the release.
+	Tag string
+
+	// Version is the released version of the library.
+	Version string
+}
+
 // ReleaseInitRequest contains all the information required for a language
 // container to run the  init command.
 type ReleaseInitRequest struct {
@@ -214,6 +335,9 @@ func (c *Docker) Generate(ctx context.Context, request *GenerateRequest) error {
 		fmt.Sprintf("%s:/output", request.Output),
 		fmt.Sprintf("%s:/source:ro", request.ApiRoot), // readonly volume
 	}
+	for _, name := range slices.Sorted(maps.Keys(request.APISources)) {
+		mounts = append(mounts, fmt.Sprintf("%s:/sources/%s:ro", request.APISources[name], name))
+	}
 	return c.runDocker(ctx, request.HostMount, CommandGenerate, mounts, commandArgs)
 }
 
@@ -281,6 +405,114 @@ func (c *Docker) Configure(ctx context.Context, request *ConfigureRequest) (stri
 	return request.LibraryID, nil
 }
 
+// Describe asks the container for its capabilities.
+//
+// Containers which do not implement the describe command are assumed to
+// implement the original contract, as returned by [LegacyCapabilities]. Such
+// containers either reject the unknown command by exiting with a non-zero
+// code, or exit successfully without writing a response. Any other failure,
+// such as Docker failing to run the container, is returned as an error, as is
+// a response which is not compatible with this version of Librarian.
+func (c *Docker) Describe(ctx context.Context, request *DescribeRequest) (*Capabilities, error) {
+	librarianDir := filepath.Join(request.RepoDir, config.LibrarianDir)
+	responseFilePath := filepath.Join(librarianDir, config.DescribeResponse)
+	defer func() {
+		if err := os.Remove(responseFilePath); err != nil && !os.IsNotExist(err) {
+			slog.Warn("fail to remove file", slog.String("name", responseFilePath), slog.Any("err", err))
+		}
+	}()
+	mounts := []string{
+		fmt.Sprintf("%s:/librarian", librarianDir),
+	}
+	commandArgs := []string{
+		"--librarian=/librarian",
+	}
+	if err := c.runDocker(ctx, request.HostMount, CommandDescribe, mounts, commandArgs); err != nil {
+		if !isContainerExitError(err) {
+			return nil, fmt.Errorf("failed to run describe command: %w", err)
+		}
+		slog.Warn("describe command rejected, assuming legacy container capabilities", "image", c.Image, "err", err)
+		return LegacyCapabilities(), nil
+	}
+
+	data, err := os.ReadFile(responseFilePath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			slog.Warn("no describe response, assuming legacy container capabilities", "image", c.Image)
+			return LegacyCapabilities(), nil
+		}
+		return nil, fmt.Errorf("failed to read describe response: %w", err)
+	}
+	capabilities := &Capabilities{}
+	if err := json.Unmarshal(data, capabilities); err != nil {
+		return nil, fmt.Errorf("failed to parse describe response: %w", err)
+	}
+	if err := capabilities.Validate(); err != nil {
+		return nil, fmt.Errorf("image %s is not compatible with this version of librarian: %w", c.Image, err)
+	}
+	return capabilities, nil
+}
+
+// Package packages the release artifacts of a released library into the
+// output directory of the request.
+func (c *Docker) Package(ctx context.Context, request *PackageRequest) error {
+	jsonFilePath := filepath.Join(request.RepoDir, config.LibrarianDir, config.PackageRequest)
+	if err := writeLibraryState(request.State, request.LibraryID, jsonFilePath); err != nil {
+		return err
+	}
+	defer func(name string) {
+		err := os.Remove(name)
+		if err != nil {
+			slog.Warn("fail to remove file", slog.String("name", name), slog.Any("err", err))
+		}
+	}(jsonFilePath)
+
+	librarianDir := filepath.Join(request.RepoDir, config.LibrarianDir)
+	mounts := []string{
+		fmt.Sprintf("%s:/librarian", librarianDir),
+		fmt.Sprintf("%s:/repo:ro", request.RepoDir), // readonly volume
+		fmt.Sprintf("%s:/output", request.Output),
+	}
+	commandArgs := []string{
+		"--librarian=/librarian",
+		"--repo=/repo",
+		"--output=/output",
+	}
+
+	return c.runDocker(ctx, request.HostMount, CommandPackage, mounts, commandArgs)
+}
+
+// Publish publishes a released library to its package registry.
+func (c *Docker) Publish(ctx context.Context, request *PublishRequest) error {
+	jsonFilePath := filepath.Join(request.RepoDir, config.LibrarianDir, config.PublishRequest)
+	if err := writeLibraryState(request.State, request.LibraryID, jsonFilePath); err != nil {
+		return err
+	}
+	defer func(name string) {
+		err := os.Remove(name)
+		if err != nil {
+			slog.Warn("fail to remove file", slog.String("name", name), slog.Any("err", err))
+		}
+	}(jsonFilePath)
+
+	librarianDir := filepath.Join(request.RepoDir, config.LibrarianDir)
+	mounts := []string{
+		fmt.Sprintf("%s:/librarian", librarianDir),
+		fmt.Sprintf("%s:/repo:ro", request.RepoDir), // readonly volume
+	}
+	commandArgs := []string{
+		"--librarian=/librarian",
+		"--repo=/repo",
+		fmt.Sprintf("--tag=%s", request.Tag),
+		fmt.Sprintf("--version=%s", request.Version),
+	}
+	if request.DryRun {
+		commandArgs = append(commandArgs, "--dry-run")
+	}
+
+	return c.runDocker(ctx, request.HostMount, CommandPublish, mounts, commandArgs)
+}
+
 // ReleaseInit initiates a release for a given language repository.
 func (c *Docker) ReleaseInit(ctx context.Context, request *ReleaseInitRequest) error {
 	requestFilePath := filepath.Join(request.PartialRepoDir, config.LibrarianDir, config.ReleaseInitRequest)
@@ -335,6 +567,19 @@ func (c *Docker) runDocker(_ context.Context, hostMount string, command Command,
 	return c.run(args...)
 }
 
+// isContainerExitError reports whether err was caused by the command run in
+// the container exiting with a non-zero code, as opposed to Docker failing to
+// run it. The exit codes 125 to 127 are reserved by docker run for its own
+// failures.
+func isContainerExitError(err error) bool {
+	var exitErr *exec.ExitError
+	if !errors.As(err, &exitErr) {
+		return false
+	}
+	code := exitErr.ExitCode()
+	return code > 0 && (code < 125 || code > 127)
+}
+
 func maybeRelocateMounts(hostMount string, mounts []string) []string {
 	// When running in Kokoro, we'll be running sibling containers.
 	// Make sure we specify the "from" part of the mount as the host directory.
@@ -19,6 +19,7 @@ import (
 	"errors"
 	"fmt"
 	"os"
+	"os/exec"
 	"path/filepath"
 	"strings"
 	"testing"
@@ -103,6 +104,42 @@ func TestDockerRun(t *testing.T) {
 				"--source=/source",
 			},
 		},
+		{
+			name: "Generate with api sources",
+			docker: &Docker{
+				Image: testImage,
+			},
+			runCommand: func(ctx context.Context, d *Docker) error {
+				generateRequest := &GenerateRequest{
+					State:     state,
+					RepoDir:   repoDir,
+					ApiRoot:   testAPIRoot,
+					Output:    testOutput,
+					LibraryID: testLibraryID,
+					APISources: map[string]string{
+						"private": "privateAPIRoot",
+						"extra":   "extraAPIRoot",
+					},
+				}
+
+				return d.Generate(ctx, generateRequest)
+			},
+			want: []string{
+				"run", "--rm",
+				"-v", fmt.Sprintf("%s/.librarian:/librarian", repoDir),
+				"-v", fmt.Sprintf("%s/.librarian/generator-input:/input", repoDir),
+				"-v", fmt.Sprintf("%s:/output", testOutput),
+				"-v", fmt.Sprintf("%s:/source:ro", testAPIRoot),
+				"-v", "extraAPIRoot:/sources/extra:ro",
+				"-v", "privateAPIRoot:/sources/private:ro",
+				testImage,
+				string(CommandGenerate),
+				"--librarian=/librarian",
+				"--input=/input",
+				"--output=/output",
+				"--source=/source",
+			},
+		},
 		{
 			name: "Generate with invalid repo root",
 			docker: &Docker{
@@ -197,6 +234,123 @@ func TestDockerRun(t *testing.T) {
 				"--repo=/repo",
 			},
 		},
+		{
+			name: "Package",
+			docker: &Docker{
+				Image: testImage,
+			},
+			runCommand: func(ctx context.Context, d *Docker) error {
+				packageRequest := &PackageRequest{
+					State:     state,
+					LibraryID: testLibraryID,
+					Output:    testOutput,
+					RepoDir:   repoDir,
+				}
+				return d.Package(ctx, packageRequest)
+			},
+			want: []string{
+				"run", "--rm",
+				"-v", fmt.Sprintf("%s/.librarian:/librarian", repoDir),
+				"-v", fmt.Sprintf("%s:/repo:ro", repoDir),
+				"-v", fmt.Sprintf("%s:/output", testOutput),
+				testImage,
+				string(CommandPackage),
+				"--librarian=/librarian",
+				"--repo=/repo",
+				"--output=/output",
+			},
+		},
+		{
+			name: "Package with invalid repo dir",
+			docker: &Docker{
+				Image: testImage,
+			},
+			runCommand: func(ctx context.Context, d *Docker) error {
+				packageRequest := &PackageRequest{
+					State:     state,
+					LibraryID: testLibraryID,
+					Output:    testOutput,
+					RepoDir:   "/non-exist-dir",
+				}
+				return d.Package(ctx, packageRequest)
+			},
+			want:       []string{},
+			wantErr:    true,
+			wantErrMsg: "failed to make directory",
+		},
+		{
+			name: "Publish",
+			docker: &Docker{
+				Image: testImage,
+			},
+			runCommand: func(ctx context.Context, d *Docker) error {
+				publishRequest := &PublishRequest{
+					State:     state,
+					LibraryID: testLibraryID,
+					RepoDir:   repoDir,
+					Tag:       "v1.2.3",
+					Version:   "1.2.3",
+				}
+				return d.Publish(ctx, publishRequest)
+			},
+			want: []string{
+				"run", "--rm",
+				"-v", fmt.Sprintf("%s/.librarian:/librarian", repoDir),
+				"-v", fmt.Sprintf("%s:/repo:ro", repoDir),
+				testImage,
+				string(CommandPublish),
+				"--librarian=/librarian",
+				"--repo=/repo",
+				"--tag=v1.2.3",
+				"--version=1.2.3",
+			},
+		},
+		{
+			name: "Publish dry run",
+			docker: &Docker{
+				Image: testImage,
+			},
+			runCommand: func(ctx context.Context, d *Docker) error {
+				publishRequest := &PublishRequest{
+					DryRun:    true,
+					State:     state,
+					LibraryID: testLibraryID,
+					RepoDir:   repoDir,
+					Tag:       "v1.2.3",
+					Version:   "1.2.3",
+				}
+				return d.Publish(ctx, publishRequest)
+			},
+			want: []string{
+				"run", "--rm",
+				"-v", fmt.Sprintf("%s/.librarian:/librarian", repoDir),
+				"-v", fmt.Sprintf("%s:/repo:ro", repoDir),
+				testImage,
+				string(CommandPublish),
+				"--librarian=/librarian",
+				"--repo=/repo",
+				"--tag=v1.2.3",
+				"--version=1.2.3",
+				"--dry-run",
+			},
+		},
+		{
+			name: "Describe",
+			docker: &Docker{
+				Image: testImage,
+			},
+			runCommand: func(ctx context.Context, d *Docker) error {
+				_, err := d.Describe(ctx, &DescribeRequest{RepoDir: repoDir})
+				return err
+			},
+			want: []string{
+				"run", "--rm",
+				"-v", fmt.Sprintf("%s/.librarian:/librarian", repoDir),
+				testImage,
+				string(CommandDescribe),
+				"--librarian=/librarian",
+			},
+		},
 		{
 			name: "Build with invalid repo dir",
 			docker: &Docker{
@@ -868,3 +1022,125 @@ func TestReleaseInitRequestContent(t *testing.T) {
 		t.Fatalf("d.ReleaseInit() failed: %v", err)
 	}
 }
+
+func TestDescribe(t *testing.T) {
+	t.Parallel()
+	for _, test := range []struct {
+		name       string
+		response   string
+		dockerErr  error
+		want       *Capabilities
+		wantErrMsg string
+	}{
+		{
+			name:     "reported capabilities",
+			response: `{"contract_version": 1, "commands": ["generate", "configure"]}`,
+			want: &Capabilities{
+				ContractVersion: 1,
+				Commands:        []Command{CommandGenerate, CommandConfigure},
+			},
+		},
+		{
+			name: "no response",
+			want: LegacyCapabilities(),
+		},
+		{
+			name:      "command rejected by container",
+			dockerErr: exitError(t, 2),
+			want:      LegacyCapabilities(),
+		},
+		{
+			name:       "docker fails to run container",
+			dockerErr:  exitError(t, 125),
+			wantErrMsg: "failed to run describe command",
+		},
+		{
+			name:       "docker not found",
+			dockerErr:  exec.ErrNotFound,
+			wantErrMsg: "failed to run describe command",
+		},
+		{
+			name:       "invalid response",
+			response:   `{"contract_version": `,
+			wantErrMsg: "failed to parse describe response",
+		},
+		{
+			name:       "unsupported contract version",
+			response:   `{"contract_version": 99, "commands": ["generate"]}`,
+			wantErrMsg: "not compatible with this version of librarian",
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			repoDir := t.TempDir()
+			responsePath := filepath.Join(repoDir, config.LibrarianDir, config.DescribeResponse)
+			d := &Docker{
+				Image: "testImage",
+				run: func(args ...string) error {
+					if test.dockerErr != nil {
+						return test.dockerErr
+					}
+					if test.response == "" {
+						return nil
+					}
+					if err := os.MkdirAll(filepath.Dir(responsePath), 0755); err != nil {
+						return err
+					}
+					return os.WriteFile(responsePath, []byte(test.response), 0644)
+				},
+			}
+			got, err := d.Describe(t.Context(), &DescribeRequest{RepoDir: repoDir})
+			if test.wantErrMsg != "" {
+				if err == nil {
+					t.Fatalf("Describe() should return error")
+				}
+				if !strings.Contains(err.Error(), test.wantErrMsg) {
+					t.Errorf("want error message: %s, got: %s", test.wantErrMsg, err.Error())
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(test.want, got); diff != "" {
+				t.Errorf("Describe() mismatch (-want +got):\n%s", diff)
+			}
+			if _, err := os.Stat(responsePath); !os.IsNotExist(err) {
+				t.Errorf("describe response %s should be removed, got err: %v", responsePath, err)
+			}
+		})
+	}
+}
+
+// exitError returns the error of a command exiting with the given code.
+func exitError(t *testing.T, code int) error {
+	t.Helper()
+	err := exec.Command("sh", "-c", fmt.Sprintf("exit %d", code)).Run()
+	var exitErr *exec.ExitError
+	if !errors.As(err, &exitErr) {
+		t.Fatalf("expected exit error, got %v", err)
+	}
+	return err
+}
+
+func TestCapabilitiesSupports(t *testing.T) {
+	t.Parallel()
+	capabilities := &Capabilities{
+		ContractVersion: 1,
+		Commands:        []Command{CommandGenerate},
+	}
+	for _, test := range []struct {
+		command Command
+		want    bool
+	}{
+		{command: CommandGenerate, want: true},
+		{command: CommandBuild, want: false},
+		{command: CommandReleaseInit, want: false},
+	} {
+		t.Run(string(test.command), func(t *testing.T) {
+			if got := capabilities.Supports(test.command); got != test.want {
+				t.Errorf("Supports(%q) = %v, want %v", test.command, got, test.want)
+			}
+		})
+	}
+}
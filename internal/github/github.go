@@ -18,10 +18,14 @@ package github
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"log/slog"
 	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
 	"strings"
 
 	"github.com/google/go-github/v69/github"
@@ -264,7 +268,8 @@ func (c *Client) GetPullRequest(ctx context.Context, number int) (*PullRequest,
 // CreateRelease creates a tag and release in the repository at the given
 // commit-ish. See
 // https://git-scm.com/docs/gitglossary#Documentation/gitglossary.txt-commit-ishalsocommittish
-// for definition of commit-ish.
+// for definition of commit-ish. If a release already exists for the tag at
+// the same commit, for example from a previous run, it is returned instead.
 func (c *Client) CreateRelease(ctx context.Context, tagName, name, body, commitish string) (*github.RepositoryRelease, error) {
 	r, _, err := c.Repositories.CreateRelease(ctx, c.repo.Owner, c.repo.Name, &github.RepositoryRelease{
 		TagName:         &tagName,
@@ -272,7 +277,106 @@ func (c *Client) CreateRelease(ctx context.Context, tagName, name, body, commiti
 		Body:            &body,
 		TargetCommitish: &commitish,
 	})
-	return r, err
+	if err == nil || !isUnprocessable(err) {
+		return r, err
+	}
+	// The release may have been created by a previous run.
+	existing, _, getErr := c.Repositories.GetReleaseByTag(ctx, c.repo.Owner, c.repo.Name, tagName)
+	if getErr != nil {
+		return nil, err
+	}
+	if existing.GetTargetCommitish() != commitish {
+		// The target of a release is only informative once its tag exists,
+		// so check the commit of the tag too.
+		ref, _, getErr := c.Git.GetRef(ctx, c.repo.Owner, c.repo.Name, "tags/"+tagName)
+		if getErr != nil || ref.GetObject().GetSHA() != commitish {
+			return nil, fmt.Errorf("release %s already exists for a different commit than %s: %w", tagName, commitish, err)
+		}
+	}
+	slog.Info("Release already exists", "tag", tagName, "id", existing.GetID())
+	return existing, nil
+}
+
+// isUnprocessable reports whether err is a GitHub API error with status 422,
+// which is returned when creating a resource that already exists.
+func isUnprocessable(err error) bool {
+	var errResponse *github.ErrorResponse
+	return errors.As(err, &errResponse) && errResponse.Response != nil &&
+		errResponse.Response.StatusCode == http.StatusUnprocessableEntity
+}
+
+// UploadURL returns the URL of the GitHub uploads API for the GitHub API
+// endpoint. For github.com the uploads API has its own host, and for GitHub
+// Enterprise Server it is served under /api/uploads/. Any other endpoint,
+// such as a test server, is assumed to serve both APIs.
+func UploadURL(endpoint *url.URL) *url.URL {
+	upload := *endpoint
+	switch {
+	case upload.Host == "api.github.com":
+		upload.Host = "uploads.github.com"
+	case strings.HasSuffix(upload.Path, "/api/v3/"):
+		upload.Path = strings.TrimSuffix(upload.Path, "v3/") + "uploads/"
+	}
+	return &upload
+}
+
+// UploadReleaseAsset uploads the file at path as an asset of the release with
+// the given ID. The asset is named after the base name of the file, and
+// replaces any existing asset with the same name.
+func (c *Client) UploadReleaseAsset(ctx context.Context, releaseID int64, path string) error {
+	name := filepath.Base(path)
+	slog.Info("Uploading release asset", "release", releaseID, "name", name)
+	err := c.uploadReleaseAsset(ctx, releaseID, path)
+	if err == nil || !isUnprocessable(err) {
+		return err
+	}
+	// The asset may have been uploaded by a previous run, and is replaced as
+	// the artifacts may have been packaged again.
+	deleted, deleteErr := c.deleteReleaseAsset(ctx, releaseID, name)
+	if deleteErr != nil || !deleted {
+		return err
+	}
+	slog.Info("Replacing release asset", "release", releaseID, "name", name)
+	return c.uploadReleaseAsset(ctx, releaseID, path)
+}
+
+// uploadReleaseAsset uploads the file at path as an asset of the release.
+// The file is opened for each upload, as the request closes it.
+func (c *Client) uploadReleaseAsset(ctx context.Context, releaseID int64, path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	_, _, err = c.Repositories.UploadReleaseAsset(ctx, c.repo.Owner, c.repo.Name, releaseID, &github.UploadOptions{
+		Name: filepath.Base(path),
+	}, f)
+	return err
+}
+
+// deleteReleaseAsset deletes the asset of the release with the given name, and
+// reports whether it existed.
+func (c *Client) deleteReleaseAsset(ctx context.Context, releaseID int64, name string) (bool, error) {
+	opt := &github.ListOptions{PerPage: 100}
+	for {
+		assets, resp, err := c.Repositories.ListReleaseAssets(ctx, c.repo.Owner, c.repo.Name, releaseID, opt)
+		if err != nil {
+			return false, err
+		}
+		for _, asset := range assets {
+			if asset.GetName() != name {
+				continue
+			}
+			if _, err := c.Repositories.DeleteReleaseAsset(ctx, c.repo.Owner, c.repo.Name, asset.GetID()); err != nil {
+				return false, err
+			}
+			return true, nil
+		}
+		if resp.NextPage == 0 {
+			return false, nil
+		}
+		opt.Page = resp.NextPage
+	}
 }
 
 // CreateIssueComment adds a comment to the issue number provided.
@@ -321,7 +425,8 @@ func (c *Client) FindMergedPullRequestsWithPendingReleaseLabel(ctx context.Conte
 }
 
 // CreateTag creates a lightweight tag in the repository at the given commit SHA.
-// This does NOT create a release, just the tag.
+// This does NOT create a release, just the tag. Creating a tag that already
+// exists at the same commit succeeds.
 func (c *Client) CreateTag(ctx context.Context, tagName, commitSHA string) error {
 	slog.Info("Creating tag", "tag", tagName, "commit", commitSHA)
 	ref := "refs/tags/" + tagName
@@ -330,7 +435,16 @@ func (c *Client) CreateTag(ctx context.Context, tagName, commitSHA string) error
 		Object: &github.GitObject{SHA: github.Ptr(commitSHA), Type: github.Ptr("commit")},
 	}
 	_, _, err := c.Git.CreateRef(ctx, c.repo.Owner, c.repo.Name, tagRef)
-	return err
+	if err == nil || !isUnprocessable(err) {
+		return err
+	}
+	// The tag may have been created by a previous run.
+	existing, _, getErr := c.Git.GetRef(ctx, c.repo.Owner, c.repo.Name, "tags/"+tagName)
+	if getErr != nil || existing.GetObject().GetSHA() != commitSHA {
+		return err
+	}
+	slog.Info("Tag already exists", "tag", tagName, "commit", commitSHA)
+	return nil
 }
 
 // ClosePullRequest closes the pull request specified by pull request number.
@@ -17,9 +17,12 @@ package github
 import (
 	"encoding/json"
 	"fmt"
+	"io"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 	"time"
@@ -884,6 +887,81 @@ func TestCreateRelease(t *testing.T) {
 			},
 			wantRelease: &github.RepositoryRelease{TagName: github.Ptr("v1.0.0"), Name: github.Ptr("Version 1.0.0")},
 		},
+		{
+			name:        "Already exists",
+			tagName:     "v1.0.0",
+			releaseName: "Version 1.0.0",
+			body:        "Initial release",
+			commitish:   "main",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				if r.Method == http.MethodPost {
+					w.WriteHeader(http.StatusUnprocessableEntity)
+					fmt.Fprint(w, `{"message": "Validation Failed", "errors": [{"resource": "Release", "code": "already_exists", "field": "tag_name"}]}`)
+					return
+				}
+				wantPath := "/repos/owner/repo/releases/tags/v1.0.0"
+				if r.URL.Path != wantPath {
+					t.Errorf("unexpected path: got %s, want %s", r.URL.Path, wantPath)
+				}
+				fmt.Fprint(w, `{"id": 42, "tag_name": "v1.0.0", "target_commitish": "main"}`)
+			},
+			wantRelease: &github.RepositoryRelease{ID: github.Ptr(int64(42)), TagName: github.Ptr("v1.0.0"), TargetCommitish: github.Ptr("main")},
+		},
+		{
+			name:        "Already exists with tag at commit",
+			tagName:     "v1.0.0",
+			releaseName: "Version 1.0.0",
+			body:        "Initial release",
+			commitish:   "abcdef",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				switch {
+				case r.Method == http.MethodPost:
+					w.WriteHeader(http.StatusUnprocessableEntity)
+				case r.URL.Path == "/repos/owner/repo/releases/tags/v1.0.0":
+					fmt.Fprint(w, `{"id": 42, "tag_name": "v1.0.0", "target_commitish": "main"}`)
+				case r.URL.Path == "/repos/owner/repo/git/ref/tags/v1.0.0":
+					fmt.Fprint(w, `{"ref": "refs/tags/v1.0.0", "object": {"sha": "abcdef", "type": "commit"}}`)
+				default:
+					t.Errorf("unexpected request: %s %s", r.Method, r.URL.Path)
+				}
+			},
+			wantRelease: &github.RepositoryRelease{ID: github.Ptr(int64(42)), TagName: github.Ptr("v1.0.0"), TargetCommitish: github.Ptr("main")},
+		},
+		{
+			name:        "Already exists at other commit",
+			tagName:     "v1.0.0",
+			releaseName: "Version 1.0.0",
+			body:        "Initial release",
+			commitish:   "abcdef",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				switch {
+				case r.Method == http.MethodPost:
+					w.WriteHeader(http.StatusUnprocessableEntity)
+				case r.URL.Path == "/repos/owner/repo/releases/tags/v1.0.0":
+					fmt.Fprint(w, `{"id": 42, "tag_name": "v1.0.0", "target_commitish": "0123456"}`)
+				default:
+					fmt.Fprint(w, `{"ref": "refs/tags/v1.0.0", "object": {"sha": "0123456", "type": "commit"}}`)
+				}
+			},
+			wantErr:       true,
+			wantErrSubstr: "already exists for a different commit",
+		},
+		{
+			name:        "Already exists lookup error",
+			tagName:     "v1.0.0",
+			releaseName: "Version 1.0.0",
+			body:        "Initial release",
+			commitish:   "main",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				if r.Method == http.MethodPost {
+					w.WriteHeader(http.StatusUnprocessableEntity)
+					return
+				}
+				w.WriteHeader(http.StatusNotFound)
+			},
+			wantErr:       true,
+			wantErrSubstr: "422",
+		},
 		{
 			name:          "API Error",
 			tagName:       "v1.0.0",
@@ -924,6 +1002,116 @@ func TestCreateRelease(t *testing.T) {
 	}
 }
 
+func TestUploadReleaseAsset(t *testing.T) {
+	t.Parallel()
+	assetPath := filepath.Join(t.TempDir(), "library-1.0.0.tar.gz")
+	if err := os.WriteFile(assetPath, []byte("archive"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	for _, test := range []struct {
+		name          string
+		path          string
+		handler       http.HandlerFunc
+		wantErr       bool
+		wantErrSubstr string
+	}{
+		{
+			name: "Success",
+			path: assetPath,
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				if r.Method != http.MethodPost {
+					t.Errorf("unexpected method: got %s, want %s", r.Method, http.MethodPost)
+				}
+				wantPath := "/repos/owner/repo/releases/42/assets"
+				if r.URL.Path != wantPath {
+					t.Errorf("unexpected path: got %s, want %s", r.URL.Path, wantPath)
+				}
+				if got, want := r.URL.Query().Get("name"), "library-1.0.0.tar.gz"; got != want {
+					t.Errorf("unexpected asset name: got %q, want %q", got, want)
+				}
+				body, err := io.ReadAll(r.Body)
+				if err != nil {
+					t.Fatalf("failed to read request body: %v", err)
+				}
+				if string(body) != "archive" {
+					t.Errorf("unexpected asset content: got %q, want %q", body, "archive")
+				}
+				fmt.Fprint(w, `{"id": 1, "name": "library-1.0.0.tar.gz"}`)
+			},
+		},
+		{
+			name: "Replaces existing asset",
+			path: assetPath,
+			handler: func() http.HandlerFunc {
+				uploads := 0
+				return func(w http.ResponseWriter, r *http.Request) {
+					switch {
+					case r.Method == http.MethodPost && r.URL.Path == "/repos/owner/repo/releases/42/assets":
+						uploads++
+						if uploads == 1 {
+							w.WriteHeader(http.StatusUnprocessableEntity)
+							fmt.Fprint(w, `{"message": "Validation Failed", "errors": [{"resource": "ReleaseAsset", "code": "already_exists", "field": "name"}]}`)
+							return
+						}
+						body, err := io.ReadAll(r.Body)
+						if err != nil {
+							t.Fatalf("failed to read request body: %v", err)
+						}
+						if string(body) != "archive" {
+							t.Errorf("unexpected asset content: got %q, want %q", body, "archive")
+						}
+						fmt.Fprint(w, `{"id": 2, "name": "library-1.0.0.tar.gz"}`)
+					case r.Method == http.MethodGet && r.URL.Path == "/repos/owner/repo/releases/42/assets":
+						fmt.Fprint(w, `[{"id": 1, "name": "library-1.0.0.tar.gz"}]`)
+					case r.Method == http.MethodDelete && r.URL.Path == "/repos/owner/repo/releases/assets/1":
+						w.WriteHeader(http.StatusNoContent)
+					default:
+						t.Errorf("unexpected request: %s %s", r.Method, r.URL.Path)
+					}
+				}
+			}(),
+		},
+		{
+			name:          "Missing file",
+			path:          filepath.Join(t.TempDir(), "missing.tar.gz"),
+			handler:       func(w http.ResponseWriter, r *http.Request) { t.Error("unexpected request") },
+			wantErr:       true,
+			wantErrSubstr: "no such file",
+		},
+		{
+			name:          "API Error",
+			path:          assetPath,
+			handler:       func(w http.ResponseWriter, r *http.Request) { w.WriteHeader(http.StatusInternalServerError) },
+			wantErr:       true,
+			wantErrSubstr: "500",
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			server := httptest.NewServer(test.handler)
+			defer server.Close()
+
+			repo := &Repository{Owner: "owner", Name: "repo"}
+			client := newClientWithHTTP("fake-token", repo, server.Client())
+			client.BaseURL, _ = url.Parse(server.URL + "/")
+			client.UploadURL, _ = url.Parse(server.URL + "/")
+
+			err := client.UploadReleaseAsset(t.Context(), 42, test.path)
+
+			if test.wantErr {
+				if err == nil {
+					t.Fatalf("UploadReleaseAsset() err = nil, want error containing %q", test.wantErrSubstr)
+				}
+				if !strings.Contains(err.Error(), test.wantErrSubstr) {
+					t.Errorf("UploadReleaseAsset() err = %v, want error containing %q", err, test.wantErrSubstr)
+				}
+			} else if err != nil {
+				t.Errorf("UploadReleaseAsset() err = %v, want nil", err)
+			}
+		})
+	}
+}
+
 func TestCreateIssueComment(t *testing.T) {
 	t.Parallel()
 	for _, test := range []struct {
@@ -1096,6 +1284,37 @@ func TestCreateTag(t *testing.T) {
 			handler:   func(w http.ResponseWriter, r *http.Request) { w.WriteHeader(http.StatusInternalServerError) },
 			wantErr:   true,
 		},
+		{
+			name:      "Already exists at commit",
+			tagName:   "v1.2.3",
+			commitSHA: "abcdef123456",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				if r.Method == http.MethodPost {
+					w.WriteHeader(http.StatusUnprocessableEntity)
+					fmt.Fprint(w, `{"message": "Reference already exists"}`)
+					return
+				}
+				wantPath := "/repos/owner/repo/git/ref/tags/v1.2.3"
+				if r.URL.Path != wantPath {
+					t.Errorf("unexpected path: got %s, want %s", r.URL.Path, wantPath)
+				}
+				fmt.Fprint(w, `{"ref": "refs/tags/v1.2.3", "object": {"sha": "abcdef123456", "type": "commit"}}`)
+			},
+		},
+		{
+			name:      "Already exists at other commit",
+			tagName:   "v1.2.3",
+			commitSHA: "abcdef123456",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				if r.Method == http.MethodPost {
+					w.WriteHeader(http.StatusUnprocessableEntity)
+					fmt.Fprint(w, `{"message": "Reference already exists"}`)
+					return
+				}
+				fmt.Fprint(w, `{"ref": "refs/tags/v1.2.3", "object": {"sha": "0123456789ab", "type": "commit"}}`)
+			},
+			wantErr: true,
+		},
 	} {
 		t.Run(test.name, func(t *testing.T) {
 			t.Parallel()
@@ -1118,3 +1337,42 @@ func TestCreateTag(t *testing.T) {
 		})
 	}
 }
+
+func TestUploadURL(t *testing.T) {
+	t.Parallel()
+	for _, test := range []struct {
+		name     string
+		endpoint string
+		want     string
+	}{
+		{
+			name:     "github.com",
+			endpoint: "https://api.github.com/",
+			want:     "https://uploads.github.com/",
+		},
+		{
+			name:     "GitHub Enterprise Server",
+			endpoint: "https://github.example.com/api/v3/",
+			want:     "https://github.example.com/api/uploads/",
+		},
+		{
+			name:     "test server",
+			endpoint: "http://127.0.0.1:8080/",
+			want:     "http://127.0.0.1:8080/",
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			endpoint, err := url.Parse(test.endpoint)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := UploadURL(endpoint).String(); got != test.want {
+				t.Errorf("UploadURL(%q) = %q, want %q", test.endpoint, got, test.want)
+			}
+			if endpoint.String() != test.endpoint {
+				t.Errorf("UploadURL() modified the endpoint to %q", endpoint)
+			}
+		})
+	}
+}
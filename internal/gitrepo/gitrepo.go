@@ -16,12 +16,20 @@
 package gitrepo
 
 import (
+	"bufio"
+	"bytes"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"log/slog"
 	"os"
 	"os/exec"
+	"path/filepath"
+	"regexp"
+	"slices"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/go-git/go-git/v5"
@@ -34,6 +42,7 @@ import (
 // Repository defines the interface for git repository operations.
 type Repository interface {
 	AddAll() (git.Status, error)
+	AddPaths(paths []string) (git.Status, error)
 	Commit(msg string) error
 	IsClean() (bool, error)
 	Remotes() ([]*git.Remote, error)
@@ -43,9 +52,13 @@ type Repository interface {
 	GetCommit(commitHash string) (*Commit, error)
 	GetCommitsForPathsSinceTag(paths []string, tagName string) ([]*Commit, error)
 	GetCommitsForPathsSinceCommit(paths []string, sinceCommit string) ([]*Commit, error)
+	DeepenUntilReachable(commits []string) error
 	CreateBranchAndCheckout(name string) error
+	CheckoutCommit(commitHash string) error
+	ForceCheckoutCommit(commitHash string) error
 	Push(branchName string) error
 	Restore(paths []string) error
+	CleanUntracked(paths []string) error
 	pushRefSpec(refSpec string) error
 }
 
@@ -54,6 +67,15 @@ type LocalRepository struct {
 	Dir         string
 	repo        *git.Repository
 	gitPassword string
+	// indexFile, if not empty, is the on-disk index of the paths changed by
+	// each commit.
+	indexFile string
+	// indexLoaded is true once the on-disk index is read into changedPaths.
+	indexLoaded bool
+	// changedPaths caches the paths changed by each commit, shared by all
+	// the queries on the commit history.
+	changedPaths map[plumbing.Hash][]string
+	mu           sync.Mutex
 }
 
 // Commit represents a git commit.
@@ -68,11 +90,12 @@ type RepositoryOptions struct {
 	// Dir is the directory where the repository will reside locally. Required.
 	Dir string
 	// MaybeClone will try to clone the repository if it does not exist locally.
-	// If set to true, RemoteURL and RemoteBranch must also be set. Optional.
+	// If set to true, RemoteURL must also be set. Optional.
 	MaybeClone bool
 	// RemoteURL is the URL of the remote repository to clone from. Required if MaybeClone is set to true.
 	RemoteURL string
-	// RemoteBranch is the remote branch to clone. Required if MaybeClone is set to true.
+	// RemoteBranch is the remote branch to clone. If empty, the default
+	// branch of the remote repository is cloned. Optional.
 	RemoteBranch string
 	// CI is the type of Continuous Integration (CI) environment in which
 	// the tool is executing.
@@ -81,6 +104,15 @@ type RepositoryOptions struct {
 	GitPassword string
 	// Depth controls the cloning depth if the repository needs to be cloned.
 	Depth int
+	// IndexChangedPaths stores the paths changed by each commit in the git
+	// directory of the repository, so they are only computed once across
+	// runs. Optional.
+	IndexChangedPaths bool
+	// SparsePaths, if not empty, makes a sparse and partial clone of the
+	// repository, where only the files in these directories are downloaded and
+	// checked out. If the repository already exists as a sparse checkout, any
+	// missing directories are added to it. Optional.
+	SparsePaths []string
 }
 
 // NewRepository provides access to a git repository based on the provided options.
@@ -95,6 +127,9 @@ func NewRepository(opts *RepositoryOptions) (*LocalRepository, error) {
 		return repo, err
 	}
 	repo.gitPassword = opts.GitPassword
+	if opts.IndexChangedPaths {
+		repo.indexFile = filepath.Join(repo.Dir, git.GitDirName, "librarian", "changed-paths.jsonl")
+	}
 	return repo, nil
 }
 
@@ -109,16 +144,25 @@ func newRepositoryWithoutUser(opts *RepositoryOptions) (*LocalRepository, error)
 	slog.Info("Checking for repository", "dir", opts.Dir)
 	_, err := os.Stat(opts.Dir)
 	if err == nil {
-		return open(opts.Dir)
+		repo, err := open(opts.Dir)
+		if err != nil {
+			return nil, err
+		}
+		if len(opts.SparsePaths) != 0 && repo.isSparseCheckout() {
+			if err := repo.addSparsePaths(opts.SparsePaths); err != nil {
+				return nil, err
+			}
+		}
+		return repo, nil
 	}
 	if os.IsNotExist(err) {
 		if opts.RemoteURL == "" {
 			return nil, fmt.Errorf("gitrepo: remote URL is required when cloning")
 		}
-		if opts.RemoteBranch == "" {
-			return nil, fmt.Errorf("gitrepo: remote branch is required when cloning")
-		}
 		slog.Info("Repository not found, executing clone")
+		if len(opts.SparsePaths) != 0 {
+			return sparseClone(opts.Dir, opts.RemoteURL, opts.RemoteBranch, opts.CI, opts.Depth, opts.SparsePaths)
+		}
 		return clone(opts.Dir, opts.RemoteURL, opts.RemoteBranch, opts.CI, opts.Depth)
 	}
 	return nil, fmt.Errorf("failed to check for repository at %q: %w", opts.Dir, err)
@@ -140,15 +184,18 @@ func open(dir string) (*LocalRepository, error) {
 func clone(dir, url, branch, ci string, depth int) (*LocalRepository, error) {
 	slog.Info("Cloning repository", "url", url, "dir", dir)
 	options := &git.CloneOptions{
-		URL:           url,
-		ReferenceName: plumbing.NewBranchReferenceName(branch),
-		SingleBranch:  true,
-		Tags:          git.AllTags,
-		Depth:         depth,
+		URL:          url,
+		SingleBranch: true,
+		Tags:         git.AllTags,
+		Depth:        depth,
 		// .NET uses submodules for conformance tests.
 		// (There may be other examples too.)
 		RecurseSubmodules: git.DefaultSubmoduleRecursionDepth,
 	}
+	if branch != "" {
+		// Without a reference name, the remote HEAD is cloned.
+		options.ReferenceName = plumbing.NewBranchReferenceName(branch)
+	}
 	if ci == "" {
 		options.Progress = os.Stdout // When not a CI build, output progress.
 	}
@@ -163,6 +210,96 @@ func clone(dir, url, branch, ci string, depth int) (*LocalRepository, error) {
 	}, nil
 }
 
+// sparseClone clones the repository without downloading the contents of files
+// outside the given directories, and checks out only those directories.
+//
+// Wrap git operations in exec, because go-git does not support partial clones.
+func sparseClone(dir, url, branch, ci string, depth int, paths []string) (*LocalRepository, error) {
+	slog.Info("Cloning repository with sparse checkout", "url", url, "dir", dir, "paths", strings.Join(paths, ","))
+	args := []string{"clone", "--filter=blob:none", "--sparse", "--single-branch"}
+	if branch != "" {
+		args = append(args, "--branch", branch)
+	}
+	if depth > 0 {
+		args = append(args, fmt.Sprintf("--depth=%d", depth))
+	}
+	if ci != "" {
+		args = append(args, "--quiet") // Only output progress when not a CI build.
+	}
+	args = append(args, url, dir)
+	if err := runGit("", args...); err != nil {
+		return nil, fmt.Errorf("failed to clone %s: %w", url, err)
+	}
+	if err := runGit(dir, append([]string{"sparse-checkout", "set"}, paths...)...); err != nil {
+		return nil, fmt.Errorf("failed to set sparse checkout paths: %w", err)
+	}
+	return open(dir)
+}
+
+// isSparseCheckout reports whether the working tree is a sparse checkout.
+func (r *LocalRepository) isSparseCheckout() bool {
+	out, err := gitOutput(r.Dir, "config", "--get", "core.sparseCheckout")
+	return err == nil && strings.TrimSpace(out) == "true"
+}
+
+// addSparsePaths adds any missing directories to a sparse checkout. With a
+// partial clone, git downloads the contents of the new directories as needed.
+func (r *LocalRepository) addSparsePaths(paths []string) error {
+	out, err := gitOutput(r.Dir, "sparse-checkout", "list")
+	if err != nil {
+		return fmt.Errorf("failed to list sparse checkout paths: %w", err)
+	}
+	current := map[string]bool{}
+	for _, line := range strings.Split(out, "\n") {
+		current[strings.TrimSpace(line)] = true
+	}
+	var missing []string
+	for _, p := range paths {
+		if !current[p] {
+			missing = append(missing, p)
+		}
+	}
+	if len(missing) == 0 {
+		return nil
+	}
+	slog.Info("Expanding sparse checkout", "dir", r.Dir, "paths", strings.Join(missing, ","))
+	if err := runGit(r.Dir, append([]string{"sparse-checkout", "add"}, missing...)...); err != nil {
+		return fmt.Errorf("failed to add sparse checkout paths: %w", err)
+	}
+	return nil
+}
+
+// runGit runs a git command in dir, forwarding its output.
+func runGit(dir string, args ...string) error {
+	cmd := exec.Command("git", args...)
+	cmd.Stderr = os.Stderr
+	cmd.Stdout = os.Stdout
+	cmd.Dir = dir
+	return cmd.Run()
+}
+
+// gitProgress runs a git command in dir and returns its standard error, which
+// is also copied to the standard error of the process. Git reports progress,
+// such as the number of objects fetched, on its standard error.
+func gitProgress(dir string, args ...string) (string, error) {
+	var stderr bytes.Buffer
+	cmd := exec.Command("git", args...)
+	cmd.Stderr = io.MultiWriter(os.Stderr, &stderr)
+	cmd.Stdout = os.Stdout
+	cmd.Dir = dir
+	err := cmd.Run()
+	return stderr.String(), err
+}
+
+// gitOutput runs a git command in dir and returns its standard output.
+func gitOutput(dir string, args ...string) (string, error) {
+	cmd := exec.Command("git", args...)
+	cmd.Stderr = os.Stderr
+	cmd.Dir = dir
+	out, err := cmd.Output()
+	return string(out), err
+}
+
 // AddAll adds all pending changes from the working tree to the index,
 // so that the changes can later be committed.
 func (r *LocalRepository) AddAll() (git.Status, error) {
@@ -177,6 +314,28 @@ func (r *LocalRepository) AddAll() (git.Status, error) {
 	return worktree.Status()
 }
 
+// AddPaths adds the pending changes in the given files and directories to the
+// index, including deleted files. Paths without changes are ignored.
+func (r *LocalRepository) AddPaths(paths []string) (git.Status, error) {
+	worktree, err := r.repo.Worktree()
+	if err != nil {
+		return git.Status{}, err
+	}
+	status, err := worktree.Status()
+	if err != nil {
+		return git.Status{}, err
+	}
+	for file, fileStatus := range status {
+		if fileStatus.Worktree == git.Unmodified || !changesAnyPath([]string{file}, paths) {
+			continue
+		}
+		if _, err := worktree.Add(file); err != nil {
+			return git.Status{}, err
+		}
+	}
+	return worktree.Status()
+}
+
 // Commit creates a new commit with the provided message and author
 // information.
 func (r *LocalRepository) Commit(msg string) error {
@@ -306,39 +465,23 @@ func (r *LocalRepository) GetCommitsForPathsSinceCommit(paths []string, sinceCom
 		if commit.NumParents() != 1 {
 			return nil
 		}
-		parentCommit, err := commit.Parent(0)
+		// The paths changed by each commit are computed once, and shared by
+		// all the queries. This is much faster than comparing the tree of
+		// each path with the parent commit on every query, as the history is
+		// queried for each library.
+		files, err := r.changedPathsInCommit(commit)
 		if err != nil {
 			return err
 		}
-
-		// We perform filtering by finding out if the tree hash for the given
-		// path at the commit we're looking at is the same as the tree hash
-		// for the commit's parent.
-		// This is much, much faster than any other filtering option, it seems.
-		// In theory, we should be able to remember our "current" commit for each
-		// path, but that's likely to be significantly more complex.
-		for _, candidatePath := range paths {
-			currentPathHash, err := getHashForPathOrEmpty(commit, candidatePath)
-			if err != nil {
-				return err
-			}
-			parentPathHash, err := getHashForPathOrEmpty(parentCommit, candidatePath)
-			if err != nil {
-				return err
-			}
-			// If we've found a change (including a path being added or removed),
-			// add it to our list of commits and proceed to the next commit.
-			if currentPathHash != parentPathHash {
-
-				commits = append(commits, &Commit{
-					Hash:    commit.Hash,
-					Message: commit.Message,
-					When:    commit.Author.When,
-				})
-				return nil
-			}
+		// If we've found a change (including a path being added or removed),
+		// add it to our list of commits and proceed to the next commit.
+		if changesAnyPath(files, paths) {
+			commits = append(commits, &Commit{
+				Hash:    commit.Hash,
+				Message: commit.Message,
+				When:    commit.Author.When,
+			})
 		}
-
 		return nil
 	})
 	if err != nil && err != ErrStopIterating {
@@ -350,68 +493,270 @@ func (r *LocalRepository) GetCommitsForPathsSinceCommit(paths []string, sinceCom
 	return commits, nil
 }
 
-// getHashForPathOrEmpty returns the hash for a path at a given commit, or an
-// empty string if the path (file or directory) did not exist.
-func getHashForPathOrEmpty(commit *object.Commit, path string) (string, error) {
-	tree, err := commit.Tree()
-	if err != nil {
-		return "", err
+const (
+	// initialDeepen is the number of commits fetched by the first attempt of
+	// [LocalRepository.DeepenUntilReachable]. Each following attempt doubles
+	// the number of commits.
+	initialDeepen = 64
+	// maxDeepen is the largest number of commits fetched when deepening a
+	// shallow clone. Past this point the remaining history is fetched at once.
+	maxDeepen = 8192
+)
+
+// DeepenUntilReachable fetches more history into a shallow clone until all the
+// given commits are available locally.
+//
+// The history is deepened progressively, doubling the number of commits on
+// each attempt, and fetched completely as a last resort. Commits which are
+// still missing once the complete history is fetched are only reported with a
+// warning, as they may have been removed from the remote by a force push.
+// Repositories which are not shallow clones are left untouched, even if some
+// commits are missing. Empty commit hashes are ignored.
+//
+// Wrap git operations in exec, because [git.Repository.Fetch] does not
+// support deepening an existing shallow clone.
+func (r *LocalRepository) DeepenUntilReachable(commits []string) error {
+	missing := r.missingCommits(commits)
+	if len(missing) == 0 {
+		return nil
 	}
-	treeEntry, err := tree.FindEntry(path)
-	if err == object.ErrEntryNotFound || err == object.ErrDirectoryNotFound {
-		return "", nil
+	for deepen := initialDeepen; ; deepen *= 2 {
+		shallow, err := r.repo.Storer.Shallow()
+		if err != nil {
+			return err
+		}
+		if len(shallow) == 0 {
+			if deepen != initialDeepen {
+				slog.Warn("Commits not found in the repository history", "dir", r.Dir, "commits", strings.Join(missing, ", "))
+			}
+			return nil
+		}
+		args := []string{"fetch", "--progress", fmt.Sprintf("--deepen=%d", deepen), "origin"}
+		if deepen > maxDeepen {
+			args = []string{"fetch", "--progress", "--unshallow", "origin"}
+		}
+		slog.Info("Deepening shallow clone", "dir", r.Dir, "missing", len(missing), "args", strings.Join(args, " "))
+		start := time.Now()
+		progress, err := gitProgress(r.Dir, args...)
+		if err != nil {
+			return fmt.Errorf("failed to deepen repository %s: %w", r.Dir, err)
+		}
+		objects, size := fetchStats(progress)
+		// The object storage caches the list of packfiles, reopen the
+		// repository to find the fetched objects.
+		repo, err := git.PlainOpen(r.Dir)
+		if err != nil {
+			return err
+		}
+		r.repo = repo
+		missing = r.missingCommits(missing)
+		slog.Info("Deepened shallow clone", "dir", r.Dir, "args", strings.Join(args, " "), "missing", len(missing),
+			"objects", objects, "size", size, "elapsed", time.Since(start))
+		if len(missing) == 0 {
+			return nil
+		}
 	}
-	if err != nil {
-		return "", err
+}
+
+var (
+	// fetchReceivedRegexp matches the final progress line of the objects
+	// received by git fetch, e.g.
+	// "Receiving objects: 100% (1234/1234), 5.67 MiB | 8.90 MiB/s, done.".
+	// Small fetches are unpacked and report "Unpacking objects" instead.
+	fetchReceivedRegexp = regexp.MustCompile(`(?:Receiving|Unpacking) objects: 100% \((\d+)/\d+\), ([\d.]+ (?:bytes|[KMGT]iB))`)
+	// fetchTotalRegexp matches the number of objects sent by the remote, e.g.
+	// "remote: Total 1234 (delta 56), reused 0 (delta 0), pack-reused 0".
+	fetchTotalRegexp = regexp.MustCompile(`remote: Total (\d+)`)
+)
+
+// fetchStats returns the number of objects and the size of the data reported
+// in the progress output of git fetch. Values which are not reported, e.g.
+// when the fetch is too fast for git to show the progress, are empty.
+func fetchStats(progress string) (objects, size string) {
+	if m := fetchReceivedRegexp.FindAllStringSubmatch(progress, -1); len(m) > 0 {
+		last := m[len(m)-1]
+		return last[1], last[2]
+	}
+	if m := fetchTotalRegexp.FindStringSubmatch(progress); m != nil {
+		return m[1], ""
 	}
-	return treeEntry.Hash.String(), nil
+	return "", ""
 }
 
-// ChangedFilesInCommit returns the files changed in the given commit.
-func (r *LocalRepository) ChangedFilesInCommit(commitHash string) ([]string, error) {
-	commit, err := r.repo.CommitObject(plumbing.NewHash(commitHash))
-	if err != nil {
-		return nil, fmt.Errorf("failed to get commit object for hash %s: %w", commitHash, err)
+// missingCommits returns the subset of commits which are not in the local
+// repository.
+func (r *LocalRepository) missingCommits(commits []string) []string {
+	var missing []string
+	for _, commit := range commits {
+		if commit == "" {
+			continue
+		}
+		if _, err := r.repo.CommitObject(plumbing.NewHash(commit)); err != nil {
+			missing = append(missing, commit)
+		}
 	}
+	return missing
+}
 
-	var fromTree *object.Tree
-	var toTree *object.Tree
+// changesAnyPath reports whether any of the changed files is one of the paths,
+// or is in one of the directories.
+func changesAnyPath(files, paths []string) bool {
+	for _, file := range files {
+		for _, path := range paths {
+			path = strings.TrimSuffix(path, "/")
+			if file == path || strings.HasPrefix(file, path+"/") {
+				return true
+			}
+		}
+	}
+	return false
+}
 
-	toTree, err = commit.Tree()
+// changedPathsInCommit returns the paths of the files changed by the commit,
+// compared to its first parent.
+//
+// The result is cached in memory and, if enabled, in the on-disk index.
+func (r *LocalRepository) changedPathsInCommit(commit *object.Commit) ([]string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.changedPaths == nil {
+		r.changedPaths = map[plumbing.Hash][]string{}
+	}
+	r.loadChangedPathsIndex()
+	if files, ok := r.changedPaths[commit.Hash]; ok {
+		return files, nil
+	}
+	files, err := diffCommit(commit)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get tree for commit %s: %w", commitHash, err)
+		return nil, err
 	}
+	r.appendChangedPathsIndex(commit.Hash, files)
+	r.changedPaths[commit.Hash] = files
+	return files, nil
+}
 
-	if commit.NumParents() == 0 {
-		fromTree = &object.Tree{} // Empty tree for initial commit
-	} else {
+// diffCommit computes the paths of the files changed by the commit, compared
+// to its first parent.
+//
+// Compare the trees instead of computing a patch, as partial clones may not
+// have the contents of the files.
+func diffCommit(commit *object.Commit) ([]string, error) {
+	toTree, err := commit.Tree()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tree for commit %s: %w", commit.Hash, err)
+	}
+	fromTree := &object.Tree{} // Empty tree for initial commit
+	if commit.NumParents() != 0 {
 		parent, err := commit.Parent(0)
 		if err != nil {
-			return nil, fmt.Errorf("failed to get parent for commit %s: %w", commitHash, err)
+			return nil, fmt.Errorf("failed to get parent for commit %s: %w", commit.Hash, err)
 		}
 		fromTree, err = parent.Tree()
 		if err != nil {
-			return nil, fmt.Errorf("failed to get parent tree for commit %s: %w", commitHash, err)
+			return nil, fmt.Errorf("failed to get parent tree for commit %s: %w", commit.Hash, err)
 		}
 	}
-
-	patch, err := fromTree.Patch(toTree)
+	changes, err := fromTree.Diff(toTree)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get patch for commit %s: %w", commitHash, err)
+		return nil, fmt.Errorf("failed to get changes for commit %s: %w", commit.Hash, err)
 	}
-	var files []string
-	for _, filePatch := range patch.FilePatches() {
-		from, to := filePatch.Files()
-		if from != nil {
-			files = append(files, from.Path())
+	files := []string{}
+	for _, change := range changes {
+		from, to := change.From.Name, change.To.Name
+		if from != "" {
+			files = append(files, from)
 		}
-		if to != nil && (from == nil || from.Path() != to.Path()) {
-			files = append(files, to.Path())
+		if to != "" && from != to {
+			files = append(files, to)
 		}
 	}
 	return files, nil
 }
 
+// changedPathsIndexEntry is an entry in the on-disk index, with the paths
+// changed by a commit. The index is a JSON Lines file, with one entry per line.
+type changedPathsIndexEntry struct {
+	Hash  string   `json:"hash"`
+	Paths []string `json:"paths"`
+}
+
+// loadChangedPathsIndex reads the on-disk index, if enabled, into the
+// in-memory cache. The index is read once per repository. Lines which cannot
+// be parsed, such as an entry partially written by an interrupted run, are
+// ignored.
+func (r *LocalRepository) loadChangedPathsIndex() {
+	if r.indexFile == "" || r.indexLoaded {
+		return
+	}
+	r.indexLoaded = true
+	f, err := os.Open(r.indexFile)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			slog.Warn("failed to read changed paths index", "file", r.indexFile, "err", err)
+		}
+		return
+	}
+	defer f.Close()
+	scanner := bufio.NewScanner(f)
+	// Some commits change many files, such as a regeneration of all the
+	// APIs.
+	scanner.Buffer(nil, 64*1024*1024)
+	for scanner.Scan() {
+		var entry changedPathsIndexEntry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil || entry.Paths == nil {
+			continue
+		}
+		r.changedPaths[plumbing.NewHash(entry.Hash)] = entry.Paths
+	}
+	if err := scanner.Err(); err != nil {
+		slog.Warn("failed to read changed paths index", "file", r.indexFile, "err", err)
+	}
+}
+
+// appendChangedPathsIndex appends the paths changed by a commit to the on-disk
+// index, if enabled. Each entry is written with a single append, so concurrent
+// writers do not interleave entries. The index is only an optimization, so
+// errors are logged and ignored.
+func (r *LocalRepository) appendChangedPathsIndex(hash plumbing.Hash, files []string) {
+	if r.indexFile == "" {
+		return
+	}
+	line, err := json.Marshal(&changedPathsIndexEntry{Hash: hash.String(), Paths: files})
+	if err != nil {
+		slog.Warn("failed to encode changed paths index entry", "commit", hash.String(), "err", err)
+		return
+	}
+	if err := os.MkdirAll(filepath.Dir(r.indexFile), 0755); err != nil {
+		slog.Warn("failed to create changed paths index directory", "dir", filepath.Dir(r.indexFile), "err", err)
+		return
+	}
+	f, err := os.OpenFile(r.indexFile, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		slog.Warn("failed to open changed paths index", "file", r.indexFile, "err", err)
+		return
+	}
+	defer f.Close()
+	if _, err := f.Write(append(line, '\n')); err != nil {
+		slog.Warn("failed to write changed paths index", "file", r.indexFile, "err", err)
+	}
+}
+
+// ChangedFilesInCommit returns the files changed in the given commit.
+func (r *LocalRepository) ChangedFilesInCommit(commitHash string) ([]string, error) {
+	commit, err := r.repo.CommitObject(plumbing.NewHash(commitHash))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get commit object for hash %s: %w", commitHash, err)
+	}
+	files, err := r.changedPathsInCommit(commit)
+	if err != nil {
+		return nil, err
+	}
+	if len(files) == 0 {
+		return nil, nil
+	}
+	return slices.Clone(files), nil
+}
+
 // CreateBranchAndCheckout creates a new git branch and checks out the
 // branch in the local git repository.
 func (r *LocalRepository) CreateBranchAndCheckout(name string) error {
@@ -427,6 +772,33 @@ func (r *LocalRepository) CreateBranchAndCheckout(name string) error {
 	})
 }
 
+// CheckoutCommit checks out the given commit, detaching HEAD. The changes in
+// the working tree are kept, but unstaged.
+func (r *LocalRepository) CheckoutCommit(commitHash string) error {
+	slog.Info("Checking out commit", "hash", commitHash)
+	worktree, err := r.repo.Worktree()
+	if err != nil {
+		return err
+	}
+	hash := plumbing.NewHash(commitHash)
+	if err := worktree.Checkout(&git.CheckoutOptions{Hash: hash, Keep: true}); err != nil {
+		return err
+	}
+	return worktree.Reset(&git.ResetOptions{Commit: hash, Mode: git.MixedReset})
+}
+
+// ForceCheckoutCommit checks out the given commit, detaching HEAD. Unlike
+// CheckoutCommit, the tracked files in the working tree are replaced by their
+// contents in the commit, discarding any changes.
+func (r *LocalRepository) ForceCheckoutCommit(commitHash string) error {
+	slog.Info("Checking out commit", "hash", commitHash, "force", true)
+	worktree, err := r.repo.Worktree()
+	if err != nil {
+		return err
+	}
+	return worktree.Checkout(&git.CheckoutOptions{Hash: plumbing.NewHash(commitHash), Force: true})
+}
+
 // Push pushes the local branch to the origin remote.
 func (r *LocalRepository) Push(branchName string) error {
 	// https://stackoverflow.com/a/75727620
@@ -479,3 +851,19 @@ func (r *LocalRepository) Restore(paths []string) error {
 	cmd.Dir = r.Dir
 	return cmd.Run()
 }
+
+// CleanUntracked removes untracked files and directories in the given paths.
+// Ignored files are not removed.
+//
+// Wrap git operations in exec, because go-git does not support cleaning
+// specific paths.
+func (r *LocalRepository) CleanUntracked(paths []string) error {
+	args := []string{"clean", "-f", "-d", "--"}
+	args = append(args, paths...)
+	slog.Info("Removing untracked files", "paths", strings.Join(paths, ","))
+	cmd := exec.Command("git", args...)
+	cmd.Stderr = os.Stderr
+	cmd.Stdout = os.Stdout
+	cmd.Dir = r.Dir
+	return cmd.Run()
+}
@@ -15,6 +15,8 @@
 package librarian
 
 import (
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 
@@ -47,6 +49,10 @@ func TestLibrarianAction(t *testing.T) {
 // separately in internal/config.
 func testActionConfig(t *testing.T, cmd *cli.Command) {
 	t.Helper()
+	cloneWorkRoot := t.TempDir()
+	if err := os.Mkdir(filepath.Join(cloneWorkRoot, "language-repo"), 0755); err != nil {
+		t.Fatal(err)
+	}
 	for _, test := range []struct {
 		cfg     *config.Config
 		wantErr string
@@ -73,11 +79,13 @@ func testActionConfig(t *testing.T, cmd *cli.Command) {
 			wantErr: "specified library version without library id",
 		},
 		{
+			// The work root has a directory for the repository URL, which
+			// is opened instead of cloning the repository.
 			cfg: &config.Config{
-				WorkRoot: t.TempDir(),
+				WorkRoot: cloneWorkRoot,
 				Repo:     "https://github.com/googleapis/language-repo",
 			},
-			wantErr: "remote branch is required when cloning",
+			wantErr: "repository does not exist",
 		},
 	} {
 		t.Run(test.wantErr, func(t *testing.T) {
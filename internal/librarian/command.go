@@ -38,8 +38,9 @@ import (
 )
 
 const (
-	generate = "generate"
-	release  = "release"
+	generate    = "generate"
+	release     = "release"
+	updateImage = "update-image"
 )
 
 var globalPreservePatterns = []string{
@@ -57,14 +58,19 @@ type GitHubClient interface {
 	GetPullRequest(ctx context.Context, number int) (*github.PullRequest, error)
 	CreateRelease(ctx context.Context, tagName, name, body, commitish string) (*github.RepositoryRelease, error)
 	CreateIssueComment(ctx context.Context, number int, comment string) error
+	UploadReleaseAsset(ctx context.Context, releaseID int64, path string) error
 	CreateTag(ctx context.Context, tag, commitish string) error
+	ClosePullRequest(ctx context.Context, number int) error
 }
 
 // ContainerClient is an abstraction over the Docker client.
 type ContainerClient interface {
 	Build(ctx context.Context, request *docker.BuildRequest) error
 	Configure(ctx context.Context, request *docker.ConfigureRequest) (string, error)
+	Describe(ctx context.Context, request *docker.DescribeRequest) (*docker.Capabilities, error)
 	Generate(ctx context.Context, request *docker.GenerateRequest) error
+	Package(ctx context.Context, request *docker.PackageRequest) error
+	Publish(ctx context.Context, request *docker.PublishRequest) error
 	ReleaseInit(ctx context.Context, request *docker.ReleaseInitRequest) error
 }
 
@@ -75,6 +81,8 @@ type commitInfo struct {
 	failedLibraries   []string
 	ghClient          GitHubClient
 	idToCommits       map[string]string
+	imageUpdate       *imageUpdate
+	librarianConfig   *config.LibrarianConfig
 	library           string
 	libraryVersion    string
 	prType            string
@@ -82,12 +90,19 @@ type commitInfo struct {
 	push              bool
 	repo              gitrepo.Repository
 	sourceRepo        gitrepo.Repository
-	state             *config.LibrarianState
+	sourceRepos       map[string]gitrepo.Repository
+	sourceCommits     map[string]map[string]string
+	apiSource         string
+	// baseState is the state before generation, used when the generated
+	// changes are split into several pull requests.
+	baseState *config.LibrarianState
+	state     *config.LibrarianState
 }
 
 type commandRunner struct {
 	repo            gitrepo.Repository
 	sourceRepo      gitrepo.Repository
+	sourceRepos     map[string]gitrepo.Repository
 	state           *config.LibrarianState
 	librarianConfig *config.LibrarianConfig
 	ghClient        GitHubClient
@@ -99,32 +114,42 @@ type commandRunner struct {
 const defaultAPISourceBranch = "master"
 
 func newCommandRunner(cfg *config.Config) (*commandRunner, error) {
-	languageRepo, err := cloneOrOpenRepo(cfg.WorkRoot, cfg.Repo, cfg.APISourceDepth, cfg.Branch, cfg.CI, cfg.GitHubToken)
+	languageRepo, err := cloneOrOpenRepo(cfg.WorkRoot, cfg.Repo, cfg.APISourceDepth, cfg.Branch, cfg.CI, cfg.GitHubToken, nil)
 	if err != nil {
 		return nil, err
 	}
 
-	var (
-		sourceRepo    gitrepo.Repository
-		sourceRepoDir string
-	)
-	if cfg.CommandName == generateCmdName {
-		sourceRepo, err = cloneOrOpenRepo(cfg.WorkRoot, cfg.APISource, cfg.APISourceDepth, defaultAPISourceBranch, cfg.CI, cfg.GitHubToken)
-		if err != nil {
-			return nil, err
-		}
-		sourceRepoDir = sourceRepo.GetDir()
-	}
-	state, err := loadRepoState(languageRepo, sourceRepoDir)
+	state, err := loadRepoState(languageRepo, "")
 	if err != nil {
 		return nil, err
 	}
-
 	librarianConfig, err := loadLibrarianConfig(languageRepo)
 	if err != nil {
 		return nil, err
 	}
 
+	var (
+		sourceRepo  gitrepo.Repository
+		sourceRepos map[string]gitrepo.Repository
+	)
+	if cfg.CommandName == generateCmdName || cfg.CommandName == updateImageCmdName {
+		sparsePaths := apiSourceSparsePaths(librarianConfig, state, "", cfg.API)
+		sourceRepo, err = cloneOrOpenRepo(cfg.WorkRoot, cfg.APISource, cfg.APISourceDepth, defaultAPISourceBranch, cfg.CI, cfg.GitHubToken, sparsePaths)
+		if err != nil {
+			return nil, err
+		}
+		if err := populateServiceConfigIfEmpty(state, sourceRepo.GetDir()); err != nil {
+			return nil, fmt.Errorf("populating service config: %w", err)
+		}
+		if err := sourceRepo.DeepenUntilReachable(lastGeneratedCommits(processedLibraries(cfg, state), "")); err != nil {
+			return nil, fmt.Errorf("failed to fetch the history of the api source: %w", err)
+		}
+		sourceRepos, err = cloneOrOpenAPISources(cfg, state, librarianConfig)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	image := deriveImage(cfg.Image, state)
 
 	var gitRepo *github.Repository
@@ -159,6 +184,7 @@ func newCommandRunner(cfg *config.Config) (*commandRunner, error) {
 		workRoot:        cfg.WorkRoot,
 		repo:            languageRepo,
 		sourceRepo:      sourceRepo,
+		sourceRepos:     sourceRepos,
 		state:           state,
 		librarianConfig: librarianConfig,
 		image:           image,
@@ -167,7 +193,7 @@ func newCommandRunner(cfg *config.Config) (*commandRunner, error) {
 	}, nil
 }
 
-func cloneOrOpenRepo(workRoot, repo string, depth int, branch, ci string, gitPassword string) (*gitrepo.LocalRepository, error) {
+func cloneOrOpenRepo(workRoot, repo string, depth int, branch, ci string, gitPassword string, sparsePaths []string) (*gitrepo.LocalRepository, error) {
 	if repo == "" {
 		return nil, fmt.Errorf("repo must be specified")
 	}
@@ -186,6 +212,10 @@ func cloneOrOpenRepo(workRoot, repo string, depth int, branch, ci string, gitPas
 			CI:           ci,
 			GitPassword:  gitPassword,
 			Depth:        depth,
+			SparsePaths:  sparsePaths,
+			// Librarian owns the clones in the work root, so the changed
+			// paths index can be reused by later runs.
+			IndexChangedPaths: true,
 		})
 	}
 	// repo is a directory
@@ -211,6 +241,109 @@ func cloneOrOpenRepo(workRoot, repo string, depth int, branch, ci string, gitPas
 	return githubRepo, nil
 }
 
+// cloneOrOpenAPISources clones, or opens, the additional API sources in the
+// state, and fills the service config of their APIs.
+//
+// The API sources are cloned in `{workRoot}/sources/{name}`, to avoid clashes
+// with the default API source and the language repository.
+func cloneOrOpenAPISources(cfg *config.Config, state *config.LibrarianState, librarianConfig *config.LibrarianConfig) (map[string]gitrepo.Repository, error) {
+	repos := map[string]gitrepo.Repository{}
+	for _, source := range state.APISources {
+		workRoot := filepath.Join(cfg.WorkRoot, "sources", source.Name)
+		if isURL(source.URL) {
+			if err := os.MkdirAll(workRoot, 0755); err != nil {
+				return nil, err
+			}
+		}
+		sparsePaths := apiSourceSparsePaths(librarianConfig, state, source.Name)
+		repo, err := cloneOrOpenRepo(workRoot, source.URL, cfg.APISourceDepth, source.Branch, cfg.CI, cfg.GitHubToken, sparsePaths)
+		if err != nil {
+			return nil, fmt.Errorf("failed to clone api source %s: %w", source.Name, err)
+		}
+		if err := repo.DeepenUntilReachable(lastGeneratedCommits(processedLibraries(cfg, state), source.Name)); err != nil {
+			return nil, fmt.Errorf("failed to fetch the history of api source %s: %w", source.Name, err)
+		}
+		if err := populateServiceConfigForSource(state, source.Name, repo.GetDir()); err != nil {
+			return nil, fmt.Errorf("populating service config for api source %s: %w", source.Name, err)
+		}
+		repos[source.Name] = repo
+	}
+	return repos, nil
+}
+
+// apiSourceSparsePaths returns the directories to check out from the given
+// API source, or nil if sparse checkouts are not enabled. These are the paths
+// of the APIs in the source, any additional API paths, and the common paths
+// from the config. The default API source is named "".
+func apiSourceSparsePaths(librarianConfig *config.LibrarianConfig, state *config.LibrarianState, source string, apiPaths ...string) []string {
+	if librarianConfig == nil || !librarianConfig.APISourceSparseCheckout {
+		return nil
+	}
+	paths := slices.Clone(librarianConfig.CommonAPISourcePaths())
+	for _, library := range state.Libraries {
+		for _, api := range library.APIs {
+			if api.Source == source {
+				paths = append(paths, api.Path)
+			}
+		}
+	}
+	for _, apiPath := range apiPaths {
+		if apiPath != "" {
+			paths = append(paths, apiPath)
+		}
+	}
+	slices.Sort(paths)
+	return slices.Compact(paths)
+}
+
+// processedLibraries returns the libraries processed by the command: the
+// library selected with `--library` or `--api`, if any, or else all the
+// libraries in the state. A new library, which is not in the state yet, is not
+// returned.
+func processedLibraries(cfg *config.Config, state *config.LibrarianState) []*config.LibraryState {
+	libraryID := cfg.Library
+	if libraryID == "" && cfg.API != "" {
+		libraryID = findLibraryIDByAPIPath(state, cfg.API)
+		if libraryID == "" {
+			return nil
+		}
+	}
+	if libraryID == "" {
+		return state.Libraries
+	}
+	if library := findLibraryByID(state, libraryID); library != nil {
+		return []*config.LibraryState{library}
+	}
+	return nil
+}
+
+// lastGeneratedCommits returns the commits at which the libraries were last
+// generated from the given API source. The default API source is named "".
+func lastGeneratedCommits(libraries []*config.LibraryState, source string) []string {
+	var commits []string
+	for _, library := range libraries {
+		if commit := library.LastGeneratedCommitFor(source); commit != "" {
+			commits = append(commits, commit)
+		}
+	}
+	return commits
+}
+
+// gitHubSourceName returns the `{owner}/{name}` of the GitHub repository for
+// an API source, used in the links to its commits, and true. Sources which
+// are not GitHub repositories return `fallback` and false, as their commits
+// cannot be linked.
+func gitHubSourceName(apiSource, fallback string) (string, bool) {
+	if !isURL(apiSource) {
+		return fallback, false
+	}
+	repo, err := github.ParseRemote(apiSource)
+	if err != nil {
+		return fallback, false
+	}
+	return fmt.Sprintf("%s/%s", repo.Owner, repo.Name), true
+}
+
 func deriveImage(imageOverride string, state *config.LibrarianState) string {
 	if imageOverride != "" {
 		return imageOverride
@@ -347,6 +480,9 @@ func commitAndPush(ctx context.Context, info *commitInfo) error {
 		slog.Info("Push flag and Commit flag are not specified, skipping committing")
 		return nil
 	}
+	if info.prType == generate && splitGenerationPullRequests(info.librarianConfig) {
+		return commitAndPushGenerationGroups(ctx, info)
+	}
 
 	repo := info.repo
 	status, err := repo.AddAll()
@@ -394,7 +530,26 @@ func commitAndPush(ctx context.Context, info *commitInfo) error {
 		return fmt.Errorf("failed to create pull request: %w", err)
 	}
 
-	return addLabelsToPullRequest(ctx, info.ghClient, info.pullRequestLabels, pullRequestMetadata)
+	if err := addLabelsToPullRequest(ctx, info.ghClient, info.pullRequestLabels, pullRequestMetadata); err != nil {
+		return err
+	}
+	if info.prType != generate || !supersedesPullRequests(info, "") {
+		return nil
+	}
+	return closeSupersededPullRequests(ctx, info.ghClient, info.branch, "", pullRequestMetadata)
+}
+
+// supersedesPullRequests reports whether the generation pull request of the
+// group replaces the open generation pull requests of the same group, which
+// is only the case if it contains the same libraries. A pull request
+// generating a single library only replaces those of a group containing
+// only that library.
+func supersedesPullRequests(info *commitInfo, group string) bool {
+	if info.library == "" || group == info.library {
+		return true
+	}
+	slog.Info("generated a single library, keeping the open generation pull requests", "library", info.library, "group", group)
+	return false
 }
 
 // addLabelsToPullRequest adds a list of labels to a single pull request (specified by the id number).
@@ -417,9 +572,11 @@ func addLabelsToPullRequest(ctx context.Context, ghClient GitHubClient, pullRequ
 func createPRBody(info *commitInfo) (string, error) {
 	switch info.prType {
 	case generate:
-		return formatGenerationPRBody(info.sourceRepo, info.state, info.idToCommits, info.failedLibraries)
+		return formatGenerationPRBody(generationSources(info), info.state, info.failedLibraries)
 	case release:
-		return formatReleaseNotes(info.repo, info.state)
+		return formatReleaseNotes(info.repo, info.state, info.librarianConfig)
+	case updateImage:
+		return formatUpdateImagePRBody(info.imageUpdate, generationSources(info), info.state)
 	default:
 		return "", fmt.Errorf("unrecognized pull request type: %s", info.prType)
 	}
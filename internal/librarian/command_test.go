@@ -29,6 +29,7 @@ import (
 	gogitConfig "github.com/go-git/go-git/v5/config"
 	"github.com/go-git/go-git/v5/storage/memory"
 	"github.com/google/go-cmp/cmp"
+	gh "github.com/google/go-github/v69/github"
 	"github.com/googleapis/librarian/internal/config"
 	"github.com/googleapis/librarian/internal/github"
 	"github.com/googleapis/librarian/internal/gitrepo"
@@ -126,6 +127,207 @@ func TestDeriveImage(t *testing.T) {
 	}
 }
 
+func TestAPISourceSparsePaths(t *testing.T) {
+	state := &config.LibrarianState{
+		Libraries: []*config.LibraryState{
+			{
+				ID: "one",
+				APIs: []*config.API{
+					{Path: "google/cloud/one/v1"},
+					{Path: "google/cloud/one/v2"},
+				},
+			},
+			{
+				ID: "two",
+				APIs: []*config.API{
+					{Path: "google/cloud/two/v1", Source: "private"},
+					{Path: "google/api"},
+				},
+			},
+		},
+	}
+	for _, test := range []struct {
+		name            string
+		librarianConfig *config.LibrarianConfig
+		source          string
+		apiPaths        []string
+		want            []string
+	}{
+		{
+			name: "no config",
+		},
+		{
+			name:            "sparse checkout disabled",
+			librarianConfig: &config.LibrarianConfig{},
+		},
+		{
+			name:            "default source",
+			librarianConfig: &config.LibrarianConfig{APISourceSparseCheckout: true, APISourceCommonPaths: []string{"google/api", "google/rpc"}},
+			apiPaths:        []string{"", "google/cloud/three/v1"},
+			want:            []string{"google/api", "google/cloud/one/v1", "google/cloud/one/v2", "google/cloud/three/v1", "google/rpc"},
+		},
+		{
+			name:            "named source with default common paths",
+			librarianConfig: &config.LibrarianConfig{APISourceSparseCheckout: true},
+			source:          "private",
+			want: []string{
+				"google/api",
+				"google/cloud/location",
+				"google/cloud/two/v1",
+				"google/iam/v1",
+				"google/longrunning",
+				"google/rpc",
+				"google/type",
+			},
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			got := apiSourceSparsePaths(test.librarianConfig, state, test.source, test.apiPaths...)
+			if diff := cmp.Diff(test.want, got); diff != "" {
+				t.Errorf("apiSourceSparsePaths() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestLastGeneratedCommits(t *testing.T) {
+	state := &config.LibrarianState{
+		Libraries: []*config.LibraryState{
+			{
+				ID:                   "one",
+				LastGeneratedCommit:  "aaaa",
+				LastGeneratedCommits: map[string]string{"private": "bbbb"},
+			},
+			{
+				ID: "two",
+			},
+			{
+				ID:                  "three",
+				LastGeneratedCommit: "cccc",
+			},
+		},
+	}
+	for _, test := range []struct {
+		name   string
+		source string
+		want   []string
+	}{
+		{
+			name: "default source",
+			want: []string{"aaaa", "cccc"},
+		},
+		{
+			name:   "named source",
+			source: "private",
+			want:   []string{"bbbb"},
+		},
+		{
+			name:   "unknown source",
+			source: "unknown",
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			got := lastGeneratedCommits(state.Libraries, test.source)
+			if diff := cmp.Diff(test.want, got); diff != "" {
+				t.Errorf("lastGeneratedCommits() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestProcessedLibraries(t *testing.T) {
+	state := &config.LibrarianState{
+		Libraries: []*config.LibraryState{
+			{
+				ID:   "one",
+				APIs: []*config.API{{Path: "google/one/v1"}},
+			},
+			{
+				ID:   "two",
+				APIs: []*config.API{{Path: "google/two/v1"}},
+			},
+		},
+	}
+	for _, test := range []struct {
+		name string
+		cfg  *config.Config
+		want []string
+	}{
+		{
+			name: "all libraries",
+			cfg:  &config.Config{},
+			want: []string{"one", "two"},
+		},
+		{
+			name: "library flag",
+			cfg:  &config.Config{Library: "two"},
+			want: []string{"two"},
+		},
+		{
+			name: "api flag",
+			cfg:  &config.Config{API: "google/one/v1"},
+			want: []string{"one"},
+		},
+		{
+			name: "new library",
+			cfg:  &config.Config{Library: "three", API: "google/three/v1"},
+		},
+		{
+			name: "new api",
+			cfg:  &config.Config{API: "google/three/v1"},
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			var got []string
+			for _, library := range processedLibraries(test.cfg, state) {
+				got = append(got, library.ID)
+			}
+			if diff := cmp.Diff(test.want, got); diff != "" {
+				t.Errorf("processedLibraries() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestGitHubSourceName(t *testing.T) {
+	for _, test := range []struct {
+		name         string
+		apiSource    string
+		want         string
+		wantOnGitHub bool
+	}{
+		{
+			name:         "github url",
+			apiSource:    "https://github.com/example/private-apis",
+			want:         "example/private-apis",
+			wantOnGitHub: true,
+		},
+		{
+			name:         "github url with .git suffix",
+			apiSource:    "https://github.com/example/private-apis.git",
+			want:         "example/private-apis",
+			wantOnGitHub: true,
+		},
+		{
+			name:      "local directory",
+			apiSource: "/path/to/apis",
+			want:      "private",
+		},
+		{
+			name:      "not a github url",
+			apiSource: "https://gitlab.com/example/apis",
+			want:      "private",
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			got, onGitHub := gitHubSourceName(test.apiSource, "private")
+			if got != test.want || onGitHub != test.wantOnGitHub {
+				t.Errorf("gitHubSourceName() = (%q, %t), want (%q, %t)", got, onGitHub, test.want, test.wantOnGitHub)
+			}
+		})
+	}
+}
+
 // newTestGitRepoWithCommit creates a new git repository with an initial commit.
 // If dir is empty, a new temporary directory is created.
 // It returns the path to the repository directory.
@@ -241,7 +443,7 @@ func TestCloneOrOpenLanguageRepo(t *testing.T) {
 				}
 			}()
 
-			repo, err := cloneOrOpenRepo(workRoot, test.repo, 1, test.ci, "main", "")
+			repo, err := cloneOrOpenRepo(workRoot, test.repo, 1, test.ci, "main", "", nil)
 			if test.wantErr {
 				if err == nil {
 					t.Fatal("cloneOrOpenLanguageRepo() expected an error but got nil")
@@ -1291,6 +1493,63 @@ func TestCompileRegexps(t *testing.T) {
 	}
 }
 
+func TestCommitAndPushSupersededPullRequests(t *testing.T) {
+	openPullRequest := &github.PullRequest{
+		Number: gh.Ptr(1),
+		State:  gh.Ptr("open"),
+		Head:   &gh.PullRequestBranch{Ref: gh.Ptr("librarian-20250101T000000Z")},
+		Base:   &gh.PullRequestBranch{Ref: gh.Ptr("main")},
+	}
+	for _, test := range []struct {
+		name       string
+		library    string
+		wantClosed []int
+	}{
+		{
+			name:       "all libraries",
+			wantClosed: []int{1},
+		},
+		{
+			// The pull request of a single library does not contain the
+			// changes of the other libraries in the open pull request.
+			name:    "single library",
+			library: "lib-a",
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			remote := git.NewRemote(memory.NewStorage(), &gogitConfig.RemoteConfig{
+				Name: "origin",
+				URLs: []string{"https://github.com/googleapis/librarian.git"},
+			})
+			status := make(git.Status)
+			status["file.txt"] = &git.FileStatus{Worktree: git.Modified}
+			client := &mockGitHubClient{
+				createdPR:    &github.PullRequestMetadata{Number: 123, Repo: &github.Repository{Owner: "googleapis", Name: "librarian"}},
+				pullRequests: []*github.PullRequest{openPullRequest},
+			}
+			info := &commitInfo{
+				branch:   "main",
+				ghClient: client,
+				library:  test.library,
+				prType:   generate,
+				push:     true,
+				repo: &MockRepository{
+					Dir:          t.TempDir(),
+					AddAllStatus: status,
+					RemotesValue: []*git.Remote{remote},
+				},
+				state: &config.LibrarianState{},
+			}
+			if err := commitAndPush(t.Context(), info); err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(test.wantClosed, client.closedPullRequests); diff != "" {
+				t.Errorf("closed pull requests mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
 func TestCommitAndPush(t *testing.T) {
 	for _, test := range []struct {
 		name            string
@@ -40,8 +40,9 @@ func GetConventionalCommitsSinceLastRelease(repo gitrepo.Repository, library *co
 }
 
 // getConventionalCommitsSinceLastGeneration returns all conventional commits for
-// all API paths in given library since the last generation.
-func getConventionalCommitsSinceLastGeneration(repo gitrepo.Repository, library *config.LibraryState, lastGenCommit string) ([]*conventionalcommits.ConventionalCommit, error) {
+// the API paths in the given API source of a library since the last
+// generation. An empty source is the default API source.
+func getConventionalCommitsSinceLastGeneration(repo gitrepo.Repository, library *config.LibraryState, source, lastGenCommit string) ([]*conventionalcommits.ConventionalCommit, error) {
 	if lastGenCommit == "" {
 		slog.Info("the last generation commit is empty, skip fetching conventional commits", "library", library.ID)
 		return make([]*conventionalcommits.ConventionalCommit, 0), nil
@@ -49,6 +50,9 @@ func getConventionalCommitsSinceLastGeneration(repo gitrepo.Repository, library
 
 	apiPaths := make([]string, 0)
 	for _, oneAPI := range library.APIs {
+		if oneAPI.Source != source {
+			continue
+		}
 		apiPaths = append(apiPaths, oneAPI.Path)
 	}
 
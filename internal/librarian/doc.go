@@ -125,6 +125,51 @@ Flags:
 	  	supported. If not specified, will try to detect if the current working directory
 	  	is configured as a language repository.
 
+# lint
+
+The lint command checks the '.librarian/state.yaml' and
+'.librarian/config.yaml' files of a language repository for problems that
+are otherwise only caught at generation or release time. It runs offline and
+reports every problem found, with its file and line position.
+
+The following checks are performed:
+
+  - each library in state.yaml is valid, as checked before any command runs.
+  - source roots of different libraries do not overlap.
+  - no preserve_regex of a library is also one of its remove_regex entries, or
+    preserves every file under its source roots which a remove_regex matches.
+  - the tag formats of different libraries cannot produce the same tag.
+  - every API path, and its service config, exists in the API source. These
+    checks are skipped unless '--api-source' is a local directory.
+  - every library and dependency listed in config.yaml exists in state.yaml,
+    and the dependencies do not form a cycle.
+
+The command exits with an error if any problem is found.
+
+Examples:
+
+	# Lint the repository in the current directory.
+	librarian lint
+
+	# Also check API paths against a local googleapis checkout.
+	librarian lint --repo=/path/to/repo --api-source=/path/to/googleapis
+
+Usage:
+
+	librarian lint [flags]
+
+Flags:
+
+	-api-source string
+	  	The location of an API specification repository.
+	  	Can be a remote URL or a local file path. (default "https://github.com/googleapis/googleapis")
+	-repo string
+	  	Code repository where the generated code will reside. Can be a remote
+	  	in the format of a remote URL such as https://github.com/{owner}/{repo} or a
+	  	local file path like /path/to/repo. Both absolute and relative paths are
+	  	supported. If not specified, will try to detect if the current working directory
+	  	is configured as a language repository.
+
 # release
 
 Manages releases of libraries.
@@ -138,6 +183,71 @@ Commands:
 	init                       initiates a release by creating a release pull request.
 	tag-and-release            tags and creates a GitHub release for a merged pull request.
 
+# update-image
+
+The update-image command rolls the language container image used by a
+repository. It records the image given by '--image' in '.librarian/state.yaml'
+and regenerates every library, except those with 'generate_blocked' set in
+'.librarian/config.yaml', with the new image.
+
+The pull request created by the command lists the result of regenerating each
+library, including the error of every library which failed to generate. As the
+libraries are regenerated at the latest commit of the API sources, it also
+lists the API changes since their last generation, like a generate pull
+request, so that they appear in the release notes.
+
+If more than '--max-failure-percent' percent of the regenerated libraries fail,
+the image in state.yaml and the source roots of the regenerated libraries are
+restored, no commit is created and the command exits with an error.
+
+Examples:
+
+	# Regenerate all libraries with a new image and create a pull request.
+	librarian update-image --image=us-central1-docker.pkg.dev/cloud-sdk-librarian-prod/images-prod/go:v2 --push
+
+	# Allow up to 10% of the libraries to fail.
+	librarian update-image --image=example.com/go:v2 --max-failure-percent=10 --push
+
+Usage:
+
+	librarian update-image --image=<image> [flags]
+
+Flags:
+
+	-api-source string
+	  	The location of an API specification repository.
+	  	Can be a remote URL or a local file path. (default "https://github.com/googleapis/googleapis")
+	-branch string
+	  	The branch to use with remote code repositories. This is used to specify
+	  	which branch to clone and which branch to use as the base for a pull
+	  	request. (default "main")
+	-build
+	  	If true, Librarian will build each generated library by invoking the
+	  	language-specific container.
+	-host-mount string
+	  	For use when librarian is running in a container. A mapping of a
+	  	directory from the host to the container, in the format
+	  	<host-mount>:<local-mount>.
+	-image string
+	  	Language specific image used to invoke code generation and releasing.
+	  	If not specified, the image configured in the state.yaml is used.
+	-max-failure-percent int
+	  	The percentage of libraries which may fail to generate with the new image
+	  	before the image update is rolled back. (default 50)
+	-output string
+	  	Working directory root. When this is not specified, a working directory
+	  	will be created in /tmp.
+	-push
+	  	If true, Librarian will create a commit and a pull request for the changes.
+	  	A GitHub token with push access must be provided via the
+	  	LIBRARIAN_GITHUB_TOKEN environment variable.
+	-repo string
+	  	Code repository where the generated code will reside. Can be a remote
+	  	in the format of a remote URL such as https://github.com/{owner}/{repo} or a
+	  	local file path like /path/to/repo. Both absolute and relative paths are
+	  	supported. If not specified, will try to detect if the current working directory
+	  	is configured as a language repository.
+
 # version
 
 Version prints version information for the librarian binary.
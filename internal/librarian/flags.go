@@ -44,6 +44,14 @@ func addFlagCommit(fs *flag.FlagSet, cfg *config.Config) {
 a pull request. This flag is ignored if push is set to true.`)
 }
 
+func addFlagDryRun(fs *flag.FlagSet, cfg *config.Config) {
+	fs.BoolVar(&cfg.DryRun, "dry-run", false,
+		`If true, the language-specific container only checks that each released
+library can be published, without publishing it, and no tags, releases,
+comments or labels are written to GitHub. Requires the --publish flag to be
+specified.`)
+}
+
 func addFlagGitHubAPIEndpoint(fs *flag.FlagSet, cfg *config.Config) {
 	fs.StringVar(&cfg.GitHubAPIEndpoint, "github-api-endpoint", "",
 		`The GitHub API endpoint to use for all GitHub API operations.
@@ -76,6 +84,12 @@ func addFlagLibraryVersion(fs *flag.FlagSet, cfg *config.Config) {
 version for a library. Requires the --library flag to be specified.`)
 }
 
+func addFlagMaxFailurePercent(fs *flag.FlagSet, cfg *config.Config) {
+	fs.IntVar(&cfg.MaxFailurePercent, "max-failure-percent", 50,
+		`The percentage of libraries which may fail to generate with the new image
+before the image update is rolled back.`)
+}
+
 func addFlagPR(fs *flag.FlagSet, cfg *config.Config) {
 	fs.StringVar(&cfg.PullRequest, "pr", "",
 		`The URL of a pull request to operate on.
@@ -84,6 +98,13 @@ If not specified, will search for all merged pull requests with the label
 "release:pending" in the last 30 days.`)
 }
 
+func addFlagPublish(fs *flag.FlagSet, cfg *config.Config) {
+	fs.BoolVar(&cfg.Publish, "publish", false,
+		`If true, Librarian will publish each released library to its package
+registry by invoking the language-specific container, and comment the result
+on the release pull request.`)
+}
+
 func addFlagPush(fs *flag.FlagSet, cfg *config.Config) {
 	fs.BoolVar(&cfg.Push, "push", false,
 		`If true, Librarian will create a commit and a pull request for the changes.
@@ -91,6 +112,13 @@ A GitHub token with push access must be provided via the
 LIBRARIAN_GITHUB_TOKEN environment variable.`)
 }
 
+func addFlagReleaseArtifacts(fs *flag.FlagSet, cfg *config.Config) {
+	fs.BoolVar(&cfg.ReleaseArtifacts, "release-artifacts", false,
+		`If true, Librarian will package the release artifacts of each released
+library by invoking the language-specific container, and attach them to
+the GitHub release along with a SHA256SUMS file.`)
+}
+
 func addFlagRepo(fs *flag.FlagSet, cfg *config.Config) {
 	fs.StringVar(&cfg.Repo, "repo", "",
 		`Code repository where the generated code will reside. Can be a remote
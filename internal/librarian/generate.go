@@ -36,6 +36,7 @@ type generateRunner struct {
 	apiSource       string
 	branch          string
 	build           bool
+	capabilities    *docker.Capabilities
 	commit          bool
 	containerClient ContainerClient
 	ghClient        GitHubClient
@@ -45,6 +46,7 @@ type generateRunner struct {
 	push            bool
 	repo            gitrepo.Repository
 	sourceRepo      gitrepo.Repository
+	sourceRepos     map[string]gitrepo.Repository
 	state           *config.LibrarianState
 	librarianConfig *config.LibrarianConfig
 	workRoot        string
@@ -69,6 +71,7 @@ func newGenerateRunner(cfg *config.Config) (*generateRunner, error) {
 		push:            cfg.Push,
 		repo:            runner.repo,
 		sourceRepo:      runner.sourceRepo,
+		sourceRepos:     runner.sourceRepos,
 		state:           runner.state,
 		librarianConfig: runner.librarianConfig,
 		workRoot:        runner.workRoot,
@@ -85,21 +88,43 @@ func (r *generateRunner) run(ctx context.Context) error {
 	if err := os.Mkdir(outputDir, 0755); err != nil {
 		return fmt.Errorf("failed to make output directory, %s: %w", outputDir, err)
 	}
+	if err := r.describeContainer(ctx); err != nil {
+		return err
+	}
+	// Keep the state before generation, to split the changes into several
+	// pull requests.
+	var baseState *config.LibrarianState
+	if splitGenerationPullRequests(r.librarianConfig) {
+		var err error
+		baseState, err = parseLibrarianState(filepath.Join(r.repo.GetDir(), config.LibrarianDir, librarianStateFile), "")
+		if err != nil {
+			return err
+		}
+	}
 	// The last generated commit is changed after library generation,
 	// use this map to keep the mapping from library id to commit sha before the
 	// generation since we need these commits to create pull request body.
 	idToCommits := make(map[string]string, 0)
-	var failedLibraries []string
+	// The same mapping for each additional API source, keyed by the source
+	// name.
+	sourceCommits := make(map[string]map[string]string)
+	var (
+		failedLibraries []string
+		singleLibrary   string
+	)
 	if r.api != "" || r.library != "" {
 		libraryID := r.library
 		if libraryID == "" {
 			libraryID = findLibraryIDByAPIPath(r.state, r.api)
 		}
+		singleLibrary = libraryID
+		oldSourceCommits := r.lastGeneratedSourceCommits(libraryID)
 		oldCommit, err := r.generateSingleLibrary(ctx, libraryID, outputDir)
 		if err != nil {
 			return err
 		}
 		idToCommits[libraryID] = oldCommit
+		addSourceCommits(sourceCommits, libraryID, oldSourceCommits)
 	} else {
 		succeededGenerations := 0
 		failedGenerations := 0
@@ -113,6 +138,7 @@ func (r *generateRunner) run(ctx context.Context) error {
 					continue
 				}
 			}
+			oldSourceCommits := r.lastGeneratedSourceCommits(library.ID)
 			oldCommit, err := r.generateSingleLibrary(ctx, library.ID, outputDir)
 			if err != nil {
 				slog.Error("failed to generate library", "id", library.ID, "err", err)
@@ -122,6 +148,7 @@ func (r *generateRunner) run(ctx context.Context) error {
 				// Only add the mapping if library generation is successful so that
 				// failed library will not appear in generation PR body.
 				idToCommits[library.ID] = oldCommit
+				addSourceCommits(sourceCommits, library.ID, oldSourceCommits)
 				succeededGenerations++
 			}
 		}
@@ -149,10 +176,16 @@ func (r *generateRunner) run(ctx context.Context) error {
 		failedLibraries: failedLibraries,
 		ghClient:        r.ghClient,
 		idToCommits:     idToCommits,
+		library:         singleLibrary,
 		prType:          generate,
 		push:            r.push,
 		repo:            r.repo,
 		sourceRepo:      r.sourceRepo,
+		sourceRepos:     r.sourceRepos,
+		sourceCommits:   sourceCommits,
+		apiSource:       r.apiSource,
+		baseState:       baseState,
+		librarianConfig: r.librarianConfig,
 		state:           r.state,
 	}
 
@@ -174,6 +207,9 @@ func (r *generateRunner) run(ctx context.Context) error {
 // Returns the last generated commit before the generation and error, if any.
 func (r *generateRunner) generateSingleLibrary(ctx context.Context, libraryID, outputDir string) (string, error) {
 	if r.needsConfigure() {
+		if !r.supports(docker.CommandConfigure) {
+			return "", fmt.Errorf("image %s does not support the %s command, cannot configure library %s", r.image, docker.CommandConfigure, r.library)
+		}
 		slog.Info("library not configured, start initial configuration", "library", r.library)
 		configuredLibraryID, err := r.runConfigureCommand(ctx)
 		if err != nil {
@@ -218,24 +254,89 @@ func (r *generateRunner) generateSingleLibrary(ctx context.Context, libraryID, o
 	return lastGenCommit, nil
 }
 
+// describeContainer asks the container for its capabilities and checks that
+// it supports generation.
+func (r *generateRunner) describeContainer(ctx context.Context) error {
+	capabilities, err := r.containerClient.Describe(ctx, &docker.DescribeRequest{
+		HostMount: r.hostMount,
+		RepoDir:   r.repo.GetDir(),
+	})
+	if err != nil {
+		return err
+	}
+	if !capabilities.Supports(docker.CommandGenerate) {
+		return fmt.Errorf("image %s does not support the %s command", r.image, docker.CommandGenerate)
+	}
+	r.capabilities = capabilities
+	return nil
+}
+
+// supports reports whether the container supports the given command. All
+// commands of the original contract are assumed to be supported until the
+// container has been asked for its capabilities.
+func (r *generateRunner) supports(command docker.Command) bool {
+	if r.capabilities == nil {
+		return docker.LegacyCapabilities().Supports(command)
+	}
+	return r.capabilities.Supports(command)
+}
+
 func (r *generateRunner) needsConfigure() bool {
 	return r.api != "" && r.library != "" && findLibraryByID(r.state, r.library) == nil
 }
 
 func (r *generateRunner) updateLastGeneratedCommitState(libraryID string) error {
-	hash, err := r.sourceRepo.HeadHash()
-	if err != nil {
-		return err
+	library := findLibraryByID(r.state, libraryID)
+	if library == nil {
+		return nil
 	}
-	for _, l := range r.state.Libraries {
-		if l.ID == libraryID {
-			l.LastGeneratedCommit = hash
-			break
+	if len(r.sourceRepos) == 0 || library.UsesAPISource("") {
+		hash, err := r.sourceRepo.HeadHash()
+		if err != nil {
+			return err
 		}
+		library.LastGeneratedCommit = hash
+	}
+	for name, repo := range r.sourceRepos {
+		if !library.UsesAPISource(name) {
+			continue
+		}
+		hash, err := repo.HeadHash()
+		if err != nil {
+			return err
+		}
+		library.SetLastGeneratedCommitFor(name, hash)
 	}
 	return nil
 }
 
+// lastGeneratedSourceCommits returns the last generated commit of the library
+// for each additional API source it uses.
+func (r *generateRunner) lastGeneratedSourceCommits(libraryID string) map[string]string {
+	library := findLibraryByID(r.state, libraryID)
+	if library == nil {
+		return nil
+	}
+	commits := map[string]string{}
+	for name := range r.sourceRepos {
+		if library.UsesAPISource(name) {
+			commits[name] = library.LastGeneratedCommitFor(name)
+		}
+	}
+	return commits
+}
+
+// addSourceCommits records the last generated commits of a library in
+// `sourceCommits`, keyed by API source name and then library ID.
+func addSourceCommits(sourceCommits map[string]map[string]string, libraryID string, commits map[string]string) {
+	for name, commit := range commits {
+		if sourceCommits[name] == nil {
+			sourceCommits[name] = map[string]string{}
+		}
+		sourceCommits[name][libraryID] = commit
+	}
+}
+
 // runGenerateCommand attempts to perform generation for an API. It then cleans the
 // destination directory and copies the newly generated files into it.
 //
@@ -247,13 +348,26 @@ func (r *generateRunner) runGenerateCommand(ctx context.Context, libraryID, outp
 		return "", err
 	}
 
+	var apiSources map[string]string
+	for name, repo := range r.sourceRepos {
+		dir, err := filepath.Abs(repo.GetDir())
+		if err != nil {
+			return "", err
+		}
+		if apiSources == nil {
+			apiSources = map[string]string{}
+		}
+		apiSources[name] = dir
+	}
+
 	generateRequest := &docker.GenerateRequest{
-		ApiRoot:   apiRoot,
-		HostMount: r.hostMount,
-		LibraryID: libraryID,
-		Output:    outputDir,
-		RepoDir:   r.repo.GetDir(),
-		State:     r.state,
+		ApiRoot:    apiRoot,
+		APISources: apiSources,
+		HostMount:  r.hostMount,
+		LibraryID:  libraryID,
+		Output:     outputDir,
+		RepoDir:    r.repo.GetDir(),
+		State:      r.state,
 	}
 	slog.Info("Performing generation for library", "id", libraryID, "outputDir", outputDir)
 	if err := r.containerClient.Generate(ctx, generateRequest); err != nil {
@@ -284,6 +398,10 @@ func (r *generateRunner) runBuildCommand(ctx context.Context, libraryID string)
 		slog.Info("Build flag not specified, skipping")
 		return nil
 	}
+	if !r.supports(docker.CommandBuild) {
+		slog.Info("Image does not support the build command, skipping", "image", r.image)
+		return nil
+	}
 	if libraryID == "" {
 		slog.Warn("Cannot perform build, missing library ID")
 		return nil
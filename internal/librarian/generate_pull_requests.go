@@ -0,0 +1,288 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package librarian
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"maps"
+	"path"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/googleapis/librarian/internal/config"
+	"github.com/googleapis/librarian/internal/github"
+)
+
+// generationBranchRegex matches the branches of generation pull requests,
+// capturing the name of the pull request group, if any.
+var generationBranchRegex = regexp.MustCompile(`^librarian-\d{8}T\d{6}Z(?:-(.+))?$`)
+
+// pullRequestGroup is a group of libraries whose generated changes are
+// proposed in the same pull request.
+type pullRequestGroup struct {
+	name      string
+	libraries []string
+	labels    []string
+}
+
+// splitGenerationPullRequests reports whether the generated changes are split
+// into several pull requests.
+func splitGenerationPullRequests(librarianConfig *config.LibrarianConfig) bool {
+	return librarianConfig != nil &&
+		librarianConfig.GenerationPullRequests != nil &&
+		librarianConfig.GenerationPullRequests.Split != ""
+}
+
+// generationPullRequestGroups splits the libraries into the groups configured
+// in cfg. Libraries not in any configured group get a group each, named after
+// the library.
+func generationPullRequestGroups(cfg *config.GenerationPullRequests, libraryIDs []string) []*pullRequestGroup {
+	pending := map[string]bool{}
+	for _, id := range libraryIDs {
+		pending[id] = true
+	}
+	var groups []*pullRequestGroup
+	if cfg.Split == config.SplitByGroup {
+		for _, g := range cfg.Groups {
+			group := &pullRequestGroup{name: g.Name, labels: g.Labels}
+			for _, id := range g.Libraries {
+				if pending[id] {
+					group.libraries = append(group.libraries, id)
+					delete(pending, id)
+				}
+			}
+			if len(group.libraries) != 0 {
+				groups = append(groups, group)
+			}
+		}
+	}
+	for _, id := range slices.Sorted(maps.Keys(pending)) {
+		groups = append(groups, &pullRequestGroup{name: id, libraries: []string{id}})
+	}
+	return groups
+}
+
+// commitAndPushGenerationGroups creates a commit, and a pull request, for each
+// group of generated libraries.
+//
+// Each commit is created in its own branch, starting at the current commit of
+// the language repository. It only contains the changes in the source roots of
+// the libraries in the group, and their updated state.
+func commitAndPushGenerationGroups(ctx context.Context, info *commitInfo) error {
+	repo := info.repo
+	baseCommit, err := repo.HeadHash()
+	if err != nil {
+		return err
+	}
+	libraryIDs := slices.Concat(slices.Collect(maps.Keys(info.idToCommits)), info.failedLibraries)
+	groups := generationPullRequestGroups(info.librarianConfig.GenerationPullRequests, libraryIDs)
+
+	datetimeNow := formatTimestamp(time.Now())
+	var (
+		gitHubRepo   *github.Repository
+		pullRequests []string
+		closeErrs    []error
+	)
+	for _, group := range groups {
+		if err := repo.CheckoutCommit(baseCommit); err != nil {
+			return err
+		}
+		if err := saveLibrarianState(repo.GetDir(), groupState(info.baseState, info.state, group.libraries)); err != nil {
+			return err
+		}
+		status, err := repo.AddPaths(groupPaths(info, group))
+		if err != nil {
+			return err
+		}
+		if !hasStagedChanges(status) {
+			slog.Info("No changes to commit for pull request group, skipping", "group", group.name)
+			continue
+		}
+
+		branch := fmt.Sprintf("librarian-%s-%s", datetimeNow, group.name)
+		if err := repo.CreateBranchAndCheckout(branch); err != nil {
+			return err
+		}
+		if err := repo.Commit(info.commitMessage); err != nil {
+			return err
+		}
+		if err := repo.Push(branch); err != nil {
+			return err
+		}
+		if !info.push {
+			slog.Info("Push flag is not specified, skipping pull request creation", "group", group.name)
+			continue
+		}
+
+		if gitHubRepo == nil {
+			gitHubRepo, err = github.FetchGitHubRepoFromRemote(repo)
+			if err != nil {
+				return err
+			}
+		}
+		title := fmt.Sprintf("chore: librarian %s pull request for %s: %s", info.prType, group.name, datetimeNow)
+		groupInfo := scopeCommitInfo(info, group.libraries)
+		prBody, err := formatGenerationPRBody(generationSources(groupInfo), info.state, groupInfo.failedLibraries)
+		if err != nil {
+			return fmt.Errorf("failed to create pull request body for %s: %w", group.name, err)
+		}
+		pullRequestMetadata, err := info.ghClient.CreatePullRequest(ctx, gitHubRepo, branch, info.branch, title, prBody)
+		if err != nil {
+			return fmt.Errorf("failed to create pull request for %s: %w", group.name, err)
+		}
+		labels := slices.Concat(info.pullRequestLabels, group.labels)
+		if err := addLabelsToPullRequest(ctx, info.ghClient, labels, pullRequestMetadata); err != nil {
+			return err
+		}
+		// Failing to close the superseded pull requests of a group does not
+		// prevent creating the pull requests of the other groups.
+		if supersedesPullRequests(info, group.name) {
+			if err := closeSupersededPullRequests(ctx, info.ghClient, info.branch, group.name, pullRequestMetadata); err != nil {
+				slog.Error("failed to close superseded pull requests", "group", group.name, "err", err)
+				closeErrs = append(closeErrs, fmt.Errorf("group %s: %w", group.name, err))
+			}
+		}
+		pullRequests = append(pullRequests, fmt.Sprintf("#%d (%s)", pullRequestMetadata.Number, group.name))
+	}
+
+	// Leave the state of all the libraries in the working tree.
+	if err := saveLibrarianState(repo.GetDir(), info.state); err != nil {
+		return err
+	}
+	slog.Info("created generation pull requests", "count", len(pullRequests), "pull_requests", strings.Join(pullRequests, ", "))
+	if len(closeErrs) != 0 {
+		return fmt.Errorf("failed to close superseded pull requests: %w", errors.Join(closeErrs...))
+	}
+	return nil
+}
+
+// closeSupersededPullRequests closes the open generation pull requests into
+// baseBranch for the same group as the replacement pull request, commenting
+// with a link to the replacement. An empty group matches the pull requests
+// containing all the generated libraries.
+//
+// A failure to close one pull request does not prevent closing the others,
+// all the failures are returned.
+func closeSupersededPullRequests(ctx context.Context, ghClient GitHubClient, baseBranch, group string, replacement *github.PullRequestMetadata) error {
+	query := fmt.Sprintf("is:pr is:open base:%s in:title \"chore: librarian %s pull request\"", baseBranch, generate)
+	prs, err := ghClient.SearchPullRequests(ctx, query)
+	if err != nil {
+		return fmt.Errorf("failed to search superseded pull requests: %w", err)
+	}
+	var errs []error
+	for _, pr := range prs {
+		if pr.GetNumber() == replacement.Number || pr.GetState() != "open" || pr.GetBase().GetRef() != baseBranch {
+			continue
+		}
+		match := generationBranchRegex.FindStringSubmatch(pr.GetHead().GetRef())
+		if match == nil || match[1] != group {
+			continue
+		}
+		slog.Info("Closing superseded pull request", "number", pr.GetNumber(), "replacement", replacement.Number)
+		comment := fmt.Sprintf("Superseded by #%d.", replacement.Number)
+		if err := ghClient.CreateIssueComment(ctx, pr.GetNumber(), comment); err != nil {
+			errs = append(errs, fmt.Errorf("failed to comment on superseded pull request %d: %w", pr.GetNumber(), err))
+			continue
+		}
+		if err := ghClient.ClosePullRequest(ctx, pr.GetNumber()); err != nil {
+			errs = append(errs, fmt.Errorf("failed to close superseded pull request %d: %w", pr.GetNumber(), err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// groupState returns the state of the repository with only the libraries in
+// the group updated from current, and the other libraries as in base.
+func groupState(base, current *config.LibrarianState, libraryIDs []string) *config.LibrarianState {
+	state := *base
+	state.Libraries = nil
+	for _, library := range base.Libraries {
+		if slices.Contains(libraryIDs, library.ID) {
+			if updated := findLibraryByID(current, library.ID); updated != nil {
+				library = updated
+			}
+		}
+		state.Libraries = append(state.Libraries, library)
+	}
+	// Libraries configured during generation are not in the base state.
+	for _, library := range current.Libraries {
+		if slices.Contains(libraryIDs, library.ID) && findLibraryByID(base, library.ID) == nil {
+			state.Libraries = append(state.Libraries, library)
+		}
+	}
+	return &state
+}
+
+// groupPaths returns the paths with changes for the libraries in the group:
+// their source roots and the state file. The global files are included for
+// libraries configured during generation, as configuring a library may change
+// them.
+func groupPaths(info *commitInfo, group *pullRequestGroup) []string {
+	paths := []string{path.Join(config.LibrarianDir, librarianStateFile)}
+	for _, id := range group.libraries {
+		library := findLibraryByID(info.state, id)
+		if library == nil {
+			continue
+		}
+		paths = append(paths, library.SourceRoots...)
+		if findLibraryByID(info.baseState, id) == nil && info.librarianConfig != nil {
+			for _, globalFile := range info.librarianConfig.GlobalFilesAllowlist {
+				paths = append(paths, globalFile.Path)
+			}
+		}
+	}
+	return paths
+}
+
+// scopeCommitInfo returns a copy of info limited to the given libraries.
+func scopeCommitInfo(info *commitInfo, libraryIDs []string) *commitInfo {
+	scoped := *info
+	scoped.idToCommits = map[string]string{}
+	scoped.sourceCommits = map[string]map[string]string{}
+	scoped.failedLibraries = nil
+	for _, id := range libraryIDs {
+		if commit, ok := info.idToCommits[id]; ok {
+			scoped.idToCommits[id] = commit
+		}
+		for source, commits := range info.sourceCommits {
+			if commit, ok := commits[id]; ok {
+				if scoped.sourceCommits[source] == nil {
+					scoped.sourceCommits[source] = map[string]string{}
+				}
+				scoped.sourceCommits[source][id] = commit
+			}
+		}
+		if slices.Contains(info.failedLibraries, id) {
+			scoped.failedLibraries = append(scoped.failedLibraries, id)
+		}
+	}
+	return &scoped
+}
+
+// hasStagedChanges reports whether any file has changes in the index.
+func hasStagedChanges(status git.Status) bool {
+	for _, fileStatus := range status {
+		if fileStatus.Staging != git.Unmodified && fileStatus.Staging != git.Untracked {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,362 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package librarian
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/go-git/go-git/v5"
+	gogitConfig "github.com/go-git/go-git/v5/config"
+	"github.com/go-git/go-git/v5/storage/memory"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	gh "github.com/google/go-github/v69/github"
+	"github.com/googleapis/librarian/internal/config"
+	"github.com/googleapis/librarian/internal/github"
+	"gopkg.in/yaml.v3"
+)
+
+func TestGenerationPullRequestGroups(t *testing.T) {
+	for _, test := range []struct {
+		name       string
+		cfg        *config.GenerationPullRequests
+		libraryIDs []string
+		want       []*pullRequestGroup
+	}{
+		{
+			name:       "split by library",
+			cfg:        &config.GenerationPullRequests{Split: config.SplitByLibrary},
+			libraryIDs: []string{"lib-b", "lib-a"},
+			want: []*pullRequestGroup{
+				{name: "lib-a", libraries: []string{"lib-a"}},
+				{name: "lib-b", libraries: []string{"lib-b"}},
+			},
+		},
+		{
+			name: "split by group",
+			cfg: &config.GenerationPullRequests{
+				Split: config.SplitByGroup,
+				Groups: []*config.PullRequestGroup{
+					{Name: "empty", Libraries: []string{"lib-z"}},
+					{Name: "storage", Libraries: []string{"storage-b", "storage-a"}, Labels: []string{"api: storage"}},
+				},
+			},
+			libraryIDs: []string{"storage-a", "other", "storage-b"},
+			want: []*pullRequestGroup{
+				{name: "storage", libraries: []string{"storage-b", "storage-a"}, labels: []string{"api: storage"}},
+				{name: "other", libraries: []string{"other"}},
+			},
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			got := generationPullRequestGroups(test.cfg, test.libraryIDs)
+			if diff := cmp.Diff(test.want, got, cmp.AllowUnexported(pullRequestGroup{})); diff != "" {
+				t.Errorf("generationPullRequestGroups() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestGroupState(t *testing.T) {
+	base := &config.LibrarianState{
+		Image: "image:v1",
+		Libraries: []*config.LibraryState{
+			{ID: "lib-a", LastGeneratedCommit: "old-a"},
+			{ID: "lib-b", LastGeneratedCommit: "old-b"},
+		},
+	}
+	current := &config.LibrarianState{
+		Image: "image:v1",
+		Libraries: []*config.LibraryState{
+			{ID: "lib-a", LastGeneratedCommit: "new-a"},
+			{ID: "lib-b", LastGeneratedCommit: "new-b"},
+			{ID: "lib-c", LastGeneratedCommit: "new-c"},
+		},
+	}
+	got := groupState(base, current, []string{"lib-b", "lib-c"})
+	want := &config.LibrarianState{
+		Image: "image:v1",
+		Libraries: []*config.LibraryState{
+			{ID: "lib-a", LastGeneratedCommit: "old-a"},
+			{ID: "lib-b", LastGeneratedCommit: "new-b"},
+			{ID: "lib-c", LastGeneratedCommit: "new-c"},
+		},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("groupState() mismatch (-want +got):\n%s", diff)
+	}
+	if base.Libraries[1].LastGeneratedCommit != "old-b" {
+		t.Errorf("groupState() should not modify the base state")
+	}
+}
+
+func TestCommitAndPushGenerationGroups(t *testing.T) {
+	repoDir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(repoDir, config.LibrarianDir), 0755); err != nil {
+		t.Fatal(err)
+	}
+	remote := git.NewRemote(memory.NewStorage(), &gogitConfig.RemoteConfig{
+		Name: "origin",
+		URLs: []string{"https://github.com/googleapis/librarian.git"},
+	})
+	repo := &MockRepository{
+		Dir:                  repoDir,
+		HeadHashValue:        "base-commit",
+		RemotesValue:         []*git.Remote{remote},
+		AddPathsChangedFiles: []string{"a/file.go", "c/file.go"},
+	}
+	base := &config.LibrarianState{
+		Image: "image:v1",
+		Libraries: []*config.LibraryState{
+			{ID: "lib-a", SourceRoots: []string{"a"}},
+			{ID: "lib-b", SourceRoots: []string{"b"}},
+			{ID: "lib-c", SourceRoots: []string{"c"}},
+		},
+	}
+	state := &config.LibrarianState{
+		Image: "image:v1",
+		Libraries: []*config.LibraryState{
+			{ID: "lib-a", SourceRoots: []string{"a"}, LastGeneratedCommit: "new"},
+			{ID: "lib-b", SourceRoots: []string{"b"}, LastGeneratedCommit: "new"},
+			{ID: "lib-c", SourceRoots: []string{"c"}, LastGeneratedCommit: "new"},
+		},
+	}
+	client := &mockGitHubClient{
+		createdPR: &github.PullRequestMetadata{Number: 123, Repo: &github.Repository{Owner: "googleapis", Name: "librarian"}},
+	}
+	info := &commitInfo{
+		branch:        "main",
+		commitMessage: "feat: generate libraries",
+		ghClient:      client,
+		idToCommits:   map[string]string{"lib-a": "", "lib-b": "", "lib-c": ""},
+		librarianConfig: &config.LibrarianConfig{
+			GenerationPullRequests: &config.GenerationPullRequests{
+				Split: config.SplitByGroup,
+				Groups: []*config.PullRequestGroup{
+					{Name: "ab", Libraries: []string{"lib-a", "lib-b"}, Labels: []string{"generated"}},
+				},
+			},
+		},
+		prType:     generate,
+		push:       true,
+		repo:       repo,
+		sourceRepo: &MockRepository{},
+		baseState:  base,
+		state:      state,
+	}
+	if err := commitAndPush(context.Background(), info); err != nil {
+		t.Fatal(err)
+	}
+
+	wantAddPaths := [][]string{
+		{".librarian/state.yaml", "a", "b"},
+		{".librarian/state.yaml", "c"},
+	}
+	if diff := cmp.Diff(wantAddPaths, repo.AddPathsCalls); diff != "" {
+		t.Errorf("AddPaths() calls mismatch (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff([]string{"base-commit", "base-commit"}, repo.CheckoutCommitCalls); diff != "" {
+		t.Errorf("CheckoutCommit() calls mismatch (-want +got):\n%s", diff)
+	}
+	if len(repo.CreatedBranches) != 2 ||
+		!strings.HasSuffix(repo.CreatedBranches[0], "-ab") ||
+		!strings.HasSuffix(repo.CreatedBranches[1], "-lib-c") {
+		t.Errorf("unexpected branches %v", repo.CreatedBranches)
+	}
+	if repo.CommitCalls != 2 {
+		t.Errorf("Commit() calls = %d, want 2", repo.CommitCalls)
+	}
+	if client.createPullRequestCalls != 2 {
+		t.Errorf("CreatePullRequest() calls = %d, want 2", client.createPullRequestCalls)
+	}
+	if diff := cmp.Diff([]string{"generated"}, client.labels); diff != "" {
+		t.Errorf("labels mismatch (-want +got):\n%s", diff)
+	}
+	// The working tree keeps the state of all the libraries.
+	data, err := os.ReadFile(filepath.Join(repoDir, config.LibrarianDir, librarianStateFile))
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := &config.LibrarianState{}
+	if err := yaml.Unmarshal(data, got); err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(state, got, cmpopts.EquateEmpty()); diff != "" {
+		t.Errorf("state mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestCommitAndPushGenerationGroupsCloseError(t *testing.T) {
+	repoDir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(repoDir, config.LibrarianDir), 0755); err != nil {
+		t.Fatal(err)
+	}
+	remote := git.NewRemote(memory.NewStorage(), &gogitConfig.RemoteConfig{
+		Name: "origin",
+		URLs: []string{"https://github.com/googleapis/librarian.git"},
+	})
+	repo := &MockRepository{
+		Dir:                  repoDir,
+		HeadHashValue:        "base-commit",
+		RemotesValue:         []*git.Remote{remote},
+		AddPathsChangedFiles: []string{"a/file.go", "b/file.go"},
+	}
+	state := &config.LibrarianState{
+		Image: "image:v1",
+		Libraries: []*config.LibraryState{
+			{ID: "lib-a", SourceRoots: []string{"a"}},
+			{ID: "lib-b", SourceRoots: []string{"b"}},
+		},
+	}
+	client := &mockGitHubClient{
+		createdPR:             &github.PullRequestMetadata{Number: 123, Repo: &github.Repository{Owner: "googleapis", Name: "librarian"}},
+		searchPullRequestsErr: errors.New("search error"),
+	}
+	info := &commitInfo{
+		branch:        "main",
+		commitMessage: "feat: generate libraries",
+		ghClient:      client,
+		idToCommits:   map[string]string{"lib-a": "", "lib-b": ""},
+		librarianConfig: &config.LibrarianConfig{
+			GenerationPullRequests: &config.GenerationPullRequests{Split: config.SplitByLibrary},
+		},
+		prType:     generate,
+		push:       true,
+		repo:       repo,
+		sourceRepo: &MockRepository{},
+		baseState:  state,
+		state:      state,
+	}
+	err := commitAndPush(context.Background(), info)
+	if err == nil || !strings.Contains(err.Error(), "failed to close superseded pull requests") {
+		t.Fatalf("commitAndPush() error = %v, want failure to close superseded pull requests", err)
+	}
+	// The failure to close the superseded pull requests of the first group
+	// does not prevent creating the pull request of the second group.
+	if client.createPullRequestCalls != 2 {
+		t.Errorf("CreatePullRequest() calls = %d, want 2", client.createPullRequestCalls)
+	}
+}
+
+func TestCloseSupersededPullRequests(t *testing.T) {
+	newPullRequest := func(number int, head, base string) *github.PullRequest {
+		return &github.PullRequest{
+			Number: gh.Ptr(number),
+			State:  gh.Ptr("open"),
+			Head:   &gh.PullRequestBranch{Ref: gh.Ptr(head)},
+			Base:   &gh.PullRequestBranch{Ref: gh.Ptr(base)},
+		}
+	}
+	pullRequests := []*github.PullRequest{
+		newPullRequest(1, "librarian-20250101T000000Z", "main"),
+		newPullRequest(2, "librarian-20250101T000000Z-storage", "main"),
+		newPullRequest(3, "librarian-20250102T000000Z-storage", "main"),
+		newPullRequest(4, "librarian-20250102T000000Z-storage", "preview"),
+		newPullRequest(5, "librarian-20250102T000000Z-storage-control", "main"),
+		newPullRequest(6, "feature-storage", "main"),
+		newPullRequest(10, "librarian-20250103T000000Z-storage", "main"),
+	}
+	replacement := &github.PullRequestMetadata{Number: 10}
+	for _, test := range []struct {
+		name             string
+		group            string
+		client           *mockGitHubClient
+		wantClosed       []int
+		wantCommentCalls int
+		wantCloseCalls   int
+		wantErrorMsgs    []string
+	}{
+		{
+			name:       "all libraries",
+			client:     &mockGitHubClient{pullRequests: pullRequests},
+			wantClosed: []int{1},
+		},
+		{
+			name:       "group",
+			group:      "storage",
+			client:     &mockGitHubClient{pullRequests: pullRequests},
+			wantClosed: []int{2, 3},
+		},
+		{
+			name:          "search error",
+			client:        &mockGitHubClient{searchPullRequestsErr: errors.New("search error")},
+			wantErrorMsgs: []string{"failed to search superseded pull requests"},
+		},
+		{
+			name:  "comment error continues",
+			group: "storage",
+			client: &mockGitHubClient{
+				pullRequests:          pullRequests,
+				createIssueCommentErr: errors.New("comment error"),
+			},
+			wantCommentCalls: 2,
+			wantErrorMsgs: []string{
+				"failed to comment on superseded pull request 2",
+				"failed to comment on superseded pull request 3",
+			},
+		},
+		{
+			name:  "close error continues",
+			group: "storage",
+			client: &mockGitHubClient{
+				pullRequests:        pullRequests,
+				closePullRequestErr: errors.New("close error"),
+			},
+			wantCommentCalls: 2,
+			wantCloseCalls:   2,
+			wantErrorMsgs: []string{
+				"failed to close superseded pull request 2",
+				"failed to close superseded pull request 3",
+			},
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			err := closeSupersededPullRequests(context.Background(), test.client, "main", test.group, replacement)
+			if len(test.wantErrorMsgs) != 0 {
+				if err == nil {
+					t.Fatalf("closeSupersededPullRequests() error = nil, want %q", test.wantErrorMsgs)
+				}
+				for _, want := range test.wantErrorMsgs {
+					if !strings.Contains(err.Error(), want) {
+						t.Errorf("closeSupersededPullRequests() error = %v, want %q", err, want)
+					}
+				}
+				if test.client.createIssueCommentCalls != test.wantCommentCalls {
+					t.Errorf("createIssueCommentCalls = %d, want %d", test.client.createIssueCommentCalls, test.wantCommentCalls)
+				}
+				if test.client.closePullRequestCalls != test.wantCloseCalls {
+					t.Errorf("closePullRequestCalls = %d, want %d", test.client.closePullRequestCalls, test.wantCloseCalls)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(test.wantClosed, test.client.closedPullRequests); diff != "" {
+				t.Errorf("closed pull requests mismatch (-want +got):\n%s", diff)
+			}
+			for _, number := range test.wantClosed {
+				if got, want := test.client.comments[number], "Superseded by #10."; got != want {
+					t.Errorf("comment on %d = %q, want %q", number, got, want)
+				}
+			}
+		})
+	}
+}
@@ -24,6 +24,7 @@ import (
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/googleapis/librarian/internal/config"
+	"github.com/googleapis/librarian/internal/docker"
 	"github.com/googleapis/librarian/internal/gitrepo"
 )
 
@@ -580,6 +581,91 @@ func TestGenerateScenarios(t *testing.T) {
 			wantBuildCalls:     1,
 			wantConfigureCalls: 1,
 		},
+		{
+			name:    "image does not support generate",
+			library: "some-library",
+			state: &config.LibrarianState{
+				Image: "gcr.io/test/image:v1.2.3",
+				Libraries: []*config.LibraryState{
+					{
+						ID:          "some-library",
+						APIs:        []*config.API{{Path: "some/api"}},
+						SourceRoots: []string{"src/a"},
+					},
+				},
+			},
+			container: &mockContainerClient{
+				capabilities: &docker.Capabilities{
+					ContractVersion: 1,
+					Commands:        []docker.Command{docker.CommandBuild},
+				},
+			},
+			ghClient:   &mockGitHubClient{},
+			wantErr:    true,
+			wantErrMsg: "does not support the generate command",
+		},
+		{
+			name:    "describe error",
+			library: "some-library",
+			state: &config.LibrarianState{
+				Image: "gcr.io/test/image:v1.2.3",
+				Libraries: []*config.LibraryState{
+					{
+						ID:          "some-library",
+						APIs:        []*config.API{{Path: "some/api"}},
+						SourceRoots: []string{"src/a"},
+					},
+				},
+			},
+			container: &mockContainerClient{
+				describeErr: errors.New("incompatible image"),
+			},
+			ghClient:   &mockGitHubClient{},
+			wantErr:    true,
+			wantErrMsg: "incompatible image",
+		},
+		{
+			name:    "image does not support configure",
+			api:     "some/api",
+			library: "some-library",
+			state: &config.LibrarianState{
+				Image: "gcr.io/test/image:v1.2.3",
+			},
+			container: &mockContainerClient{
+				capabilities: &docker.Capabilities{
+					ContractVersion: 1,
+					Commands:        []docker.Command{docker.CommandGenerate},
+				},
+			},
+			ghClient:   &mockGitHubClient{},
+			wantErr:    true,
+			wantErrMsg: "does not support the configure command",
+		},
+		{
+			name:    "image does not support build",
+			library: "some-library",
+			state: &config.LibrarianState{
+				Image: "gcr.io/test/image:v1.2.3",
+				Libraries: []*config.LibraryState{
+					{
+						ID:          "some-library",
+						APIs:        []*config.API{{Path: "some/api"}},
+						SourceRoots: []string{"src/a"},
+					},
+				},
+			},
+			container: &mockContainerClient{
+				wantLibraryGen: true,
+				capabilities: &docker.Capabilities{
+					ContractVersion: 1,
+					Commands:        []docker.Command{docker.CommandGenerate},
+				},
+			},
+			ghClient:          &mockGitHubClient{},
+			build:             true,
+			wantGenerateCalls: 1,
+			wantBuildCalls:    0,
+		},
 		{
 			name:    "generate single existing library by library id",
 			library: "some-library",
@@ -1050,3 +1136,36 @@ func TestUpdateLastGeneratedCommitState(t *testing.T) {
 		t.Errorf("updateState() got = %v, want %v", r.state.Libraries[0].LastGeneratedCommit, hash)
 	}
 }
+
+func TestUpdateLastGeneratedCommitStateWithAPISources(t *testing.T) {
+	t.Parallel()
+	sourceRepo := newTestGitRepo(t)
+	privateRepo := newTestGitRepo(t)
+	privateHash, err := privateRepo.HeadHash()
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := &generateRunner{
+		sourceRepo:  sourceRepo,
+		sourceRepos: map[string]gitrepo.Repository{"private": privateRepo},
+		state: &config.LibrarianState{
+			Libraries: []*config.LibraryState{
+				{
+					ID:                  "some-library",
+					LastGeneratedCommit: "old-commit",
+					APIs:                []*config.API{{Path: "example/api/v1", Source: "private"}},
+				},
+			},
+		},
+	}
+	if err := r.updateLastGeneratedCommitState("some-library"); err != nil {
+		t.Fatal(err)
+	}
+	library := r.state.Libraries[0]
+	if library.LastGeneratedCommit != "old-commit" {
+		t.Errorf("LastGeneratedCommit got = %v, want %v", library.LastGeneratedCommit, "old-commit")
+	}
+	if got := library.LastGeneratedCommitFor("private"); got != privateHash {
+		t.Errorf("LastGeneratedCommitFor() got = %v, want %v", got, privateHash)
+	}
+}
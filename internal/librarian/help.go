@@ -73,6 +73,32 @@ in '.librarian/state.yaml'.
 Example with build and push:
   SDK_LIBRARIAN_GITHUB_TOKEN=xxx librarian generate --push --build`
 
+	lintLongHelp = `The lint command checks the '.librarian/state.yaml' and
+'.librarian/config.yaml' files of a language repository for problems that
+are otherwise only caught at generation or release time. It runs offline and
+reports every problem found, with its file and line position.
+
+The following checks are performed:
+
+- each library in state.yaml is valid, as checked before any command runs.
+- source roots of different libraries do not overlap.
+- no preserve_regex of a library is also one of its remove_regex entries, or
+  preserves every file under its source roots which a remove_regex matches.
+- the tag formats of different libraries cannot produce the same tag.
+- every API path, and its service config, exists in the API source. These
+  checks are skipped unless '--api-source' is a local directory.
+- every library and dependency listed in config.yaml exists in state.yaml,
+  and the dependencies do not form a cycle.
+
+The command exits with an error if any problem is found.
+
+Examples:
+  # Lint the repository in the current directory.
+  librarian lint
+
+  # Also check API paths against a local googleapis checkout.
+  librarian lint --repo=/path/to/repo --api-source=/path/to/googleapis`
+
 	releaseInitLongHelp = `The 'release init' command is the primary entry point for initiating
 a new release. It automates the creation of a release pull request by parsing
 conventional commits, determining the next semantic version for each library,
@@ -120,6 +146,24 @@ This command's primary responsibilities are to:
 - Update the pull request's label from 'release:pending' to 'release:done' to
   mark the process as complete.
 
+With the '--release-artifacts' flag, the command also clones the repository,
+checks out the merge commit of each pull request, and invokes the 'package'
+command of the language container for each released library. The files
+written by the container are attached to the GitHub release, along with a
+SHA256SUMS file listing their SHA-256 checksums.
+
+With the '--publish' flag, the command invokes the 'publish' command of the
+language container for each released library, once all the GitHub releases
+of the pull request are created, and comments the result of each library on
+the pull request. The pull request is labeled 'release:done' even if some
+libraries failed to publish. To publish one of them again, run the command
+with '--pr' set to the pull request and '--library' set to the library: the
+existing tag and release are reused, and the labels are left unchanged.
+
+With '--dry-run', the container only checks that each library can be
+published, and nothing is written to GitHub: the tags, releases, release
+assets, comments and labels are only logged.
+
 You can target a specific merged pull request using the '--pr' flag. If no pull
 request is specified, the command will automatically search for and process all
 merged pull requests with the 'release:pending' label from the last 30 days.
@@ -129,5 +173,36 @@ Examples:
   librarian release tag-and-release --repo=https://github.com/googleapis/google-cloud-go --pr=https://github.com/googleapis/google-cloud-go/pull/123
 
   # Find and process all pending merged release PRs in a repository.
-  librarian release tag-and-release --repo=https://github.com/googleapis/google-cloud-go`
+  librarian release tag-and-release --repo=https://github.com/googleapis/google-cloud-go
+
+  # Also attach the release artifacts packaged by the language container.
+  librarian release tag-and-release --repo=https://github.com/googleapis/google-cloud-go --release-artifacts
+
+  # Check that the released libraries can be published, without publishing them.
+  librarian release tag-and-release --repo=https://github.com/googleapis/google-cloud-go --publish --dry-run
+
+  # Publish a library again after it failed to publish.
+  librarian release tag-and-release --repo=https://github.com/googleapis/google-cloud-go --pr=https://github.com/googleapis/google-cloud-go/pull/123 --publish --library=secretmanager`
+
+	updateImageLongHelp = `The update-image command rolls the language container image used by a
+repository. It records the image given by '--image' in '.librarian/state.yaml'
+and regenerates every library, except those with 'generate_blocked' set in
+'.librarian/config.yaml', with the new image.
+
+The pull request created by the command lists the result of regenerating each
+library, including the error of every library which failed to generate. As the
+libraries are regenerated at the latest commit of the API sources, it also
+lists the API changes since their last generation, like a generate pull
+request, so that they appear in the release notes.
+
+If more than '--max-failure-percent' percent of the regenerated libraries fail,
+the image in state.yaml and the source roots of the regenerated libraries are
+restored, no commit is created and the command exits with an error.
+
+Examples:
+  # Regenerate all libraries with a new image and create a pull request.
+  librarian update-image --image=us-central1-docker.pkg.dev/cloud-sdk-librarian-prod/images-prod/go:v2 --push
+
+  # Allow up to 10% of the libraries to fail.
+  librarian update-image --image=example.com/go:v2 --max-failure-percent=10 --push`
 )
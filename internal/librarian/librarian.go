@@ -56,7 +56,9 @@ func newLibrarianCommand() *cli.Command {
 		Long:      librarianLongHelp,
 		Commands: []*cli.Command{
 			newCmdGenerate(),
+			newCmdLint(),
 			cmdRelease,
+			newCmdUpdateImage(),
 			cmdVersion,
 		},
 	}
@@ -97,6 +99,63 @@ func newCmdGenerate() *cli.Command {
 	return cmdGenerate
 }
 
+func newCmdLint() *cli.Command {
+	cmdLint := &cli.Command{
+		Short:     "lint checks state.yaml and config.yaml for consistency problems",
+		UsageLine: "librarian lint [flags]",
+		Long:      lintLongHelp,
+		Action: func(ctx context.Context, cmd *cli.Command) error {
+			if err := cmd.Config.SetDefaults(); err != nil {
+				return fmt.Errorf("failed to initialize config: %w", err)
+			}
+			if _, err := cmd.Config.IsValid(); err != nil {
+				return fmt.Errorf("failed to validate config: %s", err)
+			}
+			runner, err := newLintRunner(cmd.Config)
+			if err != nil {
+				return err
+			}
+			return runner.run(ctx)
+		},
+	}
+	cmdLint.Init()
+	addFlagAPISource(cmdLint.Flags, cmdLint.Config)
+	addFlagRepo(cmdLint.Flags, cmdLint.Config)
+	return cmdLint
+}
+
+func newCmdUpdateImage() *cli.Command {
+	cmdUpdateImage := &cli.Command{
+		Short:     "update-image updates the language image and regenerates all libraries",
+		UsageLine: "librarian update-image --image=<image> [flags]",
+		Long:      updateImageLongHelp,
+		Action: func(ctx context.Context, cmd *cli.Command) error {
+			if err := cmd.Config.SetDefaults(); err != nil {
+				return fmt.Errorf("failed to initialize config: %w", err)
+			}
+			if _, err := cmd.Config.IsValid(); err != nil {
+				return fmt.Errorf("failed to validate config: %s", err)
+			}
+			runner, err := newUpdateImageRunner(cmd.Config)
+			if err != nil {
+				return err
+			}
+			return runner.run(ctx)
+		},
+	}
+	cmdUpdateImage.Init()
+	addFlagAPISource(cmdUpdateImage.Flags, cmdUpdateImage.Config)
+	addFlagBuild(cmdUpdateImage.Flags, cmdUpdateImage.Config)
+	addFlagHostMount(cmdUpdateImage.Flags, cmdUpdateImage.Config)
+	addFlagImage(cmdUpdateImage.Flags, cmdUpdateImage.Config)
+	addFlagMaxFailurePercent(cmdUpdateImage.Flags, cmdUpdateImage.Config)
+	addFlagRepo(cmdUpdateImage.Flags, cmdUpdateImage.Config)
+	addFlagBranch(cmdUpdateImage.Flags, cmdUpdateImage.Config)
+	addFlagWorkRoot(cmdUpdateImage.Flags, cmdUpdateImage.Config)
+	addFlagPush(cmdUpdateImage.Flags, cmdUpdateImage.Config)
+	return cmdUpdateImage
+}
+
 func newCmdTagAndRelease() *cli.Command {
 	cmdTagAndRelease := &cli.Command{
 		Short:     "tag-and-release tags and creates a GitHub release for a merged pull request.",
@@ -119,7 +178,15 @@ func newCmdTagAndRelease() *cli.Command {
 	cmdTagAndRelease.Init()
 	addFlagRepo(cmdTagAndRelease.Flags, cmdTagAndRelease.Config)
 	addFlagPR(cmdTagAndRelease.Flags, cmdTagAndRelease.Config)
+	addFlagLibrary(cmdTagAndRelease.Flags, cmdTagAndRelease.Config)
 	addFlagGitHubAPIEndpoint(cmdTagAndRelease.Flags, cmdTagAndRelease.Config)
+	addFlagReleaseArtifacts(cmdTagAndRelease.Flags, cmdTagAndRelease.Config)
+	addFlagPublish(cmdTagAndRelease.Flags, cmdTagAndRelease.Config)
+	addFlagDryRun(cmdTagAndRelease.Flags, cmdTagAndRelease.Config)
+	addFlagBranch(cmdTagAndRelease.Flags, cmdTagAndRelease.Config)
+	addFlagHostMount(cmdTagAndRelease.Flags, cmdTagAndRelease.Config)
+	addFlagImage(cmdTagAndRelease.Flags, cmdTagAndRelease.Config)
+	addFlagWorkRoot(cmdTagAndRelease.Flags, cmdTagAndRelease.Config)
 	return cmdTagAndRelease
 }
 
@@ -144,6 +211,7 @@ func newCmdInit() *cli.Command {
 	}
 	cmdInit.Init()
 	addFlagCommit(cmdInit.Flags, cmdInit.Config)
+	addFlagHostMount(cmdInit.Flags, cmdInit.Config)
 	addFlagPush(cmdInit.Flags, cmdInit.Config)
 	addFlagImage(cmdInit.Flags, cmdInit.Config)
 	addFlagLibrary(cmdInit.Flags, cmdInit.Config)
@@ -0,0 +1,413 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package librarian
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/googleapis/librarian/internal/config"
+	"gopkg.in/yaml.v3"
+)
+
+const lintCmdName = "lint"
+
+// lintProblem describes a single problem found by the lint command.
+type lintProblem struct {
+	// File is the path of the file containing the problem, relative to the
+	// root of the language repository.
+	File string
+	// Line is the 1-based line number of the problem, or 0 if the problem
+	// cannot be attributed to a specific line.
+	Line int
+	// Message describes the problem.
+	Message string
+}
+
+// String formats the problem as `file:line: message`, omitting the line if it
+// is not known.
+func (p *lintProblem) String() string {
+	if p.Line == 0 {
+		return fmt.Sprintf("%s: %s", p.File, p.Message)
+	}
+	return fmt.Sprintf("%s:%d: %s", p.File, p.Line, p.Message)
+}
+
+type lintRunner struct {
+	apiSource string
+	repoDir   string
+	out       io.Writer
+}
+
+func newLintRunner(cfg *config.Config) (*lintRunner, error) {
+	if isURL(cfg.Repo) {
+		return nil, fmt.Errorf("lint requires a local repository, got %s", cfg.Repo)
+	}
+	repoDir, err := filepath.Abs(cfg.Repo)
+	if err != nil {
+		return nil, err
+	}
+	apiSource := cfg.APISource
+	if isURL(apiSource) {
+		slog.Info("api source is not a local directory, skipping API checks", "api-source", apiSource)
+		apiSource = ""
+	}
+	return &lintRunner{
+		apiSource: apiSource,
+		repoDir:   repoDir,
+		out:       os.Stdout,
+	}, nil
+}
+
+func (r *lintRunner) run(ctx context.Context) error {
+	problems, err := r.lint()
+	if err != nil {
+		return err
+	}
+	for _, p := range problems {
+		fmt.Fprintln(r.out, p.String())
+	}
+	if len(problems) > 0 {
+		return fmt.Errorf("found %d problem(s)", len(problems))
+	}
+	slog.Info("no problems found")
+	return nil
+}
+
+// lint runs all checks on the state.yaml and config.yaml files of the
+// repository and returns the problems found, in file and line order.
+func (r *lintRunner) lint() ([]*lintProblem, error) {
+	statePath := filepath.Join(config.LibrarianDir, librarianStateFile)
+	stateDoc, err := readYAMLNode(filepath.Join(r.repoDir, statePath))
+	if err != nil {
+		return nil, err
+	}
+	if stateDoc == nil {
+		return nil, fmt.Errorf("%s not found in %s", statePath, r.repoDir)
+	}
+	state := &config.LibrarianState{}
+	if err := stateDoc.Decode(state); err != nil {
+		return nil, fmt.Errorf("failed to decode %s: %w", statePath, err)
+	}
+	stateNodes := libraryNodes(stateDoc)
+
+	var problems []*lintProblem
+	addStateProblem := func(line int, format string, args ...any) {
+		problems = append(problems, &lintProblem{File: statePath, Line: line, Message: fmt.Sprintf(format, args...)})
+	}
+
+	if err := state.Validate(); err != nil {
+		addStateProblem(documentLine(stateDoc), "%v", err)
+	}
+	lintSourceRoots(state, stateNodes, addStateProblem)
+	lintRegexes(state, stateNodes, r.repoDir, addStateProblem)
+	lintTagFormats(state, stateNodes, addStateProblem)
+	if r.apiSource != "" {
+		lintAPIs(state, stateNodes, r.apiSource, addStateProblem)
+	}
+
+	configPath := filepath.Join(config.LibrarianDir, librarianConfigFile)
+	configDoc, err := readYAMLNode(filepath.Join(r.repoDir, configPath))
+	if err != nil {
+		return nil, err
+	}
+	if configDoc != nil {
+		librarianConfig := &config.LibrarianConfig{}
+		if err := configDoc.Decode(librarianConfig); err != nil {
+			return nil, fmt.Errorf("failed to decode %s: %w", configPath, err)
+		}
+		addConfigProblem := func(line int, format string, args ...any) {
+			problems = append(problems, &lintProblem{File: configPath, Line: line, Message: fmt.Sprintf(format, args...)})
+		}
+		if err := librarianConfig.Validate(); err != nil {
+			addConfigProblem(documentLine(configDoc), "%v", err)
+		}
+		lintLibraryConfigs(state, librarianConfig, libraryNodes(configDoc), addConfigProblem)
+	}
+
+	slices.SortStableFunc(problems, func(a, b *lintProblem) int {
+		if c := strings.Compare(a.File, b.File); c != 0 {
+			return c
+		}
+		return a.Line - b.Line
+	})
+	return problems, nil
+}
+
+// lintSourceRoots reports source roots which are shared by, or nested within
+// the source roots of, more than one library.
+func lintSourceRoots(state *config.LibrarianState, nodes []*yaml.Node, report func(int, string, ...any)) {
+	for i, library := range state.Libraries {
+		for j := 0; j < i; j++ {
+			other := state.Libraries[j]
+			for k, root := range library.SourceRoots {
+				for _, otherRoot := range other.SourceRoots {
+					if !pathsOverlap(root, otherRoot) {
+						continue
+					}
+					report(listItemLine(nodeAt(nodes, i), "source_roots", k),
+						"source root %q of library %q overlaps with source root %q of library %q",
+						root, library.ID, otherRoot, other.ID)
+				}
+			}
+		}
+	}
+}
+
+// lintRegexes reports preserve_regex entries which make a remove_regex entry of
+// the same library ineffective: either the two are identical, or the
+// preserve_regex matches every file under the library's source roots which
+// the remove_regex matches. As preserve_regex takes precedence, such a
+// remove_regex never removes anything.
+//
+// Invalid regular expressions are reported by state validation, so libraries
+// with any are skipped here.
+func lintRegexes(state *config.LibrarianState, nodes []*yaml.Node, repoDir string, report func(int, string, ...any)) {
+	for i, library := range state.Libraries {
+		removeRegexps, err := compileRegexps(library.RemoveRegex)
+		if err != nil {
+			continue
+		}
+		preserveRegexps, err := compileRegexps(library.PreserveRegex)
+		if err != nil {
+			continue
+		}
+		files, err := sourceRootFiles(repoDir, library.SourceRoots)
+		if err != nil {
+			slog.Warn("failed to list files of source roots", "library", library.ID, "err", err)
+		}
+		for k, preserve := range preserveRegexps {
+			for j, remove := range removeRegexps {
+				if library.PreserveRegex[k] == library.RemoveRegex[j] {
+					report(listItemLine(nodeAt(nodes, i), "preserve_regex", k),
+						"preserve_regex %q of library %q is also a remove_regex, so it never removes anything",
+						library.PreserveRegex[k], library.ID)
+					break
+				}
+				removed := filterPathsByRegex(files, []*regexp.Regexp{remove})
+				if len(removed) > 0 && len(filterPathsByRegex(removed, []*regexp.Regexp{preserve})) == len(removed) {
+					report(listItemLine(nodeAt(nodes, i), "preserve_regex", k),
+						"preserve_regex %q of library %q preserves every file matched by remove_regex %q, so it never removes anything",
+						library.PreserveRegex[k], library.ID, library.RemoveRegex[j])
+				}
+			}
+		}
+	}
+}
+
+// sourceRootFiles returns the paths, relative to repoDir and slash-separated,
+// of the regular files under the given source roots. Source roots which do
+// not exist are skipped.
+func sourceRootFiles(repoDir string, sourceRoots []string) ([]string, error) {
+	var files []string
+	for _, root := range sourceRoots {
+		rootDir := filepath.Join(repoDir, root)
+		if _, err := os.Stat(rootDir); err != nil {
+			if errors.Is(err, os.ErrNotExist) {
+				continue
+			}
+			return nil, err
+		}
+		err := filepath.WalkDir(rootDir, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if !d.Type().IsRegular() {
+				return nil
+			}
+			relPath, err := filepath.Rel(repoDir, path)
+			if err != nil {
+				return err
+			}
+			files = append(files, filepath.ToSlash(relPath))
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return files, nil
+}
+
+// lintTagFormats reports libraries whose tag format produces the same tag as
+// another library for the same version.
+func lintTagFormats(state *config.LibrarianState, nodes []*yaml.Node, report func(int, string, ...any)) {
+	const probeVersion = "1.2.3"
+	seen := make(map[string]string)
+	for i, library := range state.Libraries {
+		tag := formatTag(library.TagFormat, library.ID, probeVersion)
+		if other, ok := seen[tag]; ok {
+			report(fieldLine(nodeAt(nodes, i), "tag_format"),
+				"tag format of library %q collides with library %q (both produce %q)",
+				library.ID, other, tag)
+			continue
+		}
+		seen[tag] = library.ID
+	}
+}
+
+// lintAPIs reports API paths and service configs which do not exist in the
+// API source directory.
+func lintAPIs(state *config.LibrarianState, nodes []*yaml.Node, apiSource string, report func(int, string, ...any)) {
+	for i, library := range state.Libraries {
+		for k, api := range library.APIs {
+			apiNode := listItem(nodeAt(nodes, i), "apis", k)
+			apiPath := filepath.Join(apiSource, api.Path)
+			if info, err := os.Stat(apiPath); err != nil || !info.IsDir() {
+				report(fieldLine(apiNode, "path"),
+					"API path %q of library %q does not exist in %s", api.Path, library.ID, apiSource)
+				continue
+			}
+			if api.ServiceConfig == "" {
+				continue
+			}
+			if _, err := os.Stat(filepath.Join(apiPath, api.ServiceConfig)); err != nil {
+				report(fieldLine(apiNode, "service_config"),
+					"service config %q of library %q does not exist in %s", api.ServiceConfig, library.ID, apiPath)
+			}
+		}
+	}
+}
+
+// lintLibraryConfigs reports config.yaml library entries and dependencies
+// which refer to libraries that are not in state.yaml.
+func lintLibraryConfigs(state *config.LibrarianState, librarianConfig *config.LibrarianConfig, nodes []*yaml.Node, report func(int, string, ...any)) {
+	seen := make(map[string]bool)
+	for i, libConfig := range librarianConfig.Libraries {
+		node := nodeAt(nodes, i)
+		if state.LibraryByID(libConfig.LibraryID) == nil {
+			report(fieldLine(node, "id"), "library %q is not configured in state.yaml", libConfig.LibraryID)
+		}
+		if seen[libConfig.LibraryID] {
+			report(fieldLine(node, "id"), "duplicate library %q", libConfig.LibraryID)
+		}
+		seen[libConfig.LibraryID] = true
+		for k, dep := range libConfig.Dependencies {
+			if state.LibraryByID(dep) == nil {
+				report(listItemLine(node, "dependencies", k),
+					"dependency %q of library %q is not configured in state.yaml", dep, libConfig.LibraryID)
+			}
+		}
+	}
+	if _, err := sortLibrariesByDependencies(state, librarianConfig); err != nil {
+		report(0, "%v", err)
+	}
+}
+
+// pathsOverlap reports whether one of the given slash-separated relative
+// paths is equal to, or nested within, the other.
+func pathsOverlap(a, b string) bool {
+	a = filepath.ToSlash(filepath.Clean(a))
+	b = filepath.ToSlash(filepath.Clean(b))
+	return a == b || strings.HasPrefix(a, b+"/") || strings.HasPrefix(b, a+"/")
+}
+
+// readYAMLNode parses the YAML file at path into a document node. It returns
+// nil if the file does not exist.
+func readYAMLNode(path string) (*yaml.Node, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	return &doc, nil
+}
+
+// documentLine returns the line of the first element of doc.
+func documentLine(doc *yaml.Node) int {
+	if doc == nil || len(doc.Content) == 0 {
+		return 0
+	}
+	return doc.Content[0].Line
+}
+
+// libraryNodes returns the nodes of the top-level "libraries" sequence in
+// doc, in order.
+func libraryNodes(doc *yaml.Node) []*yaml.Node {
+	if doc == nil || len(doc.Content) == 0 {
+		return nil
+	}
+	libraries := mappingValue(doc.Content[0], "libraries")
+	if libraries == nil || libraries.Kind != yaml.SequenceNode {
+		return nil
+	}
+	return libraries.Content
+}
+
+// mappingValue returns the value for key in a mapping node, or nil if there
+// is no such key.
+func mappingValue(node *yaml.Node, key string) *yaml.Node {
+	if node == nil || node.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i+1]
+		}
+	}
+	return nil
+}
+
+func nodeAt(nodes []*yaml.Node, i int) *yaml.Node {
+	if i < len(nodes) {
+		return nodes[i]
+	}
+	return nil
+}
+
+// listItem returns the i-th item of the sequence stored under key.
+func listItem(node *yaml.Node, key string, i int) *yaml.Node {
+	list := mappingValue(node, key)
+	if list == nil || list.Kind != yaml.SequenceNode || i >= len(list.Content) {
+		return nil
+	}
+	return list.Content[i]
+}
+
+// fieldLine returns the line of the given key in a mapping node, falling
+// back to the line of the node itself if the key is not present.
+func fieldLine(node *yaml.Node, key string) int {
+	if node == nil {
+		return 0
+	}
+	if value := mappingValue(node, key); value != nil {
+		return value.Line
+	}
+	return node.Line
+}
+
+// listItemLine returns the line of the i-th item of the sequence stored under
+// key, falling back to the line of the node itself.
+func listItemLine(node *yaml.Node, key string, i int) int {
+	if item := listItem(node, key, i); item != nil {
+		return item.Line
+	}
+	return fieldLine(node, key)
+}
@@ -0,0 +1,344 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package librarian
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/googleapis/librarian/internal/config"
+)
+
+func TestLint(t *testing.T) {
+	t.Parallel()
+	for _, test := range []struct {
+		name       string
+		state      string
+		config     string
+		files      []string
+		apiPaths   []string
+		useAPIs    bool
+		want       []string
+		wantErrMsg string
+	}{
+		{
+			name: "no problems",
+			state: `image: gcr.io/test/image:v1
+libraries:
+  - id: a
+    source_roots:
+      - a
+  - id: b
+    source_roots:
+      - b
+`,
+			config: `libraries:
+  - id: b
+    dependencies:
+      - a
+`,
+		},
+		{
+			name: "overlapping source roots",
+			state: `image: gcr.io/test/image:v1
+libraries:
+  - id: a
+    source_roots:
+      - a
+  - id: b
+    source_roots:
+      - b
+      - a/nested
+`,
+			want: []string{
+				`.librarian/state.yaml:9: source root "a/nested" of library "b" overlaps with source root "a" of library "a"`,
+			},
+		},
+		{
+			name: "preserve_regex equal to remove_regex",
+			state: `image: gcr.io/test/image:v1
+libraries:
+  - id: a
+    source_roots:
+      - a
+    remove_regex:
+      - ^a/.*
+    preserve_regex:
+      - ^a/README.md
+      - ^a/.*
+`,
+			want: []string{
+				`.librarian/state.yaml:10: preserve_regex "^a/.*" of library "a" is also a remove_regex, so it never removes anything`,
+			},
+		},
+		{
+			name: "preserve_regex matching every removed file",
+			state: `image: gcr.io/test/image:v1
+libraries:
+  - id: a
+    source_roots:
+      - a
+    remove_regex:
+      - ^a/gen/.*
+    preserve_regex:
+      - ^a/.*\.go$
+`,
+			files: []string{"a/gen/client.go", "a/gen/types.go", "a/README.md"},
+			want: []string{
+				`.librarian/state.yaml:9: preserve_regex "^a/.*\\.go$" of library "a" preserves every file matched by remove_regex "^a/gen/.*", so it never removes anything`,
+			},
+		},
+		{
+			name: "preserve_regex matching some removed files",
+			state: `image: gcr.io/test/image:v1
+libraries:
+  - id: a
+    source_roots:
+      - a
+    remove_regex:
+      - ^a/.*
+    preserve_regex:
+      - ^a/.*\.go$
+`,
+			files: []string{"a/gen/client.go", "a/README.md"},
+		},
+		{
+			name: "tag format collision",
+			state: `image: gcr.io/test/image:v1
+libraries:
+  - id: a
+    source_roots:
+      - a
+    tag_format: v{version}
+  - id: b
+    source_roots:
+      - b
+    tag_format: v{version}
+`,
+			want: []string{
+				`.librarian/state.yaml:10: tag format of library "b" collides with library "a" (both produce "v1.2.3")`,
+			},
+		},
+		{
+			name: "missing API path and service config",
+			state: `image: gcr.io/test/image:v1
+libraries:
+  - id: a
+    source_roots:
+      - a
+    apis:
+      - path: google/a/v1
+        service_config: a_v1.yaml
+      - path: google/missing/v1
+`,
+			apiPaths: []string{"google/a/v1"},
+			useAPIs:  true,
+			want: []string{
+				`.librarian/state.yaml:8: service config "a_v1.yaml" of library "a" does not exist in ` + "API_SOURCE/google/a/v1",
+				`.librarian/state.yaml:9: API path "google/missing/v1" of library "a" does not exist in API_SOURCE`,
+			},
+		},
+		{
+			name: "unknown libraries in config",
+			state: `image: gcr.io/test/image:v1
+libraries:
+  - id: a
+    source_roots:
+      - a
+`,
+			config: `libraries:
+  - id: a
+    dependencies:
+      - unknown-dep
+  - id: unknown
+`,
+			want: []string{
+				`.librarian/config.yaml:4: dependency "unknown-dep" of library "a" is not configured in state.yaml`,
+				`.librarian/config.yaml:5: library "unknown" is not configured in state.yaml`,
+			},
+		},
+		{
+			name: "dependency cycle",
+			state: `image: gcr.io/test/image:v1
+libraries:
+  - id: a
+    source_roots:
+      - a
+  - id: b
+    source_roots:
+      - b
+`,
+			config: `libraries:
+  - id: a
+    dependencies: [b]
+  - id: b
+    dependencies: [a]
+`,
+			want: []string{
+				`.librarian/config.yaml: dependency cycle detected between libraries: a, b`,
+			},
+		},
+		{
+			name: "invalid state is reported",
+			state: `image: gcr.io/test/image:v1
+libraries:
+  - id: a
+`,
+			want: []string{
+				`.librarian/state.yaml:1: invalid library at index 0: source_roots cannot be empty`,
+			},
+		},
+		{
+			name:       "missing state",
+			wantErrMsg: "state.yaml not found",
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			repoDir := t.TempDir()
+			librarianDir := filepath.Join(repoDir, config.LibrarianDir)
+			if err := os.MkdirAll(librarianDir, 0755); err != nil {
+				t.Fatal(err)
+			}
+			if test.state != "" {
+				if err := os.WriteFile(filepath.Join(librarianDir, "state.yaml"), []byte(test.state), 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if test.config != "" {
+				if err := os.WriteFile(filepath.Join(librarianDir, "config.yaml"), []byte(test.config), 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			for _, f := range test.files {
+				path := filepath.Join(repoDir, f)
+				if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+					t.Fatal(err)
+				}
+				if err := os.WriteFile(path, nil, 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			var apiSource string
+			if test.useAPIs {
+				apiSource = t.TempDir()
+				for _, p := range test.apiPaths {
+					if err := os.MkdirAll(filepath.Join(apiSource, p), 0755); err != nil {
+						t.Fatal(err)
+					}
+				}
+			}
+			r := &lintRunner{repoDir: repoDir, apiSource: apiSource}
+			problems, err := r.lint()
+			if test.wantErrMsg != "" {
+				if err == nil {
+					t.Fatal("lint() should return error")
+				}
+				if !strings.Contains(err.Error(), test.wantErrMsg) {
+					t.Errorf("want error message: %q, got %q", test.wantErrMsg, err.Error())
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			var got []string
+			for _, p := range problems {
+				msg := p.String()
+				if apiSource != "" {
+					msg = strings.ReplaceAll(msg, apiSource, "API_SOURCE")
+				}
+				got = append(got, msg)
+			}
+			if diff := cmp.Diff(test.want, got); diff != "" {
+				t.Errorf("lint() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestLintRun(t *testing.T) {
+	t.Parallel()
+	repoDir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(repoDir, config.LibrarianDir), 0755); err != nil {
+		t.Fatal(err)
+	}
+	state := `image: gcr.io/test/image:v1
+libraries:
+  - id: a
+    source_roots: [a]
+  - id: b
+    source_roots: [a]
+`
+	if err := os.WriteFile(filepath.Join(repoDir, config.LibrarianDir, "state.yaml"), []byte(state), 0644); err != nil {
+		t.Fatal(err)
+	}
+	var out bytes.Buffer
+	r := &lintRunner{repoDir: repoDir, out: &out}
+	err := r.run(t.Context())
+	if err == nil || !strings.Contains(err.Error(), "found 1 problem(s)") {
+		t.Errorf("run() error = %v, want error about 1 problem", err)
+	}
+	want := ".librarian/state.yaml:6: source root \"a\" of library \"b\" overlaps with source root \"a\" of library \"a\"\n"
+	if diff := cmp.Diff(want, out.String()); diff != "" {
+		t.Errorf("run() output mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestNewLintRunner(t *testing.T) {
+	t.Parallel()
+	for _, test := range []struct {
+		name          string
+		cfg           *config.Config
+		wantAPISource string
+		wantErr       bool
+	}{
+		{
+			name:          "local api source",
+			cfg:           &config.Config{Repo: "/repo", APISource: "/googleapis"},
+			wantAPISource: "/googleapis",
+		},
+		{
+			name: "remote api source is skipped",
+			cfg:  &config.Config{Repo: "/repo", APISource: "https://github.com/googleapis/googleapis"},
+		},
+		{
+			name:    "remote repo",
+			cfg:     &config.Config{Repo: "https://github.com/googleapis/google-cloud-go"},
+			wantErr: true,
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			r, err := newLintRunner(test.cfg)
+			if test.wantErr {
+				if err == nil {
+					t.Fatal("newLintRunner() should return error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if r.apiSource != test.wantAPISource {
+				t.Errorf("apiSource = %q, want %q", r.apiSource, test.wantAPISource)
+			}
+		})
+	}
+}
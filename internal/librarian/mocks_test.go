@@ -20,6 +20,7 @@ import (
 	"errors"
 	"os"
 	"path/filepath"
+	"strings"
 
 	"github.com/go-git/go-git/v5"
 	"github.com/googleapis/librarian/internal/config"
@@ -42,6 +43,8 @@ type mockGitHubClient struct {
 	getPullRequestCalls     int
 	createReleaseCalls      int
 	createTagCalls          int
+	createIssueCommentCalls int
+	closePullRequestCalls   int
 	createPullRequestErr    error
 	addLabelsToIssuesErr    error
 	getLabelsErr            error
@@ -50,12 +53,18 @@ type mockGitHubClient struct {
 	getPullRequestErr       error
 	createReleaseErr        error
 	createTagErr            error
+	createIssueCommentErr   error
+	closePullRequestErr     error
 	createdPR               *github.PullRequestMetadata
 	labels                  []string
 	pullRequests            []*github.PullRequest
 	pullRequest             *github.PullRequest
 	createdRelease          *github.RepositoryRelease
 	librarianState          *config.LibrarianState
+	comments                map[int]string
+	closedPullRequests      []int
+	uploadReleaseAssetErr   error
+	uploadedAssets          map[string]string
 }
 
 func (m *mockGitHubClient) GetRawContent(ctx context.Context, path, ref string) ([]byte, error) {
@@ -109,17 +118,58 @@ func (m *mockGitHubClient) CreateTag(ctx context.Context, tagName, commitish str
 	return m.createTagErr
 }
 
+func (m *mockGitHubClient) CreateIssueComment(ctx context.Context, number int, comment string) error {
+	m.createIssueCommentCalls++
+	if m.createIssueCommentErr != nil {
+		return m.createIssueCommentErr
+	}
+	if m.comments == nil {
+		m.comments = map[int]string{}
+	}
+	m.comments[number] = comment
+	return nil
+}
+
+func (m *mockGitHubClient) UploadReleaseAsset(ctx context.Context, releaseID int64, path string) error {
+	if m.uploadReleaseAssetErr != nil {
+		return m.uploadReleaseAssetErr
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	if m.uploadedAssets == nil {
+		m.uploadedAssets = map[string]string{}
+	}
+	m.uploadedAssets[filepath.Base(path)] = string(data)
+	return nil
+}
+
+func (m *mockGitHubClient) ClosePullRequest(ctx context.Context, number int) error {
+	m.closePullRequestCalls++
+	if m.closePullRequestErr != nil {
+		return m.closePullRequestErr
+	}
+	m.closedPullRequests = append(m.closedPullRequests, number)
+	return nil
+}
+
 // mockContainerClient is a mock implementation of the ContainerClient interface for testing.
 type mockContainerClient struct {
 	ContainerClient
 	generateCalls  int
 	buildCalls     int
 	configureCalls int
+	describeCalls  int
 	initCalls      int
 	generateErr    error
 	buildErr       error
 	configureErr   error
+	describeErr    error
 	initErr        error
+	// Set this value if you want the describe command to report
+	// capabilities other than the legacy ones.
+	capabilities *docker.Capabilities
 	// Set this value if you want an error when
 	// generate a library with a specific id.
 	failGenerateForID string
@@ -139,6 +189,36 @@ type mockContainerClient struct {
 	// Set this value if you want the configure-response
 	// has library source roots and remove regex.
 	configureLibraryPaths []string
+	packageCalls          int
+	packageErr            error
+	// Set this value if you want the package command
+	// to write release artifacts, by file name.
+	packageArtifacts map[string]string
+	publishCalls     int
+	// Set this value if you want an error when
+	// publishing a library with a specific id.
+	failPublishForID string
+	// registry is a stand-in package registry, recording the
+	// version of each library published by the publish command.
+	registry map[string]string
+	// Set this value to record the contents of a file of the
+	// repository, as seen by the package and publish commands.
+	sourceFile     string
+	sourceContents []string
+}
+
+// recordSourceFile records the contents of sourceFile in the repository, if
+// set.
+func (m *mockContainerClient) recordSourceFile(repoDir string) error {
+	if m.sourceFile == "" {
+		return nil
+	}
+	contents, err := os.ReadFile(filepath.Join(repoDir, m.sourceFile))
+	if err != nil {
+		return err
+	}
+	m.sourceContents = append(m.sourceContents, string(contents))
+	return nil
 }
 
 func (m *mockContainerClient) Build(ctx context.Context, request *docker.BuildRequest) error {
@@ -214,6 +294,17 @@ func (m *mockContainerClient) Configure(ctx context.Context, request *docker.Con
 	return "", m.configureErr
 }
 
+func (m *mockContainerClient) Describe(ctx context.Context, request *docker.DescribeRequest) (*docker.Capabilities, error) {
+	m.describeCalls++
+	if m.describeErr != nil {
+		return nil, m.describeErr
+	}
+	if m.capabilities != nil {
+		return m.capabilities, nil
+	}
+	return docker.LegacyCapabilities(), nil
+}
+
 func (m *mockContainerClient) Generate(ctx context.Context, request *docker.GenerateRequest) error {
 	m.generateCalls++
 
@@ -292,6 +383,59 @@ func (m *mockContainerClient) ReleaseInit(ctx context.Context, request *docker.R
 	return m.initErr
 }
 
+func (m *mockContainerClient) Package(ctx context.Context, request *docker.PackageRequest) error {
+	m.packageCalls++
+	if m.packageErr != nil {
+		return m.packageErr
+	}
+	if err := m.recordSourceFile(request.RepoDir); err != nil {
+		return err
+	}
+	for name, content := range m.packageArtifacts {
+		if err := os.WriteFile(filepath.Join(request.Output, name), []byte(content), 0644); err != nil {
+			return err
+		}
+	}
+	if !m.wantErrorMsg {
+		return nil
+	}
+	if err := os.MkdirAll(filepath.Join(request.RepoDir, config.LibrarianDir), 0755); err != nil {
+		return err
+	}
+	library := &config.LibraryState{ErrorMessage: "simulated error message"}
+	b, err := json.MarshalIndent(library, "", " ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(request.RepoDir, config.LibrarianDir, config.PackageResponse), b, 0755)
+}
+
+func (m *mockContainerClient) Publish(ctx context.Context, request *docker.PublishRequest) error {
+	m.publishCalls++
+	if err := m.recordSourceFile(request.RepoDir); err != nil {
+		return err
+	}
+	if request.LibraryID == m.failPublishForID {
+		if err := os.MkdirAll(filepath.Join(request.RepoDir, config.LibrarianDir), 0755); err != nil {
+			return err
+		}
+		library := &config.LibraryState{ErrorMessage: "simulated publish error"}
+		b, err := json.MarshalIndent(library, "", " ")
+		if err != nil {
+			return err
+		}
+		return os.WriteFile(filepath.Join(request.RepoDir, config.LibrarianDir, config.PublishResponse), b, 0755)
+	}
+	if request.DryRun {
+		return nil
+	}
+	if m.registry == nil {
+		m.registry = map[string]string{}
+	}
+	m.registry[request.LibraryID] = request.Version
+	return nil
+}
+
 type MockRepository struct {
 	gitrepo.Repository
 	Dir                                    string
@@ -318,6 +462,22 @@ type MockRepository struct {
 	CreateBranchAndCheckoutError           error
 	PushError                              error
 	RestoreError                           error
+	CleanUntrackedCalls                    [][]string
+	CleanUntrackedError                    error
+	// AddPathsChangedFiles are the files reported as staged by AddPaths,
+	// when they are in the given paths.
+	AddPathsChangedFiles []string
+	AddPathsCalls        [][]string
+	AddPathsError        error
+	CheckoutCommitCalls  []string
+	CheckoutCommitError  error
+	// ForceCheckoutCommitCalls records the commits checked out with
+	// ForceCheckoutCommit.
+	ForceCheckoutCommitCalls []string
+	ForceCheckoutCommitError error
+	CreatedBranches          []string
+	HeadHashValue            string
+	HeadHashError            error
 }
 
 func (m *MockRepository) IsClean() (bool, error) {
@@ -334,6 +494,36 @@ func (m *MockRepository) AddAll() (git.Status, error) {
 	return m.AddAllStatus, nil
 }
 
+func (m *MockRepository) AddPaths(paths []string) (git.Status, error) {
+	m.AddPathsCalls = append(m.AddPathsCalls, paths)
+	if m.AddPathsError != nil {
+		return git.Status{}, m.AddPathsError
+	}
+	status := git.Status{}
+	for _, file := range m.AddPathsChangedFiles {
+		for _, p := range paths {
+			if file == p || strings.HasPrefix(file, p+"/") {
+				status[file] = &git.FileStatus{Staging: git.Modified, Worktree: git.Unmodified}
+			}
+		}
+	}
+	return status, nil
+}
+
+func (m *MockRepository) HeadHash() (string, error) {
+	return m.HeadHashValue, m.HeadHashError
+}
+
+func (m *MockRepository) CheckoutCommit(commitHash string) error {
+	m.CheckoutCommitCalls = append(m.CheckoutCommitCalls, commitHash)
+	return m.CheckoutCommitError
+}
+
+func (m *MockRepository) ForceCheckoutCommit(commitHash string) error {
+	m.ForceCheckoutCommitCalls = append(m.ForceCheckoutCommitCalls, commitHash)
+	return m.ForceCheckoutCommitError
+}
+
 func (m *MockRepository) Commit(msg string) error {
 	m.CommitCalls++
 	return m.CommitError
@@ -416,6 +606,7 @@ func (m *MockRepository) CreateBranchAndCheckout(name string) error {
 	if m.CreateBranchAndCheckoutError != nil {
 		return m.CreateBranchAndCheckoutError
 	}
+	m.CreatedBranches = append(m.CreatedBranches, name)
 	return nil
 }
 
@@ -429,3 +620,8 @@ func (m *MockRepository) Push(name string) error {
 func (m *MockRepository) Restore(paths []string) error {
 	return m.RestoreError
 }
+
+func (m *MockRepository) CleanUntracked(paths []string) error {
+	m.CleanUntrackedCalls = append(m.CleanUntrackedCalls, paths)
+	return m.CleanUntrackedError
+}
@@ -0,0 +1,79 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package librarian
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"path/filepath"
+	"strings"
+
+	"github.com/googleapis/librarian/internal/config"
+	"github.com/googleapis/librarian/internal/docker"
+)
+
+// publishLibraries asks the container to publish each released library, and
+// comments the result of each library on the release pull request.
+//
+// A failure to publish a library does not stop the other libraries from being
+// published. An error listing the libraries which failed is returned once all
+// the libraries have been processed. In a dry run, the results are only
+// logged.
+//
+// The language repository must be checked out at the released commit.
+func (r *tagAndReleaseRunner) publishLibraries(ctx context.Context, number int, requests []*docker.PublishRequest) error {
+	var failed []string
+	for _, request := range requests {
+		slog.Info("publishing library", "library", request.LibraryID, "version", request.Version, "dry_run", request.DryRun)
+		err := r.publishLibrary(ctx, request)
+		if err != nil {
+			slog.Error("failed to publish library", "library", request.LibraryID, "version", request.Version, "error", err)
+			failed = append(failed, request.LibraryID)
+		} else {
+			slog.Info("published library", "library", request.LibraryID, "version", request.Version, "dry_run", request.DryRun)
+		}
+		if request.DryRun {
+			continue
+		}
+		if err := r.ghClient.CreateIssueComment(ctx, number, formatPublishComment(request, err)); err != nil {
+			return fmt.Errorf("failed to comment publish result of %s: %w", request.LibraryID, err)
+		}
+	}
+	if len(failed) != 0 {
+		return fmt.Errorf("failed to publish libraries: %s", strings.Join(failed, ", "))
+	}
+	return nil
+}
+
+// publishLibrary runs the publish command of the container for a library,
+// and returns the error reported by the container, if any.
+func (r *tagAndReleaseRunner) publishLibrary(ctx context.Context, request *docker.PublishRequest) error {
+	if err := r.containerClient.Publish(ctx, request); err != nil {
+		return err
+	}
+	_, err := readLibraryState(filepath.Join(request.RepoDir, config.LibrarianDir, config.PublishResponse))
+	return err
+}
+
+// formatPublishComment formats the pull request comment reporting the result
+// of publishing a library.
+func formatPublishComment(request *docker.PublishRequest, err error) string {
+	if err != nil {
+		return fmt.Sprintf("Failed to publish %s %s (tag `%s`): %s\n\nTo publish it again, run `librarian release tag-and-release` with `--pr` set to this pull request, `--publish` and `--library=%s`.",
+			request.LibraryID, request.Version, request.Tag, err, request.LibraryID)
+	}
+	return fmt.Sprintf("Published %s %s (tag `%s`).", request.LibraryID, request.Version, request.Tag)
+}
@@ -0,0 +1,92 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package librarian
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/googleapis/librarian/internal/config"
+	"github.com/googleapis/librarian/internal/docker"
+)
+
+func TestPublishLibraries(t *testing.T) {
+	state := &config.LibrarianState{
+		Libraries: []*config.LibraryState{
+			{ID: "google-cloud-storage", Version: "1.2.3"},
+		},
+	}
+	for _, test := range []struct {
+		name            string
+		containerClient *mockContainerClient
+		ghClient        *mockGitHubClient
+		wantRegistry    map[string]string
+		wantComment     string
+		wantErrMsg      string
+	}{
+		{
+			name:            "published",
+			containerClient: &mockContainerClient{},
+			ghClient:        &mockGitHubClient{},
+			wantRegistry:    map[string]string{"google-cloud-storage": "1.2.3"},
+			wantComment:     "Published google-cloud-storage 1.2.3 (tag `v1.2.3`).",
+		},
+		{
+			name:            "container error",
+			containerClient: &mockContainerClient{failPublishForID: "google-cloud-storage"},
+			ghClient:        &mockGitHubClient{},
+			wantComment:     "Failed to publish google-cloud-storage 1.2.3 (tag `v1.2.3`): failed with error message: simulated publish error\n\nTo publish it again, run `librarian release tag-and-release` with `--pr` set to this pull request, `--publish` and `--library=google-cloud-storage`.",
+			wantErrMsg:      "failed to publish libraries: google-cloud-storage",
+		},
+		{
+			name:            "comment error",
+			containerClient: &mockContainerClient{},
+			ghClient:        &mockGitHubClient{createIssueCommentErr: errors.New("comment error")},
+			wantRegistry:    map[string]string{"google-cloud-storage": "1.2.3"},
+			wantErrMsg:      "failed to comment publish result of google-cloud-storage",
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			r := &tagAndReleaseRunner{
+				ghClient:        test.ghClient,
+				publish:         true,
+				containerClient: test.containerClient,
+			}
+			request := &docker.PublishRequest{
+				LibraryID: "google-cloud-storage",
+				RepoDir:   t.TempDir(),
+				State:     state,
+				Tag:       "v1.2.3",
+				Version:   "1.2.3",
+			}
+			err := r.publishLibraries(t.Context(), 123, []*docker.PublishRequest{request})
+			if test.wantErrMsg != "" {
+				if err == nil || !strings.Contains(err.Error(), test.wantErrMsg) {
+					t.Fatalf("publishLibraries() error = %v, want %q", err, test.wantErrMsg)
+				}
+			} else if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(test.wantRegistry, test.containerClient.registry); diff != "" {
+				t.Errorf("published versions mismatch (-want +got):\n%s", diff)
+			}
+			if got := test.ghClient.comments[123]; got != test.wantComment {
+				t.Errorf("comment = %q, want %q", got, test.wantComment)
+			}
+		})
+	}
+}
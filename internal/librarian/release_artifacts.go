@@ -0,0 +1,137 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package librarian
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/googleapis/librarian/internal/config"
+	"github.com/googleapis/librarian/internal/docker"
+)
+
+// checksumsFileName is the name of the release asset listing the SHA-256
+// checksums of the other release assets, in the format of sha256sum.
+const checksumsFileName = "SHA256SUMS"
+
+// uploadReleaseArtifacts asks the container to package the release artifacts
+// of a library, and uploads them as assets of the GitHub release, along with
+// a file with their SHA-256 checksums.
+//
+// The language repository must be checked out at the released commit. In a
+// dry run the artifacts are packaged, but not uploaded.
+func (r *tagAndReleaseRunner) uploadReleaseArtifacts(ctx context.Context, releaseID int64, state *config.LibrarianState, libraryID string) error {
+	outputDir, err := os.MkdirTemp(r.workRoot, "release-artifacts-")
+	if err != nil {
+		return err
+	}
+	packageRequest := &docker.PackageRequest{
+		HostMount: r.hostMount,
+		LibraryID: libraryID,
+		Output:    outputDir,
+		RepoDir:   r.repo.GetDir(),
+		State:     state,
+	}
+	slog.Info("packaging release artifacts", "library", libraryID)
+	if err := r.containerClient.Package(ctx, packageRequest); err != nil {
+		return fmt.Errorf("failed to package release artifacts for %s: %w", libraryID, err)
+	}
+	if _, err := readLibraryState(
+		filepath.Join(packageRequest.RepoDir, config.LibrarianDir, config.PackageResponse)); err != nil {
+		return fmt.Errorf("failed to package release artifacts for %s: %w", libraryID, err)
+	}
+
+	artifacts, err := releaseArtifacts(outputDir)
+	if err != nil {
+		return err
+	}
+	if len(artifacts) == 0 {
+		slog.Info("no release artifacts to upload", "library", libraryID)
+		return nil
+	}
+	checksums, err := writeChecksums(outputDir, artifacts)
+	if err != nil {
+		return err
+	}
+	if r.dryRun {
+		slog.Info("dry run, skipping upload of release artifacts", "library", libraryID, "count", len(artifacts))
+		return nil
+	}
+	for _, path := range append(artifacts, checksums) {
+		if err := r.ghClient.UploadReleaseAsset(ctx, releaseID, path); err != nil {
+			return fmt.Errorf("failed to upload release asset %s: %w", filepath.Base(path), err)
+		}
+	}
+	slog.Info("uploaded release artifacts", "library", libraryID, "count", len(artifacts))
+	return nil
+}
+
+// releaseArtifacts returns the paths of the release artifacts written by the
+// container in dir, sorted by name. Any checksums file written by the
+// container is ignored, as it is replaced by the one generated by Librarian.
+func releaseArtifacts(dir string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read release artifacts: %w", err)
+	}
+	var artifacts []string
+	for _, entry := range entries {
+		if entry.Name() == checksumsFileName {
+			continue
+		}
+		if !entry.Type().IsRegular() {
+			return nil, fmt.Errorf("release artifact %s is not a regular file", entry.Name())
+		}
+		artifacts = append(artifacts, filepath.Join(dir, entry.Name()))
+	}
+	return artifacts, nil
+}
+
+// writeChecksums writes the SHA-256 checksums of the artifacts to a checksums
+// file in dir, and returns its path.
+func writeChecksums(dir string, artifacts []string) (string, error) {
+	var sb strings.Builder
+	for _, path := range artifacts {
+		sum, err := sha256File(path)
+		if err != nil {
+			return "", err
+		}
+		fmt.Fprintf(&sb, "%x  %s\n", sum, filepath.Base(path))
+	}
+	checksums := filepath.Join(dir, checksumsFileName)
+	if err := os.WriteFile(checksums, []byte(sb.String()), 0644); err != nil {
+		return "", err
+	}
+	return checksums, nil
+}
+
+func sha256File(path string) ([]byte, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return nil, fmt.Errorf("failed to compute checksum of %s: %w", path, err)
+	}
+	return h.Sum(nil), nil
+}
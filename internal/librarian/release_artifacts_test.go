@@ -0,0 +1,136 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package librarian
+
+import (
+	"context"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/googleapis/librarian/internal/config"
+)
+
+func TestUploadReleaseArtifacts(t *testing.T) {
+	state := &config.LibrarianState{
+		Libraries: []*config.LibraryState{
+			{ID: "google-cloud-storage", Version: "1.2.3"},
+		},
+	}
+	checksum := func(content string) string {
+		return fmt.Sprintf("%x", sha256.Sum256([]byte(content)))
+	}
+	for _, test := range []struct {
+		name            string
+		dryRun          bool
+		containerClient *mockContainerClient
+		ghClient        *mockGitHubClient
+		wantAssets      map[string]string
+		wantErrMsg      string
+	}{
+		{
+			name: "artifacts and checksums",
+			containerClient: &mockContainerClient{
+				packageArtifacts: map[string]string{
+					"storage-1.2.3.tar.gz": "archive",
+					"storage-1.2.3.intoto": "provenance",
+					checksumsFileName:      "ignored",
+				},
+			},
+			ghClient: &mockGitHubClient{},
+			wantAssets: map[string]string{
+				"storage-1.2.3.tar.gz": "archive",
+				"storage-1.2.3.intoto": "provenance",
+				checksumsFileName: fmt.Sprintf("%s  storage-1.2.3.intoto\n%s  storage-1.2.3.tar.gz\n",
+					checksum("provenance"), checksum("archive")),
+			},
+		},
+		{
+			name:   "dry run",
+			dryRun: true,
+			containerClient: &mockContainerClient{
+				packageArtifacts: map[string]string{"storage-1.2.3.tar.gz": "archive"},
+			},
+			ghClient: &mockGitHubClient{uploadReleaseAssetErr: errors.New("unexpected upload")},
+		},
+		{
+			name:            "no artifacts",
+			containerClient: &mockContainerClient{},
+			ghClient:        &mockGitHubClient{},
+		},
+		{
+			name:            "package error",
+			containerClient: &mockContainerClient{packageErr: errors.New("package error")},
+			ghClient:        &mockGitHubClient{},
+			wantErrMsg:      "failed to package release artifacts for google-cloud-storage",
+		},
+		{
+			name:            "package response error",
+			containerClient: &mockContainerClient{wantErrorMsg: true},
+			ghClient:        &mockGitHubClient{},
+			wantErrMsg:      "simulated error message",
+		},
+		{
+			name: "upload error",
+			containerClient: &mockContainerClient{
+				packageArtifacts: map[string]string{"storage-1.2.3.tar.gz": "archive"},
+			},
+			ghClient:   &mockGitHubClient{uploadReleaseAssetErr: errors.New("upload error")},
+			wantErrMsg: "failed to upload release asset storage-1.2.3.tar.gz",
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			r := &tagAndReleaseRunner{
+				ghClient:         test.ghClient,
+				releaseArtifacts: true,
+				dryRun:           test.dryRun,
+				containerClient:  test.containerClient,
+				repo:             &MockRepository{Dir: t.TempDir()},
+				workRoot:         t.TempDir(),
+			}
+			err := r.uploadReleaseArtifacts(context.Background(), 42, state, "google-cloud-storage")
+			if test.wantErrMsg != "" {
+				if err == nil || !strings.Contains(err.Error(), test.wantErrMsg) {
+					t.Fatalf("uploadReleaseArtifacts() error = %v, want %q", err, test.wantErrMsg)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if test.containerClient.packageCalls != 1 {
+				t.Errorf("Package() calls = %d, want 1", test.containerClient.packageCalls)
+			}
+			if diff := cmp.Diff(test.wantAssets, test.ghClient.uploadedAssets); diff != "" {
+				t.Errorf("uploaded assets mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestReleaseArtifacts_Directory(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.Mkdir(filepath.Join(dir, "docs"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := releaseArtifacts(dir); err == nil {
+		t.Error("releaseArtifacts() expected error for a directory, got nil")
+	}
+}
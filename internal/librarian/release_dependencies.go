@@ -0,0 +1,152 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package librarian
+
+import (
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/googleapis/librarian/internal/config"
+	"github.com/googleapis/librarian/internal/conventionalcommits"
+)
+
+// dependencyUpdateScope is the conventional commit scope used for the
+// synthetic commits recording that a dependency of a library was released.
+const dependencyUpdateScope = "deps"
+
+// sortLibrariesByDependencies returns the libraries in state ordered so that
+// each library appears after all the libraries it depends on, according to
+// the dependencies declared in the librarian config. Libraries without any
+// ordering constraint keep their relative order from state.yaml.
+//
+// Dependencies on libraries which are not in state are ignored. An error is
+// returned if the dependencies form a cycle.
+func sortLibrariesByDependencies(state *config.LibrarianState, cfg *config.LibrarianConfig) ([]*config.LibraryState, error) {
+	dependencies := make(map[string][]string)
+	if cfg != nil {
+		for _, library := range state.Libraries {
+			libConfig := cfg.LibraryConfigFor(library.ID)
+			if libConfig == nil {
+				continue
+			}
+			for _, dep := range libConfig.Dependencies {
+				if state.LibraryByID(dep) == nil {
+					slog.Warn("ignoring dependency on unknown library", "library", library.ID, "dependency", dep)
+					continue
+				}
+				dependencies[library.ID] = append(dependencies[library.ID], dep)
+			}
+		}
+	}
+
+	sorted := make([]*config.LibraryState, 0, len(state.Libraries))
+	placed := make(map[string]bool)
+	for len(sorted) < len(state.Libraries) {
+		progress := false
+		for _, library := range state.Libraries {
+			if placed[library.ID] {
+				continue
+			}
+			ready := true
+			for _, dep := range dependencies[library.ID] {
+				if !placed[dep] {
+					ready = false
+					break
+				}
+			}
+			if !ready {
+				continue
+			}
+			sorted = append(sorted, library)
+			placed[library.ID] = true
+			progress = true
+		}
+		if !progress {
+			var remaining []string
+			for _, library := range state.Libraries {
+				if !placed[library.ID] {
+					remaining = append(remaining, library.ID)
+				}
+			}
+			return nil, fmt.Errorf("dependency cycle detected between libraries: %s", strings.Join(remaining, ", "))
+		}
+	}
+	return sorted, nil
+}
+
+// cascadeDependencyUpdates releases the dependents of every library that is
+// part of this release.
+//
+// Libraries are visited in dependency order, so a dependent which is released
+// only because of a dependency update in turn triggers a release of its own
+// dependents. Each dependent receives a "fix(deps)" change per updated
+// dependency, which results in (at least) a patch bump and an entry in the
+// release notes. Libraries which are already being released only get the
+// additional release note entries.
+//
+// Dependents are not released when `--library` is set, as the request to the
+// container, the version override and the pull request only describe that
+// library.
+func (r *initRunner) cascadeDependencyUpdates() error {
+	if r.librarianConfig == nil {
+		return nil
+	}
+	if r.library != "" {
+		slog.Info("Skipping the release of dependents for a single library", "library", r.library)
+		return nil
+	}
+	libraries, err := sortLibrariesByDependencies(r.state, r.librarianConfig)
+	if err != nil {
+		return err
+	}
+	for _, library := range libraries {
+		libConfig := r.librarianConfig.LibraryConfigFor(library.ID)
+		if libConfig == nil {
+			continue
+		}
+		var updates []*conventionalcommits.ConventionalCommit
+		for _, depID := range libConfig.Dependencies {
+			dep := r.state.LibraryByID(depID)
+			if dep == nil || !dep.ReleaseTriggered {
+				continue
+			}
+			updates = append(updates, &conventionalcommits.ConventionalCommit{
+				Type:      "fix",
+				Scope:     dependencyUpdateScope,
+				Subject:   fmt.Sprintf("dependency %s updated to %s", dep.ID, dep.Version),
+				LibraryID: library.ID,
+			})
+		}
+		if len(updates) == 0 {
+			continue
+		}
+		if library.ReleaseTriggered {
+			library.Changes = append(library.Changes, updates...)
+			continue
+		}
+
+		nextVersion, err := r.determineNextVersion(updates, library.Version, library.ID)
+		if err != nil {
+			return err
+		}
+		slog.Info("Releasing library due to dependency updates", "library", library.ID, "currentVersion", library.Version, "nextVersion", nextVersion)
+		library.PreviousVersion = library.Version
+		library.Changes = updates
+		library.Version = nextVersion
+		library.ReleaseTriggered = true
+	}
+	return nil
+}
@@ -0,0 +1,298 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package librarian
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/googleapis/librarian/internal/config"
+	"github.com/googleapis/librarian/internal/conventionalcommits"
+)
+
+func TestSortLibrariesByDependencies(t *testing.T) {
+	t.Parallel()
+	for _, test := range []struct {
+		name            string
+		state           *config.LibrarianState
+		librarianConfig *config.LibrarianConfig
+		want            []string
+		wantErrMsg      string
+	}{
+		{
+			name: "no librarian config",
+			state: &config.LibrarianState{
+				Libraries: []*config.LibraryState{{ID: "b"}, {ID: "a"}},
+			},
+			want: []string{"b", "a"},
+		},
+		{
+			name: "dependencies before dependents",
+			state: &config.LibrarianState{
+				Libraries: []*config.LibraryState{{ID: "app"}, {ID: "auth"}, {ID: "other"}, {ID: "core"}},
+			},
+			librarianConfig: &config.LibrarianConfig{
+				Libraries: []*config.LibraryConfig{
+					{LibraryID: "app", Dependencies: []string{"auth", "core"}},
+					{LibraryID: "auth", Dependencies: []string{"core"}},
+				},
+			},
+			want: []string{"other", "core", "auth", "app"},
+		},
+		{
+			name: "unknown dependency is ignored",
+			state: &config.LibrarianState{
+				Libraries: []*config.LibraryState{{ID: "a"}, {ID: "b"}},
+			},
+			librarianConfig: &config.LibrarianConfig{
+				Libraries: []*config.LibraryConfig{
+					{LibraryID: "a", Dependencies: []string{"unknown"}},
+				},
+			},
+			want: []string{"a", "b"},
+		},
+		{
+			name: "cycle",
+			state: &config.LibrarianState{
+				Libraries: []*config.LibraryState{{ID: "a"}, {ID: "b"}, {ID: "c"}},
+			},
+			librarianConfig: &config.LibrarianConfig{
+				Libraries: []*config.LibraryConfig{
+					{LibraryID: "a", Dependencies: []string{"b"}},
+					{LibraryID: "b", Dependencies: []string{"a"}},
+				},
+			},
+			wantErrMsg: "dependency cycle detected between libraries: a, b",
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			got, err := sortLibrariesByDependencies(test.state, test.librarianConfig)
+			if test.wantErrMsg != "" {
+				if err == nil {
+					t.Fatal("sortLibrariesByDependencies() should return error")
+				}
+				if !strings.Contains(err.Error(), test.wantErrMsg) {
+					t.Errorf("want error message: %q, got %q", test.wantErrMsg, err.Error())
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			var gotIDs []string
+			for _, library := range got {
+				gotIDs = append(gotIDs, library.ID)
+			}
+			if diff := cmp.Diff(test.want, gotIDs); diff != "" {
+				t.Errorf("sortLibrariesByDependencies() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestCascadeDependencyUpdates(t *testing.T) {
+	t.Parallel()
+	for _, test := range []struct {
+		name            string
+		library         string
+		state           *config.LibrarianState
+		librarianConfig *config.LibrarianConfig
+		want            *config.LibrarianState
+		wantErrMsg      string
+	}{
+		{
+			name: "no librarian config",
+			state: &config.LibrarianState{
+				Libraries: []*config.LibraryState{
+					{ID: "core", Version: "1.1.0", ReleaseTriggered: true},
+					{ID: "app", Version: "1.0.0"},
+				},
+			},
+			want: &config.LibrarianState{
+				Libraries: []*config.LibraryState{
+					{ID: "core", Version: "1.1.0", ReleaseTriggered: true},
+					{ID: "app", Version: "1.0.0"},
+				},
+			},
+		},
+		{
+			name: "transitive dependents are released",
+			state: &config.LibrarianState{
+				Libraries: []*config.LibraryState{
+					{ID: "app", Version: "3.0.0"},
+					{ID: "auth", Version: "2.0.0"},
+					{ID: "core", Version: "1.1.0", PreviousVersion: "1.0.0", ReleaseTriggered: true},
+					{ID: "unrelated", Version: "1.0.0"},
+				},
+			},
+			librarianConfig: &config.LibrarianConfig{
+				Libraries: []*config.LibraryConfig{
+					{LibraryID: "app", Dependencies: []string{"auth"}},
+					{LibraryID: "auth", Dependencies: []string{"core"}},
+				},
+			},
+			want: &config.LibrarianState{
+				Libraries: []*config.LibraryState{
+					{
+						ID:               "app",
+						Version:          "3.0.1",
+						PreviousVersion:  "3.0.0",
+						ReleaseTriggered: true,
+						Changes: []*conventionalcommits.ConventionalCommit{
+							{Type: "fix", Scope: "deps", Subject: "dependency auth updated to 2.0.1", LibraryID: "app"},
+						},
+					},
+					{
+						ID:               "auth",
+						Version:          "2.0.1",
+						PreviousVersion:  "2.0.0",
+						ReleaseTriggered: true,
+						Changes: []*conventionalcommits.ConventionalCommit{
+							{Type: "fix", Scope: "deps", Subject: "dependency core updated to 1.1.0", LibraryID: "auth"},
+						},
+					},
+					{ID: "core", Version: "1.1.0", PreviousVersion: "1.0.0", ReleaseTriggered: true},
+					{ID: "unrelated", Version: "1.0.0"},
+				},
+			},
+		},
+		{
+			name:    "single library does not release dependents",
+			library: "core",
+			state: &config.LibrarianState{
+				Libraries: []*config.LibraryState{
+					{ID: "core", Version: "1.1.0", PreviousVersion: "1.0.0", ReleaseTriggered: true},
+					{ID: "app", Version: "1.0.0"},
+				},
+			},
+			librarianConfig: &config.LibrarianConfig{
+				Libraries: []*config.LibraryConfig{
+					{LibraryID: "app", Dependencies: []string{"core"}},
+				},
+			},
+			want: &config.LibrarianState{
+				Libraries: []*config.LibraryState{
+					{ID: "core", Version: "1.1.0", PreviousVersion: "1.0.0", ReleaseTriggered: true},
+					{ID: "app", Version: "1.0.0"},
+				},
+			},
+		},
+		{
+			name: "dependent already released keeps its version",
+			state: &config.LibrarianState{
+				Libraries: []*config.LibraryState{
+					{ID: "core", Version: "1.1.0", ReleaseTriggered: true},
+					{
+						ID:               "app",
+						Version:          "2.0.0",
+						PreviousVersion:  "1.0.0",
+						ReleaseTriggered: true,
+						Changes: []*conventionalcommits.ConventionalCommit{
+							{Type: "feat", Subject: "breaking", IsBreaking: true},
+						},
+					},
+				},
+			},
+			librarianConfig: &config.LibrarianConfig{
+				Libraries: []*config.LibraryConfig{
+					{LibraryID: "app", Dependencies: []string{"core"}},
+				},
+			},
+			want: &config.LibrarianState{
+				Libraries: []*config.LibraryState{
+					{ID: "core", Version: "1.1.0", ReleaseTriggered: true},
+					{
+						ID:               "app",
+						Version:          "2.0.0",
+						PreviousVersion:  "1.0.0",
+						ReleaseTriggered: true,
+						Changes: []*conventionalcommits.ConventionalCommit{
+							{Type: "feat", Subject: "breaking", IsBreaking: true},
+							{Type: "fix", Scope: "deps", Subject: "dependency core updated to 1.1.0", LibraryID: "app"},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "next_version override applies to dependents",
+			state: &config.LibrarianState{
+				Libraries: []*config.LibraryState{
+					{ID: "core", Version: "1.1.0", ReleaseTriggered: true},
+					{ID: "app", Version: "1.0.0"},
+				},
+			},
+			librarianConfig: &config.LibrarianConfig{
+				Libraries: []*config.LibraryConfig{
+					{LibraryID: "app", Dependencies: []string{"core"}, NextVersion: "2.0.0"},
+				},
+			},
+			want: &config.LibrarianState{
+				Libraries: []*config.LibraryState{
+					{ID: "core", Version: "1.1.0", ReleaseTriggered: true},
+					{
+						ID:               "app",
+						Version:          "2.0.0",
+						PreviousVersion:  "1.0.0",
+						ReleaseTriggered: true,
+						Changes: []*conventionalcommits.ConventionalCommit{
+							{Type: "fix", Scope: "deps", Subject: "dependency core updated to 1.1.0", LibraryID: "app"},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "cycle",
+			state: &config.LibrarianState{
+				Libraries: []*config.LibraryState{{ID: "a"}, {ID: "b"}},
+			},
+			librarianConfig: &config.LibrarianConfig{
+				Libraries: []*config.LibraryConfig{
+					{LibraryID: "a", Dependencies: []string{"b"}},
+					{LibraryID: "b", Dependencies: []string{"a"}},
+				},
+			},
+			wantErrMsg: "dependency cycle",
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			r := &initRunner{
+				library:         test.library,
+				state:           test.state,
+				librarianConfig: test.librarianConfig,
+			}
+			err := r.cascadeDependencyUpdates()
+			if test.wantErrMsg != "" {
+				if err == nil {
+					t.Fatal("cascadeDependencyUpdates() should return error")
+				}
+				if !strings.Contains(err.Error(), test.wantErrMsg) {
+					t.Errorf("want error message: %q, got %q", test.wantErrMsg, err.Error())
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(test.want, r.state); diff != "" {
+				t.Errorf("cascadeDependencyUpdates() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -34,6 +34,7 @@ type initRunner struct {
 	commit          bool
 	containerClient ContainerClient
 	ghClient        GitHubClient
+	hostMount       string
 	image           string
 	librarianConfig *config.LibrarianConfig
 	library         string
@@ -56,6 +57,7 @@ func newInitRunner(cfg *config.Config) (*initRunner, error) {
 		commit:          cfg.Commit,
 		containerClient: runner.containerClient,
 		ghClient:        runner.ghClient,
+		hostMount:       cfg.HostMount,
 		image:           runner.image,
 		librarianConfig: runner.librarianConfig,
 		library:         cfg.Library,
@@ -75,6 +77,16 @@ func (r *initRunner) run(ctx context.Context) error {
 		return fmt.Errorf("failed to create output dir: %s", outputDir)
 	}
 	slog.Info("Initiating a release", "dir", outputDir)
+	capabilities, err := r.containerClient.Describe(ctx, &docker.DescribeRequest{
+		HostMount: r.hostMount,
+		RepoDir:   r.repo.GetDir(),
+	})
+	if err != nil {
+		return err
+	}
+	if !capabilities.Supports(docker.CommandReleaseInit) {
+		return fmt.Errorf("image %s does not support the %s command", r.image, docker.CommandReleaseInit)
+	}
 	if err := r.runInitCommand(ctx, outputDir); err != nil {
 		return err
 	}
@@ -91,13 +103,14 @@ func (r *initRunner) run(ctx context.Context) error {
 	}
 
 	commitInfo := &commitInfo{
-		branch:         r.branch,
-		commit:         r.commit,
-		commitMessage:  "chore: create a release",
-		ghClient:       r.ghClient,
-		library:        r.library,
-		libraryVersion: r.libraryVersion,
-		prType:         release,
+		branch:          r.branch,
+		commit:          r.commit,
+		commitMessage:   "chore: create a release",
+		ghClient:        r.ghClient,
+		librarianConfig: r.librarianConfig,
+		library:         r.library,
+		libraryVersion:  r.libraryVersion,
+		prType:          release,
 		// Newly created PRs from the `release init` command should have a
 		// `release:pending` GitHub tab to be tracked for release.
 		pullRequestLabels: []string{"release:pending"},
@@ -139,13 +152,19 @@ func (r *initRunner) runInitCommand(ctx context.Context, outputDir string) error
 		}
 		librariesToRelease = []*config.LibraryState{library}
 	}
-	// Mark if there are any library that needs to be released
-	foundReleasableLibrary := false
 	for _, library := range librariesToRelease {
 		if err := r.processLibrary(library); err != nil {
 			return err
 		}
+	}
 
+	if err := r.cascadeDependencyUpdates(); err != nil {
+		return err
+	}
+
+	// Mark if there are any library that needs to be released
+	foundReleasableLibrary := false
+	for _, library := range r.state.Libraries {
 		// Copy the library files over if a release is needed
 		if library.ReleaseTriggered {
 			foundReleasableLibrary = true
@@ -171,6 +190,7 @@ func (r *initRunner) runInitCommand(ctx context.Context, outputDir string) error
 	initRequest := &docker.ReleaseInitRequest{
 		Branch:          r.branch,
 		Commit:          r.commit,
+		HostMount:       r.hostMount,
 		LibrarianConfig: r.librarianConfig,
 		LibraryID:       r.library,
 		LibraryVersion:  r.libraryVersion,
@@ -190,7 +210,7 @@ func (r *initRunner) runInitCommand(ctx context.Context, outputDir string) error
 		return err
 	}
 
-	for _, library := range librariesToRelease {
+	for _, library := range r.state.Libraries {
 		// Copy the library files back if a release is needed
 		if library.ReleaseTriggered {
 			if err := copyLibraryFiles(r.state, r.repo.GetDir(), library.ID, outputDir); err != nil {
@@ -28,6 +28,7 @@ import (
 	"gopkg.in/yaml.v3"
 
 	"github.com/googleapis/librarian/internal/conventionalcommits"
+	"github.com/googleapis/librarian/internal/docker"
 
 	"github.com/go-git/go-git/v5"
 
@@ -422,6 +423,35 @@ func TestInitRun(t *testing.T) {
 			wantErr:    true,
 			wantErrMsg: "simulated init error",
 		},
+		{
+			name: "image does not support release-init",
+			containerClient: &mockContainerClient{
+				capabilities: &docker.Capabilities{
+					ContractVersion: 1,
+					Commands:        []docker.Command{docker.CommandGenerate},
+				},
+			},
+			setupRunner: func(containerClient *mockContainerClient) *initRunner {
+				return &initRunner{
+					workRoot:        t.TempDir(),
+					containerClient: containerClient,
+					image:           "gcr.io/test/image:v1.2.3",
+					state: &config.LibrarianState{
+						Libraries: []*config.LibraryState{
+							{
+								Version: "1.0.0",
+								ID:      "example-id",
+							},
+						},
+					},
+					repo:            mockRepoWithReleasableUnit,
+					partialRepo:     t.TempDir(),
+					librarianConfig: &config.LibrarianConfig{},
+				}
+			},
+			wantErr:    true,
+			wantErrMsg: "does not support the release-init command",
+		},
 		{
 			name: "release response from container contains error message",
 			containerClient: &mockContainerClient{
@@ -77,6 +77,8 @@ Language Image: {{.ImageVersion}}
 {{ range .Commits }}
 {{ if index .Footers "PiperOrigin-RevId" -}}
 * {{.Subject}} (PiperOrigin-RevId: {{index .Footers "PiperOrigin-RevId"}}) ([{{shortSHA .SHA}}]({{"https://github.com/"}}{{$noteSection.RepoOwner}}/{{$noteSection.RepoName}}/commit/{{.SHA}}))
+{{- else if not .SHA -}}
+* {{if .Scope}}**{{.Scope}}:** {{end}}{{.Subject}}
 {{- else -}}
 * {{.Subject}} ([{{shortSHA .SHA}}]({{"https://github.com/"}}{{$noteSection.RepoOwner}}/{{$noteSection.RepoName}}/commit/{{.SHA}}))
 {{- end }}
@@ -89,12 +91,10 @@ Language Image: {{.ImageVersion}}
 `))
 
 	genBodyTemplate = template.Must(template.New("genBody").Funcs(template.FuncMap{
-		"shortSHA": shortSHA,
-	}).Parse(`This pull request is generated with proto changes between
-[googleapis/googleapis@{{shortSHA .StartSHA}}](https://github.com/googleapis/googleapis/commit/{{.StartSHA}})
-(exclusive) and
-[googleapis/googleapis@{{shortSHA .EndSHA}}](https://github.com/googleapis/googleapis/commit/{{.EndSHA}})
-(inclusive).
+		"shortSHA":   shortSHA,
+		"commitLink": newCommitLink,
+	}).Parse(`
+{{- template "generationRanges" . }}
 
 Librarian Version: {{.LibrarianVersion}}
 Language Image: {{.ImageVersion}}
@@ -107,6 +107,21 @@ Language Image: {{.ImageVersion}}
 {{- end -}}
 {{- end }}
 
+{{ template "commitOverride" . }}
+{{- define "generationRanges" }}
+{{- range $i, $r := .Ranges }}{{ if $i }}
+
+It also includes changes in {{ $r.Repo }} between{{ else }}This pull request is generated with proto changes between{{ end }}
+{{ template "commitLink" (commitLink $r.Repo $r.StartSHA $r.OnGitHub) }}
+(exclusive) and
+{{ template "commitLink" (commitLink $r.Repo $r.EndSHA $r.OnGitHub) }}
+(inclusive).
+{{- end }}
+{{- end }}
+{{- define "commitLink" -}}
+{{ if .OnGitHub }}[{{ .Repo }}@{{shortSHA .SHA}}](https://github.com/{{ .Repo }}/commit/{{ .SHA }}){{ else }}{{ .Repo }}@{{shortSHA .SHA}}{{ end }}
+{{- end }}
+{{- define "commitOverride" -}}
 BEGIN_COMMIT_OVERRIDE
 {{ range .Commits }}
 BEGIN_NESTED_COMMIT
@@ -115,22 +130,69 @@ BEGIN_NESTED_COMMIT
 
 PiperOrigin-RevId: {{index .Footers "PiperOrigin-RevId"}}
 
-Source-link: [googleapis/googleapis@{{shortSHA .SHA}}](https://github.com/googleapis/googleapis/commit/{{.SHA}})
+Source-link: {{ template "commitLink" (commitLink .SourceRepo .SHA .SourceOnGitHub) }}
 END_NESTED_COMMIT
 {{ end }}
 END_COMMIT_OVERRIDE
-`))
+{{ end }}`))
 )
 
 type generationPRBody struct {
-	StartSHA         string
-	EndSHA           string
+	Ranges           []*generationRange
 	LibrarianVersion string
 	ImageVersion     string
-	Commits          []*conventionalcommits.ConventionalCommit
+	Commits          []*generationCommit
 	FailedLibraries  []string
 }
 
+// generationRange is the range of commits in an API source included in a
+// generation pull request.
+type generationRange struct {
+	Repo     string
+	OnGitHub bool
+	StartSHA string
+	EndSHA   string
+}
+
+// generationCommit is a commit in an API source, with the repository used to
+// refer to it.
+type generationCommit struct {
+	*conventionalcommits.ConventionalCommit
+	SourceRepo     string
+	SourceOnGitHub bool
+}
+
+// commitLink is a commit in a repository. Commits in GitHub repositories are
+// rendered as a link, others as plain `{repo}@{sha}` text.
+type commitLink struct {
+	Repo     string
+	SHA      string
+	OnGitHub bool
+}
+
+func newCommitLink(repo, sha string, onGitHub bool) *commitLink {
+	return &commitLink{Repo: repo, SHA: sha, OnGitHub: onGitHub}
+}
+
+// generationSource is an API source used in a generation, with the last
+// generated commit of each library before the generation.
+type generationSource struct {
+	// The name of the API source, empty for the default API source.
+	name string
+	// The repository used to refer to the commits of the API source: the
+	// `{owner}/{name}` of GitHub repositories, or else the name or the URL of
+	// the API source.
+	repoName string
+	// If true, the API source is a GitHub repository, and its commits are
+	// linked.
+	onGitHub    bool
+	repo        gitrepo.Repository
+	idToCommits map[string]string
+}
+
+// defaultGitHubSource is the GitHub repository of the default API source.
+const defaultGitHubSource = "googleapis/googleapis"
+
 type releaseNote struct {
 	LibrarianVersion string
 	ImageVersion     string
@@ -154,45 +216,20 @@ type commitSection struct {
 }
 
 // formatGenerationPRBody creates the body of a generation pull request.
-// Only consider libraries whose ID appears in idToCommits.
-func formatGenerationPRBody(repo gitrepo.Repository, state *config.LibrarianState, idToCommits map[string]string, failedLibraries []string) (string, error) {
-	var allCommits []*conventionalcommits.ConventionalCommit
-	for _, library := range state.Libraries {
-		lastGenCommit, ok := idToCommits[library.ID]
-		if !ok {
-			continue
-		}
-
-		commits, err := getConventionalCommitsSinceLastGeneration(repo, library, lastGenCommit)
-		if err != nil {
-			return "", fmt.Errorf("failed to fetch conventional commits for library, %s: %w", library.ID, err)
-		}
-		allCommits = append(allCommits, commits...)
+// For each API source, only consider libraries whose ID appears in its
+// idToCommits.
+func formatGenerationPRBody(sources []*generationSource, state *config.LibrarianState, failedLibraries []string) (string, error) {
+	ranges, allCommits, err := generationChanges(sources, state)
+	if err != nil {
+		return "", err
 	}
-
 	if len(allCommits) == 0 {
 		return "No commit is found since last generation", nil
 	}
 
-	startCommit, err := findLatestGenerationCommit(repo, state, idToCommits)
-	if err != nil {
-		return "", fmt.Errorf("failed to find the start commit: %w", err)
-	}
-	// Even though startCommit might be nil, it shouldn't happen in production
-	// because this function will return early if no conventional commit is found
-	// since last generation.
-	startSHA := startCommit.Hash.String()
-
-	// Sort the slice by commit time in reverse order,
-	// so that the latest commit appears first.
-	sort.Slice(allCommits, func(i, j int) bool {
-		return allCommits[i].When.After(allCommits[j].When)
-	})
-	endSHA := allCommits[0].SHA
 	librarianVersion := cli.Version()
 	data := &generationPRBody{
-		StartSHA:         startSHA,
-		EndSHA:           endSHA,
+		Ranges:           ranges,
 		LibrarianVersion: librarianVersion,
 		ImageVersion:     state.Image,
 		Commits:          allCommits,
@@ -206,6 +243,126 @@ func formatGenerationPRBody(repo gitrepo.Repository, state *config.LibrarianStat
 	return strings.TrimSpace(out.String()), nil
 }
 
+// formatGenerationChanges formats the source commit ranges and the commit
+// override block of a generation, for pull requests which regenerate
+// libraries for another reason. It returns an empty string if no commit is
+// found since the last generation.
+func formatGenerationChanges(sources []*generationSource, state *config.LibrarianState) (string, error) {
+	ranges, commits, err := generationChanges(sources, state)
+	if err != nil || len(commits) == 0 {
+		return "", err
+	}
+	data := &generationPRBody{
+		Ranges:  ranges,
+		Commits: commits,
+	}
+	var out bytes.Buffer
+	if err := genBodyTemplate.ExecuteTemplate(&out, "generationRanges", data); err != nil {
+		return "", fmt.Errorf("error executing template: %w", err)
+	}
+	out.WriteString("\n\n")
+	if err := genBodyTemplate.ExecuteTemplate(&out, "commitOverride", data); err != nil {
+		return "", fmt.Errorf("error executing template: %w", err)
+	}
+	return strings.TrimSpace(out.String()), nil
+}
+
+// generationChanges returns the range of commits of each API source since the
+// last generation of the libraries, and the commits themselves, latest first.
+func generationChanges(sources []*generationSource, state *config.LibrarianState) ([]*generationRange, []*generationCommit, error) {
+	var (
+		allCommits []*generationCommit
+		ranges     []*generationRange
+	)
+	for _, source := range sources {
+		var commits []*conventionalcommits.ConventionalCommit
+		for _, library := range state.Libraries {
+			lastGenCommit, ok := source.idToCommits[library.ID]
+			if !ok {
+				continue
+			}
+
+			libraryCommits, err := getConventionalCommitsSinceLastGeneration(source.repo, library, source.name, lastGenCommit)
+			if err != nil {
+				return nil, nil, fmt.Errorf("failed to fetch conventional commits for library, %s: %w", library.ID, err)
+			}
+			commits = append(commits, libraryCommits...)
+		}
+		if len(commits) == 0 {
+			continue
+		}
+
+		startCommit, err := findLatestGenerationCommit(source.repo, state, source.idToCommits)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to find the start commit: %w", err)
+		}
+		// Even though startCommit might be nil, it shouldn't happen in production
+		// because this loop continues early if no conventional commit is found
+		// since last generation.
+		startSHA := startCommit.Hash.String()
+
+		// Sort the slice by commit time in reverse order,
+		// so that the latest commit appears first.
+		sort.Slice(commits, func(i, j int) bool {
+			return commits[i].When.After(commits[j].When)
+		})
+		ranges = append(ranges, &generationRange{
+			Repo:     source.repoName,
+			OnGitHub: source.onGitHub,
+			StartSHA: startSHA,
+			EndSHA:   commits[0].SHA,
+		})
+		for _, commit := range commits {
+			allCommits = append(allCommits, &generationCommit{
+				ConventionalCommit: commit,
+				SourceRepo:         source.repoName,
+				SourceOnGitHub:     source.onGitHub,
+			})
+		}
+	}
+
+	sort.SliceStable(allCommits, func(i, j int) bool {
+		return allCommits[i].When.After(allCommits[j].When)
+	})
+	return ranges, allCommits, nil
+}
+
+// generationSources returns the API sources used in a generation, starting
+// with the default API source.
+//
+// Local directories used as the default API source are assumed to be checkouts
+// of googleapis.
+func generationSources(info *commitInfo) []*generationSource {
+	defaultSource := &generationSource{
+		repoName:    defaultGitHubSource,
+		onGitHub:    true,
+		repo:        info.sourceRepo,
+		idToCommits: info.idToCommits,
+	}
+	if isURL(info.apiSource) {
+		defaultSource.repoName, defaultSource.onGitHub = gitHubSourceName(info.apiSource, info.apiSource)
+	}
+	sources := []*generationSource{defaultSource}
+	if info.state == nil {
+		return sources
+	}
+	for _, apiSource := range info.state.APISources {
+		repo, ok := info.sourceRepos[apiSource.Name]
+		if !ok {
+			continue
+		}
+		repoName, onGitHub := gitHubSourceName(apiSource.URL, apiSource.Name)
+		sources = append(sources, &generationSource{
+			name:        apiSource.Name,
+			repoName:    repoName,
+			onGitHub:    onGitHub,
+			repo:        repo,
+			idToCommits: info.sourceCommits[apiSource.Name],
+		})
+	}
+	return sources
+}
+
 // findLatestGenerationCommit returns the latest commit among the last generated
 // commit of all the libraries.
 // A libray is skipped if the last generated commit is empty.
@@ -238,10 +395,16 @@ func findLatestGenerationCommit(repo gitrepo.Repository, state *config.Librarian
 }
 
 // formatReleaseNotes generates the body for a release pull request.
-func formatReleaseNotes(repo gitrepo.Repository, state *config.LibrarianState) (string, error) {
+// Libraries are listed in dependency order, as declared in the librarian
+// config, so that a library always appears after its dependencies.
+func formatReleaseNotes(repo gitrepo.Repository, state *config.LibrarianState, librarianConfig *config.LibrarianConfig) (string, error) {
 	librarianVersion := cli.Version()
+	libraries, err := sortLibrariesByDependencies(state, librarianConfig)
+	if err != nil {
+		return "", err
+	}
 	var releaseSections []*releaseNoteSection
-	for _, library := range state.Libraries {
+	for _, library := range libraries {
 		if !library.ReleaseTriggered {
 			continue
 		}
@@ -353,7 +353,10 @@ END_COMMIT_OVERRIDE`,
 		},
 	} {
 		t.Run(test.name, func(t *testing.T) {
-			got, err := formatGenerationPRBody(test.repo, test.state, test.idToCommits, test.failedLibraries)
+			sources := []*generationSource{
+				{repoName: defaultGitHubSource, onGitHub: true, repo: test.repo, idToCommits: test.idToCommits},
+			}
+			got, err := formatGenerationPRBody(sources, test.state, test.failedLibraries)
 			if test.wantErr {
 				if err == nil {
 					t.Fatalf("%s should return error", test.name)
@@ -373,6 +376,125 @@ END_COMMIT_OVERRIDE`,
 	}
 }
 
+func TestFormatGenerationPRBodyMultipleSources(t *testing.T) {
+	t.Parallel()
+
+	for _, test := range []struct {
+		name       string
+		privateURL string
+		// The range and the source link of the commits in the private API
+		// source.
+		wantRange      string
+		wantSourceLink string
+	}{
+		{
+			name:       "github source",
+			privateURL: "https://github.com/example/private-apis",
+			wantRange: `It also includes changes in example/private-apis between
+[example/private-apis@bbbbbbb](https://github.com/example/private-apis/commit/bbbbbbbb00000000000000000000000000000000)
+(exclusive) and
+[example/private-apis@fedcba0](https://github.com/example/private-apis/commit/fedcba0987654321000000000000000000000000)
+(inclusive).`,
+			wantSourceLink: "Source-link: [example/private-apis@fedcba0](https://github.com/example/private-apis/commit/fedcba0987654321000000000000000000000000)",
+		},
+		{
+			name:       "source not on github",
+			privateURL: "https://gitlab.com/example/private-apis",
+			wantRange: `It also includes changes in private between
+private@bbbbbbb
+(exclusive) and
+private@fedcba0
+(inclusive).`,
+			wantSourceLink: "Source-link: private@fedcba0",
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			today := time.Now()
+			publicHash := plumbing.NewHash("1234567890abcdef")
+			privateHash := plumbing.NewHash("fedcba0987654321")
+			state := &config.LibrarianState{
+				Image:      "go:1.21",
+				APISources: []*config.APISource{{Name: "private", URL: test.privateURL}},
+				Libraries: []*config.LibraryState{
+					{
+						ID: "one-library",
+						APIs: []*config.API{
+							{Path: "google/one/v1"},
+							{Path: "example/one/v1", Source: "private"},
+						},
+					},
+				},
+			}
+			public := &MockRepository{
+				GetCommitByHash: map[string]*gitrepo.Commit{
+					"aaaaaaaa": {Hash: plumbing.NewHash("aaaaaaaa"), When: time.UnixMilli(200)},
+				},
+				GetCommitsForPathsSinceLastGenByCommit: map[string][]*gitrepo.Commit{
+					"aaaaaaaa": {{Message: "feat: a public feature", Hash: publicHash, When: today}},
+				},
+				ChangedFilesInCommitValueByHash: map[string][]string{publicHash.String(): {"google/one/v1/one.proto"}},
+			}
+			private := &MockRepository{
+				GetCommitByHash: map[string]*gitrepo.Commit{
+					"bbbbbbbb": {Hash: plumbing.NewHash("bbbbbbbb"), When: time.UnixMilli(300)},
+				},
+				GetCommitsForPathsSinceLastGenByCommit: map[string][]*gitrepo.Commit{
+					"bbbbbbbb": {{Message: "fix: a private fix", Hash: privateHash, When: today.Add(time.Hour)}},
+				},
+				ChangedFilesInCommitValueByHash: map[string][]string{privateHash.String(): {"example/one/v1/one.proto"}},
+			}
+			info := &commitInfo{
+				apiSource:     "https://github.com/googleapis/googleapis",
+				sourceRepo:    public,
+				sourceRepos:   map[string]gitrepo.Repository{"private": private},
+				idToCommits:   map[string]string{"one-library": "aaaaaaaa"},
+				sourceCommits: map[string]map[string]string{"private": {"one-library": "bbbbbbbb"}},
+				state:         state,
+			}
+			got, err := formatGenerationPRBody(generationSources(info), state, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			want := fmt.Sprintf(`This pull request is generated with proto changes between
+[googleapis/googleapis@aaaaaaa](https://github.com/googleapis/googleapis/commit/aaaaaaaa00000000000000000000000000000000)
+(exclusive) and
+[googleapis/googleapis@1234567](https://github.com/googleapis/googleapis/commit/1234567890abcdef000000000000000000000000)
+(inclusive).
+
+%s
+
+Librarian Version: %s
+Language Image: go:1.21
+
+BEGIN_COMMIT_OVERRIDE
+
+BEGIN_NESTED_COMMIT
+fix: [one-library] a private fix
+
+
+PiperOrigin-RevId: 
+
+%s
+END_NESTED_COMMIT
+
+BEGIN_NESTED_COMMIT
+feat: [one-library] a public feature
+
+
+PiperOrigin-RevId: 
+
+Source-link: [googleapis/googleapis@1234567](https://github.com/googleapis/googleapis/commit/1234567890abcdef000000000000000000000000)
+END_NESTED_COMMIT
+
+END_COMMIT_OVERRIDE`, test.wantRange, cli.Version(), test.wantSourceLink)
+			if diff := cmp.Diff(want, got); diff != "" {
+				t.Errorf("formatGenerationPRBody() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
 func TestFindLatestCommit(t *testing.T) {
 	t.Parallel()
 
@@ -488,6 +610,7 @@ func TestFormatReleaseNotes(t *testing.T) {
 	for _, test := range []struct {
 		name            string
 		state           *config.LibrarianState
+		librarianConfig *config.LibrarianConfig
 		repo            gitrepo.Repository
 		wantReleaseNote string
 		wantErr         bool
@@ -739,6 +862,90 @@ Language Image: go:1.21
 </details>`,
 				librarianVersion, today),
 		},
+		{
+			name: "libraries in dependency order, with dependency update",
+			state: &config.LibrarianState{
+				Image: "go:1.21",
+				Libraries: []*config.LibraryState{
+					{
+						ID:               "dependent",
+						Version:          "1.0.1",
+						PreviousVersion:  "1.0.0",
+						ReleaseTriggered: true,
+						Changes: []*conventionalcommits.ConventionalCommit{
+							{
+								Type:    "fix",
+								Scope:   "deps",
+								Subject: "dependency core updated to 2.1.0",
+							},
+						},
+					},
+					{
+						ID:               "core",
+						Version:          "2.1.0",
+						PreviousVersion:  "2.0.0",
+						ReleaseTriggered: true,
+						Changes: []*conventionalcommits.ConventionalCommit{
+							{
+								Type:    "feat",
+								Subject: "new feature",
+								SHA:     hash1.String(),
+							},
+						},
+					},
+				},
+			},
+			librarianConfig: &config.LibrarianConfig{
+				Libraries: []*config.LibraryConfig{
+					{LibraryID: "dependent", Dependencies: []string{"core"}},
+				},
+			},
+			repo: &MockRepository{
+				RemotesValue: []*git.Remote{git.NewRemote(nil, &gitconfig.RemoteConfig{Name: "origin", URLs: []string{"https://github.com/owner/repo.git"}})},
+			},
+			wantReleaseNote: fmt.Sprintf(`Librarian Version: %s
+Language Image: go:1.21
+<details><summary>core: 2.1.0</summary>
+
+## [2.1.0](https://github.com/owner/repo/compare/core-2.0.0...core-2.1.0) (%s)
+
+### Features
+
+* new feature ([1234567](https://github.com/owner/repo/commit/1234567890abcdef000000000000000000000000))
+
+</details>
+
+
+<details><summary>dependent: 1.0.1</summary>
+
+## [1.0.1](https://github.com/owner/repo/compare/dependent-1.0.0...dependent-1.0.1) (%s)
+
+### Bug Fixes
+
+* **deps:** dependency core updated to 2.1.0
+
+</details>`,
+				librarianVersion, today, today),
+		},
+		{
+			name: "dependency cycle",
+			state: &config.LibrarianState{
+				Image: "go:1.21",
+				Libraries: []*config.LibraryState{
+					{ID: "a", ReleaseTriggered: true},
+					{ID: "b", ReleaseTriggered: true},
+				},
+			},
+			librarianConfig: &config.LibrarianConfig{
+				Libraries: []*config.LibraryConfig{
+					{LibraryID: "a", Dependencies: []string{"b"}},
+					{LibraryID: "b", Dependencies: []string{"a"}},
+				},
+			},
+			repo:          &MockRepository{},
+			wantErr:       true,
+			wantErrPhrase: "dependency cycle",
+		},
 		{
 			name: "no releases",
 			state: &config.LibrarianState{
@@ -769,7 +976,7 @@ Language Image: go:1.21
 	} {
 		t.Run(test.name, func(t *testing.T) {
 			t.Parallel()
-			got, err := formatReleaseNotes(test.repo, test.state)
+			got, err := formatReleaseNotes(test.repo, test.state, test.librarianConfig)
 			if test.wantErr {
 				if err == nil {
 					t.Fatalf("%s should return error", test.name)
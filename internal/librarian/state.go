@@ -116,8 +116,17 @@ func populateServiceConfigIfEmpty(state *config.LibrarianState, source string) e
 		slog.Info("source not specified, skipping service config population")
 		return nil
 	}
+	return populateServiceConfigForSource(state, "", source)
+}
+
+// populateServiceConfigForSource fills the missing service configs for the APIs
+// in the API source called `name`, found in the `source` directory.
+func populateServiceConfigForSource(state *config.LibrarianState, name, source string) error {
 	for i, library := range state.Libraries {
 		for j, api := range library.APIs {
+			if api.Source != name {
+				continue
+			}
 			if api.ServiceConfig != "" {
 				// Do not change API if the service config has already been set.
 				continue
@@ -258,6 +258,43 @@ func TestPopulateServiceConfig(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "skip apis from other sources",
+			state: &config.LibrarianState{
+				Libraries: []*config.LibraryState{
+					{
+						ID: "example-id",
+						APIs: []*config.API{
+							{
+								Path: "example/api",
+							},
+							{
+								Path:   "non-existed/example/api",
+								Source: "private",
+							},
+						},
+					},
+				},
+			},
+			path: filepath.Join("..", "..", "testdata", "populate_service_config"),
+			want: &config.LibrarianState{
+				Libraries: []*config.LibraryState{
+					{
+						ID: "example-id",
+						APIs: []*config.API{
+							{
+								Path:          "example/api",
+								ServiceConfig: "example_api_config.yaml",
+							},
+							{
+								Path:   "non-existed/example/api",
+								Source: "private",
+							},
+						},
+					},
+				},
+			},
+		},
 		{
 			name: "non valid api path",
 			state: &config.LibrarianState{
@@ -27,7 +27,9 @@ import (
 	"time"
 
 	"github.com/googleapis/librarian/internal/config"
+	"github.com/googleapis/librarian/internal/docker"
 	"github.com/googleapis/librarian/internal/github"
+	"github.com/googleapis/librarian/internal/gitrepo"
 )
 
 const (
@@ -45,6 +47,25 @@ var (
 type tagAndReleaseRunner struct {
 	ghClient    GitHubClient
 	pullRequest string
+	// library restricts the releases of each pull request to a single
+	// library, for example to publish it again after a failure. The labels
+	// of the pull request are left unchanged.
+	library string
+
+	// releaseArtifacts determines whether the release artifacts of each
+	// library are packaged by the container, and attached to its release.
+	releaseArtifacts bool
+	// publish determines whether each library is published by the container,
+	// with dryRun asking the container to only check that it can be
+	// published. A dry run does not write to GitHub: the tags, releases,
+	// release assets, comments and labels are only logged.
+	publish bool
+	dryRun  bool
+	// The fields below are only set when releaseArtifacts or publish is true.
+	containerClient ContainerClient
+	hostMount       string
+	repo            gitrepo.Repository
+	workRoot        string
 }
 
 func newTagAndReleaseRunner(cfg *config.Config) (*tagAndReleaseRunner, error) {
@@ -57,18 +78,47 @@ func newTagAndReleaseRunner(cfg *config.Config) (*tagAndReleaseRunner, error) {
 	}
 	ghClient := github.NewClient(cfg.GitHubToken, repo)
 	// If a custom GitHub API endpoint is provided (for testing),
-	// parse it and set it as the BaseURL on the GitHub client.
+	// parse it and set it as the BaseURL on the GitHub client, with the
+	// matching uploads API for release artifacts.
 	if cfg.GitHubAPIEndpoint != "" {
 		endpoint, err := url.Parse(cfg.GitHubAPIEndpoint)
 		if err != nil {
 			return nil, fmt.Errorf("failed to parse github-api-endpoint: %w", err)
 		}
 		ghClient.BaseURL = endpoint
+		ghClient.UploadURL = github.UploadURL(endpoint)
 	}
-	return &tagAndReleaseRunner{
+	runner := &tagAndReleaseRunner{
 		ghClient:    ghClient,
 		pullRequest: cfg.PullRequest,
-	}, nil
+		library:     cfg.Library,
+	}
+	if !cfg.ReleaseArtifacts && !cfg.Publish {
+		return runner, nil
+	}
+
+	// Packaging and publishing libraries require a full clone, to check out
+	// the merge commit of each release pull request.
+	languageRepo, err := cloneOrOpenRepo(cfg.WorkRoot, cfg.Repo, 0, cfg.Branch, cfg.CI, cfg.GitHubToken, nil)
+	if err != nil {
+		return nil, err
+	}
+	state, err := loadRepoState(languageRepo, "")
+	if err != nil {
+		return nil, err
+	}
+	container, err := docker.New(cfg.WorkRoot, deriveImage(cfg.Image, state), cfg.UserUID, cfg.UserGID)
+	if err != nil {
+		return nil, err
+	}
+	runner.releaseArtifacts = cfg.ReleaseArtifacts
+	runner.publish = cfg.Publish
+	runner.dryRun = cfg.DryRun
+	runner.containerClient = container
+	runner.hostMount = cfg.HostMount
+	runner.repo = languageRepo
+	runner.workRoot = cfg.WorkRoot
+	return runner, nil
 }
 
 func (r *tagAndReleaseRunner) run(ctx context.Context) error {
@@ -81,6 +131,9 @@ func (r *tagAndReleaseRunner) run(ctx context.Context) error {
 		slog.Info("no pull requests to process, exiting")
 		return nil
 	}
+	if err := r.checkContainerCommands(ctx); err != nil {
+		return err
+	}
 
 	var hadErrors bool
 	for _, p := range prs {
@@ -135,6 +188,15 @@ func (r *tagAndReleaseRunner) processPullRequest(ctx context.Context, p *github.
 		slog.Warn("no release details found in pull request body, skipping")
 		return nil
 	}
+	if r.library != "" {
+		releases = slices.DeleteFunc(releases, func(release libraryRelease) bool {
+			return release.Library != r.library
+		})
+		if len(releases) == 0 {
+			slog.Info("library is not released by pull request, skipping", "library", r.library, "pr", p.GetNumber())
+			return nil
+		}
+	}
 
 	// Load library state from remote repo
 	libraryState, err := loadRepoStateFromGitHub(ctx, r.ghClient, *p.Base.Ref)
@@ -146,9 +208,23 @@ func (r *tagAndReleaseRunner) processPullRequest(ctx context.Context, p *github.
 	// TODO: remove this logic as part of https://github.com/googleapis/librarian/issues/2044
 	commitSha := p.GetMergeCommitSHA()
 	tagName := fmt.Sprintf("release-please-%d", p.GetNumber())
-	if err := r.ghClient.CreateTag(ctx, tagName, commitSha); err != nil {
+	if r.dryRun {
+		slog.Info("dry run, skipping tag creation", "tag", tagName, "commit", commitSha)
+	} else if err := r.ghClient.CreateTag(ctx, tagName, commitSha); err != nil {
 		return fmt.Errorf("failed to create tag %s: %w", tagName, err)
 	}
+	if r.releaseArtifacts || r.publish {
+		// The release artifacts and published packages are built from the
+		// files on disk, which must be those of the release commit.
+		if err := r.repo.ForceCheckoutCommit(commitSha); err != nil {
+			return fmt.Errorf("failed to check out release commit %s: %w", commitSha, err)
+		}
+	}
+	// All the tags and releases are created before packaging and publishing
+	// the libraries, so that a failure to package or publish one library
+	// does not prevent releasing the others.
+	releaseIDs := make(map[string]int64)
+	var publishRequests []*docker.PublishRequest
 	for _, release := range releases {
 		slog.Info("creating release", "library", release.Library, "version", release.Version)
 
@@ -161,12 +237,85 @@ func (r *tagAndReleaseRunner) processPullRequest(ctx context.Context, p *github.
 		// Create the release.
 		tagName := formatTag(tagFormat, release.Library, release.Version)
 		releaseName := fmt.Sprintf("%s %s", release.Library, release.Version)
-		if _, err := r.ghClient.CreateRelease(ctx, tagName, releaseName, release.Body, commitSha); err != nil {
-			return fmt.Errorf("failed to create release: %w", err)
+		if r.dryRun {
+			slog.Info("dry run, skipping release creation", "tag", tagName, "name", releaseName)
+		} else {
+			ghRelease, err := r.ghClient.CreateRelease(ctx, tagName, releaseName, release.Body, commitSha)
+			if err != nil {
+				return fmt.Errorf("failed to create release: %w", err)
+			}
+			releaseIDs[release.Library] = ghRelease.GetID()
+		}
+		if r.publish {
+			publishRequests = append(publishRequests, &docker.PublishRequest{
+				DryRun:    r.dryRun,
+				HostMount: r.hostMount,
+				LibraryID: release.Library,
+				RepoDir:   r.repo.GetDir(),
+				State:     libraryState,
+				Tag:       tagName,
+				Version:   strings.TrimPrefix(release.Version, "v"),
+			})
+		}
+	}
+
+	// Packaging and publishing failures are reported after marking the
+	// release as done, as the tags and releases have been created.
+	var errs []error
+	if r.releaseArtifacts {
+		for _, release := range releases {
+			if err := r.uploadReleaseArtifacts(ctx, releaseIDs[release.Library], libraryState, release.Library); err != nil {
+				slog.Error("failed to upload release artifacts", "library", release.Library, "error", err)
+				errs = append(errs, err)
+			}
+		}
+	}
+	if r.publish {
+		if err := r.publishLibraries(ctx, p.GetNumber(), publishRequests); err != nil {
+			errs = append(errs, err)
 		}
+	}
+	// Libraries failing to publish can be published again with --library,
+	// so the release is done even if some of them failed.
+	switch {
+	case r.dryRun:
+		slog.Info("dry run, skipping label replacement", "pr", p.GetNumber(), "label", releaseDoneLabel)
+	case r.library != "":
+		slog.Info("processed a single library, leaving labels unchanged", "library", r.library, "pr", p.GetNumber())
+	default:
+		if err := r.replacePendingLabel(ctx, p); err != nil {
+			return err
+		}
+	}
+	return errors.Join(errs...)
+}
 
+// checkContainerCommands checks that the container implements the commands
+// required to package and publish the released libraries, if any.
+func (r *tagAndReleaseRunner) checkContainerCommands(ctx context.Context) error {
+	var commands []docker.Command
+	if r.releaseArtifacts {
+		commands = append(commands, docker.CommandPackage)
 	}
-	return r.replacePendingLabel(ctx, p)
+	if r.publish {
+		commands = append(commands, docker.CommandPublish)
+	}
+	if len(commands) == 0 {
+		return nil
+	}
+	capabilities, err := r.containerClient.Describe(ctx, &docker.DescribeRequest{
+		HostMount: r.hostMount,
+		RepoDir:   r.repo.GetDir(),
+	})
+	if err != nil {
+		return err
+	}
+	for _, command := range commands {
+		if !capabilities.Supports(command) {
+			return fmt.Errorf("container does not support the %s command", command)
+		}
+	}
+	return nil
 }
 
 func determineTagFormat(libraryID string, librarianState *config.LibrarianState) (string, error) {
@@ -16,13 +16,19 @@ package librarian
 
 import (
 	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
 	"strings"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
 	gh "github.com/google/go-github/v69/github"
 	"github.com/googleapis/librarian/internal/config"
+	"github.com/googleapis/librarian/internal/docker"
 	"github.com/googleapis/librarian/internal/github"
+	"github.com/googleapis/librarian/internal/gitrepo"
 )
 
 func TestNewTagAndReleaseRunner(t *testing.T) {
@@ -490,6 +496,376 @@ func TestProcessPullRequest(t *testing.T) {
 	}
 }
 
+func TestProcessPullRequest_ReleaseArtifacts(t *testing.T) {
+	pr := &github.PullRequest{
+		Body:           gh.Ptr(`<details><summary>google-cloud-storage: v1.2.3</summary>release notes</details>`),
+		Number:         gh.Ptr(123),
+		MergeCommitSHA: gh.Ptr("abcdef"),
+		Base:           &gh.PullRequestBranch{Ref: gh.Ptr("main")},
+	}
+	ghClient := &mockGitHubClient{
+		createdRelease: &github.RepositoryRelease{ID: gh.Ptr(int64(42))},
+		librarianState: &config.LibrarianState{
+			Image: "gcr.io/some-project-id/some-test-image:latest",
+			Libraries: []*config.LibraryState{
+				{ID: "google-cloud-storage", SourceRoots: []string{"storage"}, TagFormat: "v{version}"},
+			},
+		},
+	}
+	containerClient := &mockContainerClient{
+		packageArtifacts: map[string]string{"storage-1.2.3.tar.gz": "archive"},
+	}
+	repo := &MockRepository{Dir: t.TempDir()}
+	r := &tagAndReleaseRunner{
+		ghClient:         ghClient,
+		releaseArtifacts: true,
+		containerClient:  containerClient,
+		repo:             repo,
+		workRoot:         t.TempDir(),
+	}
+	if err := r.processPullRequest(t.Context(), pr); err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff([]string{"abcdef"}, repo.ForceCheckoutCommitCalls); diff != "" {
+		t.Errorf("ForceCheckoutCommit() calls mismatch (-want +got):\n%s", diff)
+	}
+	if containerClient.packageCalls != 1 {
+		t.Errorf("Package() calls = %d, want 1", containerClient.packageCalls)
+	}
+	var gotAssets []string
+	for name := range ghClient.uploadedAssets {
+		gotAssets = append(gotAssets, name)
+	}
+	slices.Sort(gotAssets)
+	if diff := cmp.Diff([]string{checksumsFileName, "storage-1.2.3.tar.gz"}, gotAssets); diff != "" {
+		t.Errorf("uploaded assets mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestProcessPullRequest_ReleaseArtifactsFailure(t *testing.T) {
+	pr := &github.PullRequest{
+		Body: gh.Ptr(`<details><summary>google-cloud-storage: v1.2.3</summary>release notes</details>
+<details><summary>google-cloud-pubsub: v2.0.0</summary>release notes</details>`),
+		Number:         gh.Ptr(123),
+		MergeCommitSHA: gh.Ptr("abcdef"),
+		Labels:         []*gh.Label{{Name: gh.Ptr(releasePendingLabel)}},
+		Base:           &gh.PullRequestBranch{Ref: gh.Ptr("main")},
+	}
+	ghClient := &mockGitHubClient{
+		createdRelease: &github.RepositoryRelease{ID: gh.Ptr(int64(42))},
+		librarianState: &config.LibrarianState{
+			Image: "gcr.io/some-project-id/some-test-image:latest",
+			Libraries: []*config.LibraryState{
+				{ID: "google-cloud-storage", SourceRoots: []string{"storage"}, TagFormat: "{id}-v{version}"},
+				{ID: "google-cloud-pubsub", SourceRoots: []string{"pubsub"}, TagFormat: "{id}-v{version}"},
+			},
+		},
+	}
+	containerClient := &mockContainerClient{packageErr: errors.New("package error")}
+	r := &tagAndReleaseRunner{
+		ghClient:         ghClient,
+		releaseArtifacts: true,
+		containerClient:  containerClient,
+		repo:             &MockRepository{Dir: t.TempDir()},
+		workRoot:         t.TempDir(),
+	}
+	err := r.processPullRequest(t.Context(), pr)
+	for _, want := range []string{
+		"failed to package release artifacts for google-cloud-storage",
+		"failed to package release artifacts for google-cloud-pubsub",
+	} {
+		if err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("processPullRequest() error = %v, want %q", err, want)
+		}
+	}
+	// All the releases are created before packaging any library, and the
+	// release is done even if packaging failed.
+	if ghClient.createReleaseCalls != 2 {
+		t.Errorf("CreateRelease() calls = %d, want 2", ghClient.createReleaseCalls)
+	}
+	if containerClient.packageCalls != 2 {
+		t.Errorf("Package() calls = %d, want 2", containerClient.packageCalls)
+	}
+	if ghClient.replaceLabelsCalls != 1 {
+		t.Errorf("ReplaceLabels() calls = %d, want 1", ghClient.replaceLabelsCalls)
+	}
+}
+
+func TestProcessPullRequest_Publish(t *testing.T) {
+	pr := &github.PullRequest{
+		Body: gh.Ptr(`<details><summary>google-cloud-storage: v1.2.3</summary>release notes</details>
+<details><summary>google-cloud-pubsub: v2.0.0</summary>release notes</details>`),
+		Number:         gh.Ptr(123),
+		MergeCommitSHA: gh.Ptr("abcdef"),
+		Labels:         []*gh.Label{{Name: gh.Ptr(releasePendingLabel)}},
+		Base:           &gh.PullRequestBranch{Ref: gh.Ptr("main")},
+	}
+	state := &config.LibrarianState{
+		Image: "gcr.io/some-project-id/some-test-image:latest",
+		Libraries: []*config.LibraryState{
+			{ID: "google-cloud-storage", SourceRoots: []string{"storage"}, TagFormat: "{id}-v{version}"},
+			{ID: "google-cloud-pubsub", SourceRoots: []string{"pubsub"}, TagFormat: "{id}-v{version}"},
+		},
+	}
+	for _, test := range []struct {
+		name                   string
+		dryRun                 bool
+		library                string
+		failPublishForID       string
+		wantRegistry           map[string]string
+		wantPublishCalls       int
+		wantCreateTagCalls     int
+		wantCreateReleaseCalls int
+		wantComments           int
+		wantReplaceLabelsCalls int
+		wantErrMsg             string
+	}{
+		{
+			name: "publish",
+			wantRegistry: map[string]string{
+				"google-cloud-storage": "1.2.3",
+				"google-cloud-pubsub":  "2.0.0",
+			},
+			wantPublishCalls:       2,
+			wantCreateTagCalls:     1,
+			wantCreateReleaseCalls: 2,
+			wantComments:           2,
+			wantReplaceLabelsCalls: 1,
+		},
+		{
+			// A dry run does not write to GitHub.
+			name:             "dry run",
+			dryRun:           true,
+			wantPublishCalls: 2,
+		},
+		{
+			// The release is done even if a library failed to publish.
+			name:             "publish failure",
+			failPublishForID: "google-cloud-storage",
+			wantRegistry: map[string]string{
+				"google-cloud-pubsub": "2.0.0",
+			},
+			wantPublishCalls:       2,
+			wantCreateTagCalls:     1,
+			wantCreateReleaseCalls: 2,
+			wantComments:           2,
+			wantReplaceLabelsCalls: 1,
+			wantErrMsg:             "failed to publish libraries: google-cloud-storage",
+		},
+		{
+			// A single library is published again, leaving the labels
+			// unchanged.
+			name:    "single library",
+			library: "google-cloud-storage",
+			wantRegistry: map[string]string{
+				"google-cloud-storage": "1.2.3",
+			},
+			wantPublishCalls:       1,
+			wantCreateTagCalls:     1,
+			wantCreateReleaseCalls: 1,
+			wantComments:           1,
+		},
+		{
+			name:    "library not released",
+			library: "google-cloud-spanner",
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			ghClient := &mockGitHubClient{librarianState: state}
+			containerClient := &mockContainerClient{failPublishForID: test.failPublishForID}
+			repo := &MockRepository{Dir: t.TempDir()}
+			r := &tagAndReleaseRunner{
+				ghClient:        ghClient,
+				library:         test.library,
+				publish:         true,
+				dryRun:          test.dryRun,
+				containerClient: containerClient,
+				repo:            repo,
+				workRoot:        t.TempDir(),
+			}
+			err := r.processPullRequest(t.Context(), pr)
+			if test.wantErrMsg != "" {
+				if err == nil || !strings.Contains(err.Error(), test.wantErrMsg) {
+					t.Fatalf("processPullRequest() error = %v, want %q", err, test.wantErrMsg)
+				}
+			} else if err != nil {
+				t.Fatal(err)
+			}
+			// The libraries are published from the merge commit.
+			var wantCheckouts []string
+			if test.wantPublishCalls != 0 {
+				wantCheckouts = []string{"abcdef"}
+			}
+			if diff := cmp.Diff(wantCheckouts, repo.ForceCheckoutCommitCalls); diff != "" {
+				t.Errorf("ForceCheckoutCommit() calls mismatch (-want +got):\n%s", diff)
+			}
+			if containerClient.publishCalls != test.wantPublishCalls {
+				t.Errorf("Publish() calls = %d, want %d", containerClient.publishCalls, test.wantPublishCalls)
+			}
+			if diff := cmp.Diff(test.wantRegistry, containerClient.registry); diff != "" {
+				t.Errorf("published versions mismatch (-want +got):\n%s", diff)
+			}
+			if ghClient.createTagCalls != test.wantCreateTagCalls {
+				t.Errorf("CreateTag() calls = %d, want %d", ghClient.createTagCalls, test.wantCreateTagCalls)
+			}
+			if ghClient.createReleaseCalls != test.wantCreateReleaseCalls {
+				t.Errorf("CreateRelease() calls = %d, want %d", ghClient.createReleaseCalls, test.wantCreateReleaseCalls)
+			}
+			if ghClient.createIssueCommentCalls != test.wantComments {
+				t.Errorf("CreateIssueComment() calls = %d, want %d", ghClient.createIssueCommentCalls, test.wantComments)
+			}
+			if ghClient.replaceLabelsCalls != test.wantReplaceLabelsCalls {
+				t.Errorf("ReplaceLabels() calls = %d, want %d", ghClient.replaceLabelsCalls, test.wantReplaceLabelsCalls)
+			}
+		})
+	}
+}
+
+func TestProcessPullRequest_ReleaseCommit(t *testing.T) {
+	dir := t.TempDir()
+	runGit(t, dir, "init")
+	runGit(t, dir, "config", "user.email", "test@example.com")
+	runGit(t, dir, "config", "user.name", "Test User")
+	writeVersion := func(version string) string {
+		t.Helper()
+		if err := os.MkdirAll(filepath.Join(dir, "storage"), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, "storage", "version.txt"), []byte(version), 0644); err != nil {
+			t.Fatal(err)
+		}
+		runGit(t, dir, "add", ".")
+		runGit(t, dir, "commit", "-m", "chore: version "+version)
+		out, err := exec.Command("git", "-C", dir, "rev-parse", "HEAD").Output()
+		if err != nil {
+			t.Fatal(err)
+		}
+		return strings.TrimSpace(string(out))
+	}
+	mergeCommit := writeVersion("1.2.3")
+	// The branch moved past the merge commit of the release pull request.
+	writeVersion("1.3.0-dev")
+	repo, err := gitrepo.NewRepository(&gitrepo.RepositoryOptions{Dir: dir})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pr := &github.PullRequest{
+		Body:           gh.Ptr(`<details><summary>google-cloud-storage: v1.2.3</summary>release notes</details>`),
+		Number:         gh.Ptr(123),
+		MergeCommitSHA: gh.Ptr(mergeCommit),
+		Labels:         []*gh.Label{{Name: gh.Ptr(releasePendingLabel)}},
+		Base:           &gh.PullRequestBranch{Ref: gh.Ptr("main")},
+	}
+	ghClient := &mockGitHubClient{
+		createdRelease: &github.RepositoryRelease{ID: gh.Ptr(int64(42))},
+		librarianState: &config.LibrarianState{
+			Image: "gcr.io/some-project-id/some-test-image:latest",
+			Libraries: []*config.LibraryState{
+				{ID: "google-cloud-storage", SourceRoots: []string{"storage"}, TagFormat: "{id}-v{version}"},
+			},
+		},
+	}
+	containerClient := &mockContainerClient{sourceFile: filepath.Join("storage", "version.txt")}
+	r := &tagAndReleaseRunner{
+		ghClient:         ghClient,
+		releaseArtifacts: true,
+		publish:          true,
+		containerClient:  containerClient,
+		repo:             repo,
+		workRoot:         t.TempDir(),
+	}
+	if err := r.processPullRequest(t.Context(), pr); err != nil {
+		t.Fatal(err)
+	}
+	// Both the package and the publish commands see the sources of the
+	// merge commit, not those of the branch.
+	if diff := cmp.Diff([]string{"1.2.3", "1.2.3"}, containerClient.sourceContents); diff != "" {
+		t.Errorf("sources seen by the container mismatch (-want +got):\n%s", diff)
+	}
+	head, err := repo.HeadHash()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if head != mergeCommit {
+		t.Errorf("HeadHash() = %s, want %s", head, mergeCommit)
+	}
+}
+
+func TestCheckContainerCommands(t *testing.T) {
+	for _, test := range []struct {
+		name             string
+		releaseArtifacts bool
+		publish          bool
+		containerClient  *mockContainerClient
+		wantDescribe     bool
+		wantErrMsg       string
+	}{
+		{
+			name:            "no container commands",
+			containerClient: &mockContainerClient{},
+		},
+		{
+			name:             "supported",
+			releaseArtifacts: true,
+			publish:          true,
+			containerClient: &mockContainerClient{
+				capabilities: &docker.Capabilities{
+					ContractVersion: 1,
+					Commands:        []docker.Command{docker.CommandPackage, docker.CommandPublish},
+				},
+			},
+			wantDescribe: true,
+		},
+		{
+			name:             "legacy container",
+			releaseArtifacts: true,
+			containerClient:  &mockContainerClient{},
+			wantDescribe:     true,
+			wantErrMsg:       "container does not support the package command",
+		},
+		{
+			name:    "publish not supported",
+			publish: true,
+			containerClient: &mockContainerClient{
+				capabilities: &docker.Capabilities{
+					ContractVersion: 1,
+					Commands:        []docker.Command{docker.CommandPackage},
+				},
+			},
+			wantDescribe: true,
+			wantErrMsg:   "container does not support the publish command",
+		},
+		{
+			name:            "describe error",
+			publish:         true,
+			containerClient: &mockContainerClient{describeErr: errors.New("describe error")},
+			wantDescribe:    true,
+			wantErrMsg:      "describe error",
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			r := &tagAndReleaseRunner{
+				releaseArtifacts: test.releaseArtifacts,
+				publish:          test.publish,
+				containerClient:  test.containerClient,
+				repo:             &MockRepository{Dir: t.TempDir()},
+			}
+			err := r.checkContainerCommands(t.Context())
+			if test.wantErrMsg != "" {
+				if err == nil || !strings.Contains(err.Error(), test.wantErrMsg) {
+					t.Fatalf("checkContainerCommands() error = %v, want %q", err, test.wantErrMsg)
+				}
+			} else if err != nil {
+				t.Fatal(err)
+			}
+			if got := test.containerClient.describeCalls == 1; got != test.wantDescribe {
+				t.Errorf("Describe() called = %t, want %t", got, test.wantDescribe)
+			}
+		})
+	}
+}
+
 func TestReplacePendingLabel(t *testing.T) {
 	prWithPending := &github.PullRequest{
 		Number: gh.Ptr(123),
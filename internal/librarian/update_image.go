@@ -0,0 +1,284 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package librarian
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+
+	"github.com/googleapis/librarian/internal/cli"
+	"github.com/googleapis/librarian/internal/config"
+)
+
+const updateImageCmdName = "update-image"
+
+// The possible results of regenerating a library with a new image.
+const (
+	generationSucceeded = "succeeded"
+	generationFailed    = "failed"
+	generationBlocked   = "blocked"
+)
+
+var updateImageBodyTemplate = template.Must(template.New("updateImageBody").Parse(`This pull request updates the language image from
+` + "`{{.OldImage}}`" + ` to ` + "`{{.NewImage}}`" + ` and regenerates all libraries.
+
+Librarian Version: {{.LibrarianVersion}}
+Language Image: {{.NewImage}}
+
+| Library | Result |
+| :------ | :----- |
+{{- range .Results }}
+| {{.LibraryID}} | {{.Status}} |
+{{- end }}
+{{- if .Failures }}
+
+## Generation failed for
+{{- range .Failures }}
+- {{.LibraryID}}: {{.Error}}
+{{- end }}
+{{- end }}
+`))
+
+// libraryGenerationResult is the result of regenerating a single library with
+// a new image.
+type libraryGenerationResult struct {
+	LibraryID string
+	Status    string
+	// Error is the reason of the failure, if Status is generationFailed.
+	Error string
+}
+
+// imageUpdate records the libraries regenerated by the update-image command.
+type imageUpdate struct {
+	OldImage string
+	NewImage string
+	Results  []*libraryGenerationResult
+}
+
+// failures returns the results of the libraries which failed to generate.
+func (u *imageUpdate) failures() []*libraryGenerationResult {
+	var failures []*libraryGenerationResult
+	for _, result := range u.Results {
+		if result.Status == generationFailed {
+			failures = append(failures, result)
+		}
+	}
+	return failures
+}
+
+// attempts returns the number of libraries which were regenerated, whether
+// successfully or not.
+func (u *imageUpdate) attempts() int {
+	n := 0
+	for _, result := range u.Results {
+		if result.Status != generationBlocked {
+			n++
+		}
+	}
+	return n
+}
+
+type updateImageRunner struct {
+	generator         *generateRunner
+	maxFailurePercent int
+}
+
+func newUpdateImageRunner(cfg *config.Config) (*updateImageRunner, error) {
+	if cfg.Image == "" {
+		return nil, errors.New("update-image requires an image to be specified with the -image flag")
+	}
+	runner, err := newCommandRunner(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return &updateImageRunner{
+		generator: &generateRunner{
+			apiSource:       cfg.APISource,
+			branch:          cfg.Branch,
+			build:           cfg.Build,
+			containerClient: runner.containerClient,
+			ghClient:        runner.ghClient,
+			hostMount:       cfg.HostMount,
+			image:           runner.image,
+			push:            cfg.Push,
+			repo:            runner.repo,
+			sourceRepo:      runner.sourceRepo,
+			sourceRepos:     runner.sourceRepos,
+			state:           runner.state,
+			librarianConfig: runner.librarianConfig,
+			workRoot:        runner.workRoot,
+		},
+		maxFailurePercent: cfg.MaxFailurePercent,
+	}, nil
+}
+
+// run updates the image in the state.yaml file and regenerates every library
+// which is not blocked from generation with the new image.
+//
+// If the percentage of libraries which fail to generate exceeds the maximum
+// failure percentage, the image and the regenerated files are rolled back and
+// no commit is created.
+func (r *updateImageRunner) run(ctx context.Context) error {
+	g := r.generator
+	oldImage := g.state.Image
+	if oldImage == g.image {
+		slog.Info("state.yaml already uses the image, nothing to update", "image", g.image)
+		return nil
+	}
+	outputDir := filepath.Join(g.workRoot, "output")
+	if err := os.Mkdir(outputDir, 0755); err != nil {
+		return fmt.Errorf("failed to make output directory, %s: %w", outputDir, err)
+	}
+	if err := g.describeContainer(ctx); err != nil {
+		return err
+	}
+
+	slog.Info("Updating image", "from", oldImage, "to", g.image)
+	g.state.Image = g.image
+	update := &imageUpdate{
+		OldImage: oldImage,
+		NewImage: g.image,
+	}
+	// As in generate, the last generated commits before the generation are
+	// kept to describe the API changes picked up by the regeneration.
+	idToCommits := make(map[string]string)
+	sourceCommits := make(map[string]map[string]string)
+	var regeneratedRoots []string
+	for _, library := range g.state.Libraries {
+		if g.librarianConfig != nil {
+			libConfig := g.librarianConfig.LibraryConfigFor(library.ID)
+			if libConfig != nil && libConfig.GenerateBlocked {
+				slog.Info("library has generate_blocked, skipping", "id", library.ID)
+				update.Results = append(update.Results, &libraryGenerationResult{
+					LibraryID: library.ID,
+					Status:    generationBlocked,
+				})
+				continue
+			}
+		}
+		regeneratedRoots = append(regeneratedRoots, library.SourceRoots...)
+		oldSourceCommits := g.lastGeneratedSourceCommits(library.ID)
+		oldCommit, err := g.generateSingleLibrary(ctx, library.ID, outputDir)
+		if err != nil {
+			slog.Error("failed to generate library", "id", library.ID, "err", err)
+			update.Results = append(update.Results, &libraryGenerationResult{
+				LibraryID: library.ID,
+				Status:    generationFailed,
+				Error:     err.Error(),
+			})
+			continue
+		}
+		idToCommits[library.ID] = oldCommit
+		addSourceCommits(sourceCommits, library.ID, oldSourceCommits)
+		update.Results = append(update.Results, &libraryGenerationResult{
+			LibraryID: library.ID,
+			Status:    generationSucceeded,
+		})
+	}
+
+	failures := len(update.failures())
+	attempts := update.attempts()
+	slog.Info(
+		"generation statistics",
+		"all", len(g.state.Libraries),
+		"attempts", attempts,
+		"failures", failures)
+	if exceedsFailurePercent(failures, attempts, r.maxFailurePercent) {
+		g.state.Image = oldImage
+		if len(regeneratedRoots) > 0 {
+			if err := g.repo.Restore(regeneratedRoots); err != nil {
+				return fmt.Errorf("failed to roll back regenerated libraries: %w", err)
+			}
+			if err := g.repo.CleanUntracked(regeneratedRoots); err != nil {
+				return fmt.Errorf("failed to roll back regenerated libraries: %w", err)
+			}
+		}
+		return fmt.Errorf("%d of %d libraries failed to generate with image %s (maximum %d%%), image rolled back to %s",
+			failures, attempts, g.image, r.maxFailurePercent, oldImage)
+	}
+
+	if err := saveLibrarianState(g.repo.GetDir(), g.state); err != nil {
+		return err
+	}
+
+	commitInfo := &commitInfo{
+		branch:        g.branch,
+		commit:        g.commit,
+		commitMessage: fmt.Sprintf("chore: update image to %s", g.image),
+		ghClient:      g.ghClient,
+		idToCommits:   idToCommits,
+		imageUpdate:   update,
+		prType:        updateImage,
+		push:          g.push,
+		repo:          g.repo,
+		sourceRepo:    g.sourceRepo,
+		sourceRepos:   g.sourceRepos,
+		sourceCommits: sourceCommits,
+		apiSource:     g.apiSource,
+		state:         g.state,
+	}
+	return commitAndPush(ctx, commitInfo)
+}
+
+// exceedsFailurePercent reports whether failures out of attempts is more than
+// maxPercent percent.
+func exceedsFailurePercent(failures, attempts, maxPercent int) bool {
+	if failures == 0 || attempts == 0 {
+		return false
+	}
+	return failures*100 > attempts*maxPercent
+}
+
+// formatUpdateImagePRBody creates the body of an update-image pull request,
+// listing the result of regenerating each library, followed by the API changes
+// picked up by the regeneration, from the given sources, like in a generation
+// pull request.
+func formatUpdateImagePRBody(update *imageUpdate, sources []*generationSource, state *config.LibrarianState) (string, error) {
+	if update == nil {
+		return "", errors.New("no image update to describe")
+	}
+	data := struct {
+		*imageUpdate
+		LibrarianVersion string
+		Failures         []*libraryGenerationResult
+	}{
+		imageUpdate:      update,
+		LibrarianVersion: cli.Version(),
+		Failures:         update.failures(),
+	}
+	var out bytes.Buffer
+	if err := updateImageBodyTemplate.Execute(&out, data); err != nil {
+		return "", fmt.Errorf("error executing template: %w", err)
+	}
+	body := strings.TrimSpace(out.String())
+	if state == nil {
+		return body, nil
+	}
+	changes, err := formatGenerationChanges(sources, state)
+	if err != nil {
+		return "", err
+	}
+	if changes == "" {
+		return body, nil
+	}
+	return body + "\n\n" + changes, nil
+}
@@ -0,0 +1,432 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package librarian
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/google/go-cmp/cmp"
+	"github.com/googleapis/librarian/internal/cli"
+	"github.com/googleapis/librarian/internal/config"
+	"github.com/googleapis/librarian/internal/gitrepo"
+	"gopkg.in/yaml.v3"
+)
+
+func TestNewUpdateImageRunner(t *testing.T) {
+	t.Parallel()
+	for _, test := range []struct {
+		name       string
+		cfg        *config.Config
+		wantErrMsg string
+	}{
+		{
+			name: "valid config",
+			cfg: &config.Config{
+				APISource:         newTestGitRepo(t).GetDir(),
+				Repo:              newTestGitRepo(t).GetDir(),
+				WorkRoot:          t.TempDir(),
+				Image:             "gcr.io/test/test-image:v2",
+				MaxFailurePercent: 10,
+				CommandName:       updateImageCmdName,
+			},
+		},
+		{
+			name: "missing image",
+			cfg: &config.Config{
+				APISource:   newTestGitRepo(t).GetDir(),
+				Repo:        newTestGitRepo(t).GetDir(),
+				WorkRoot:    t.TempDir(),
+				CommandName: updateImageCmdName,
+			},
+			wantErrMsg: "requires an image",
+		},
+		{
+			name: "invalid api source",
+			cfg: &config.Config{
+				APISource:   t.TempDir(), // Not a git repo
+				Repo:        newTestGitRepo(t).GetDir(),
+				WorkRoot:    t.TempDir(),
+				Image:       "gcr.io/test/test-image:v2",
+				CommandName: updateImageCmdName,
+			},
+			wantErrMsg: "repository does not exist",
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			r, err := newUpdateImageRunner(test.cfg)
+			if test.wantErrMsg != "" {
+				if err == nil {
+					t.Fatalf("newUpdateImageRunner() should return error")
+				}
+				if !strings.Contains(err.Error(), test.wantErrMsg) {
+					t.Errorf("want error message %s, got %s", test.wantErrMsg, err.Error())
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if r.generator.sourceRepo == nil {
+				t.Errorf("newUpdateImageRunner() sourceRepo is nil")
+			}
+			if r.generator.image != test.cfg.Image {
+				t.Errorf("newUpdateImageRunner() image = %q, want %q", r.generator.image, test.cfg.Image)
+			}
+			if r.maxFailurePercent != test.cfg.MaxFailurePercent {
+				t.Errorf("newUpdateImageRunner() maxFailurePercent = %d, want %d", r.maxFailurePercent, test.cfg.MaxFailurePercent)
+			}
+		})
+	}
+}
+
+func TestUpdateImageRun(t *testing.T) {
+	t.Parallel()
+	const (
+		oldImage = "gcr.io/test/image:v1"
+		newImage = "gcr.io/test/image:v2"
+	)
+	newState := func() *config.LibrarianState {
+		return &config.LibrarianState{
+			Image: oldImage,
+			Libraries: []*config.LibraryState{
+				{
+					ID:          "library-a",
+					APIs:        []*config.API{{Path: "a/api"}},
+					SourceRoots: []string{"a"},
+				},
+				{
+					ID:          "library-b",
+					APIs:        []*config.API{{Path: "b/api"}},
+					SourceRoots: []string{"b"},
+				},
+			},
+		}
+	}
+	for _, test := range []struct {
+		name              string
+		image             string
+		maxFailurePercent int
+		librarianConfig   *config.LibrarianConfig
+		container         *mockContainerClient
+		wantGenerateCalls int
+		wantImage         string
+		wantCleaned       bool
+		wantErrMsg        string
+	}{
+		{
+			name:              "all libraries regenerated",
+			image:             newImage,
+			container:         &mockContainerClient{wantLibraryGen: true},
+			wantGenerateCalls: 2,
+			wantImage:         newImage,
+		},
+		{
+			name:              "image unchanged",
+			image:             oldImage,
+			container:         &mockContainerClient{},
+			wantGenerateCalls: 0,
+			wantImage:         oldImage,
+		},
+		{
+			name:  "blocked library skipped",
+			image: newImage,
+			librarianConfig: &config.LibrarianConfig{
+				Libraries: []*config.LibraryConfig{
+					{LibraryID: "library-b", GenerateBlocked: true},
+				},
+			},
+			container:         &mockContainerClient{wantLibraryGen: true},
+			wantGenerateCalls: 1,
+			wantImage:         newImage,
+		},
+		{
+			name:              "failures within limit",
+			image:             newImage,
+			maxFailurePercent: 50,
+			container: &mockContainerClient{
+				wantLibraryGen:    true,
+				failGenerateForID: "library-a",
+				generateErrForID:  errors.New("generate error"),
+			},
+			wantGenerateCalls: 2,
+			wantImage:         newImage,
+		},
+		{
+			name:              "too many failures rolls back image",
+			image:             newImage,
+			maxFailurePercent: 10,
+			container: &mockContainerClient{
+				wantLibraryGen:    true,
+				failGenerateForID: "library-a",
+				generateErrForID:  errors.New("generate error"),
+			},
+			wantGenerateCalls: 2,
+			wantImage:         oldImage,
+			wantCleaned:       true,
+			wantErrMsg:        "image rolled back to " + oldImage,
+		},
+		{
+			name:       "describe error",
+			image:      newImage,
+			container:  &mockContainerClient{describeErr: errors.New("incompatible image")},
+			wantImage:  newImage,
+			wantErrMsg: "incompatible image",
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			state := newState()
+			repo := newTestGitRepoWithState(t, state, true)
+			untrackedFile := filepath.Join(repo.GetDir(), "a", "untracked.txt")
+			if err := os.MkdirAll(filepath.Dir(untrackedFile), 0755); err != nil {
+				t.Fatal(err)
+			}
+			if err := os.WriteFile(untrackedFile, []byte("generated"), 0644); err != nil {
+				t.Fatal(err)
+			}
+			r := &updateImageRunner{
+				generator: &generateRunner{
+					image:           test.image,
+					repo:            repo,
+					sourceRepo:      newTestGitRepo(t),
+					state:           state,
+					librarianConfig: test.librarianConfig,
+					containerClient: test.container,
+					ghClient:        &mockGitHubClient{},
+					workRoot:        t.TempDir(),
+				},
+				maxFailurePercent: test.maxFailurePercent,
+			}
+
+			err := r.run(context.Background())
+			if diff := cmp.Diff(test.wantGenerateCalls, test.container.generateCalls); diff != "" {
+				t.Errorf("run() generateCalls mismatch (-want +got):%s", diff)
+			}
+			if _, statErr := os.Stat(untrackedFile); test.wantCleaned && !os.IsNotExist(statErr) {
+				t.Errorf("untracked file %s should be removed on rollback, got err: %v", untrackedFile, statErr)
+			}
+			if test.wantErrMsg != "" {
+				if err == nil {
+					t.Fatalf("run() should return error")
+				}
+				if !strings.Contains(err.Error(), test.wantErrMsg) {
+					t.Errorf("want error message %s, got %s", test.wantErrMsg, err.Error())
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			data, err := os.ReadFile(filepath.Join(repo.GetDir(), config.LibrarianDir, config.LibrarianStateFile))
+			if err != nil {
+				t.Fatal(err)
+			}
+			saved := &config.LibrarianState{}
+			if err := yaml.Unmarshal(data, saved); err != nil {
+				t.Fatal(err)
+			}
+			if saved.Image != test.wantImage {
+				t.Errorf("state.yaml image = %q, want %q", saved.Image, test.wantImage)
+			}
+		})
+	}
+}
+
+func TestExceedsFailurePercent(t *testing.T) {
+	t.Parallel()
+	for _, test := range []struct {
+		failures   int
+		attempts   int
+		maxPercent int
+		want       bool
+	}{
+		{failures: 0, attempts: 0, maxPercent: 0, want: false},
+		{failures: 0, attempts: 10, maxPercent: 0, want: false},
+		{failures: 1, attempts: 10, maxPercent: 0, want: true},
+		{failures: 1, attempts: 10, maxPercent: 10, want: false},
+		{failures: 2, attempts: 10, maxPercent: 10, want: true},
+		{failures: 10, attempts: 10, maxPercent: 100, want: false},
+	} {
+		t.Run(fmt.Sprintf("%d of %d max %d", test.failures, test.attempts, test.maxPercent), func(t *testing.T) {
+			if got := exceedsFailurePercent(test.failures, test.attempts, test.maxPercent); got != test.want {
+				t.Errorf("exceedsFailurePercent() = %v, want %v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestFormatUpdateImagePRBody(t *testing.T) {
+	t.Parallel()
+	librarianVersion := cli.Version()
+	fixHash := plumbing.NewHash("fedcba0987654321")
+	state := &config.LibrarianState{
+		Image:     "gcr.io/test/image:v2",
+		Libraries: []*config.LibraryState{{ID: "library-a"}},
+	}
+	sourceRepo := &MockRepository{
+		GetCommitByHash: map[string]*gitrepo.Commit{
+			"1234567890": {Hash: plumbing.NewHash("1234567890"), When: time.UnixMilli(200)},
+		},
+		GetCommitsForPathsSinceLastGenByCommit: map[string][]*gitrepo.Commit{
+			"1234567890": {{Message: "fix: a bug fix\n\nPiperOrigin-RevId: 573342", Hash: fixHash, When: time.Now()}},
+		},
+		ChangedFilesInCommitValueByHash: map[string][]string{fixHash.String(): {"path/to/file"}},
+	}
+	for _, test := range []struct {
+		name       string
+		update     *imageUpdate
+		sources    []*generationSource
+		state      *config.LibrarianState
+		want       string
+		wantErrMsg string
+	}{
+		{
+			name: "all succeeded",
+			update: &imageUpdate{
+				OldImage: "gcr.io/test/image:v1",
+				NewImage: "gcr.io/test/image:v2",
+				Results: []*libraryGenerationResult{
+					{LibraryID: "library-a", Status: generationSucceeded},
+					{LibraryID: "library-b", Status: generationBlocked},
+				},
+			},
+			want: fmt.Sprintf(`This pull request updates the language image from
+`+"`gcr.io/test/image:v1`"+` to `+"`gcr.io/test/image:v2`"+` and regenerates all libraries.
+
+Librarian Version: %s
+Language Image: gcr.io/test/image:v2
+
+| Library | Result |
+| :------ | :----- |
+| library-a | succeeded |
+| library-b | blocked |`, librarianVersion),
+		},
+		{
+			name: "with failures",
+			update: &imageUpdate{
+				OldImage: "gcr.io/test/image:v1",
+				NewImage: "gcr.io/test/image:v2",
+				Results: []*libraryGenerationResult{
+					{LibraryID: "library-a", Status: generationFailed, Error: "generate error"},
+					{LibraryID: "library-b", Status: generationSucceeded},
+				},
+			},
+			want: fmt.Sprintf(`This pull request updates the language image from
+`+"`gcr.io/test/image:v1`"+` to `+"`gcr.io/test/image:v2`"+` and regenerates all libraries.
+
+Librarian Version: %s
+Language Image: gcr.io/test/image:v2
+
+| Library | Result |
+| :------ | :----- |
+| library-a | failed |
+| library-b | succeeded |
+
+## Generation failed for
+- library-a: generate error`, librarianVersion),
+		},
+		{
+			// The API changes picked up by the regeneration are described as
+			// in a generation pull request.
+			name: "with API changes",
+			update: &imageUpdate{
+				OldImage: "gcr.io/test/image:v1",
+				NewImage: "gcr.io/test/image:v2",
+				Results: []*libraryGenerationResult{
+					{LibraryID: "library-a", Status: generationSucceeded},
+				},
+			},
+			sources: []*generationSource{
+				{repoName: defaultGitHubSource, onGitHub: true, repo: sourceRepo, idToCommits: map[string]string{"library-a": "1234567890"}},
+			},
+			state: state,
+			want: fmt.Sprintf(`This pull request updates the language image from
+`+"`gcr.io/test/image:v1`"+` to `+"`gcr.io/test/image:v2`"+` and regenerates all libraries.
+
+Librarian Version: %s
+Language Image: gcr.io/test/image:v2
+
+| Library | Result |
+| :------ | :----- |
+| library-a | succeeded |
+
+This pull request is generated with proto changes between
+[googleapis/googleapis@1234567](https://github.com/googleapis/googleapis/commit/1234567890000000000000000000000000000000)
+(exclusive) and
+[googleapis/googleapis@fedcba0](https://github.com/googleapis/googleapis/commit/fedcba0987654321000000000000000000000000)
+(inclusive).
+
+BEGIN_COMMIT_OVERRIDE
+
+BEGIN_NESTED_COMMIT
+fix: [library-a] a bug fix
+
+
+PiperOrigin-RevId: 573342
+
+Source-link: [googleapis/googleapis@fedcba0](https://github.com/googleapis/googleapis/commit/fedcba0987654321000000000000000000000000)
+END_NESTED_COMMIT
+
+END_COMMIT_OVERRIDE`, librarianVersion),
+		},
+		{
+			name:    "no API changes",
+			update:  &imageUpdate{OldImage: "gcr.io/test/image:v1", NewImage: "gcr.io/test/image:v2"},
+			sources: []*generationSource{{repoName: defaultGitHubSource, onGitHub: true, repo: sourceRepo}},
+			state:   state,
+			want: fmt.Sprintf(`This pull request updates the language image from
+`+"`gcr.io/test/image:v1`"+` to `+"`gcr.io/test/image:v2`"+` and regenerates all libraries.
+
+Librarian Version: %s
+Language Image: gcr.io/test/image:v2
+
+| Library | Result |
+| :------ | :----- |`, librarianVersion),
+		},
+		{
+			name:       "no update",
+			wantErrMsg: "no image update",
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			got, err := formatUpdateImagePRBody(test.update, test.sources, test.state)
+			if test.wantErrMsg != "" {
+				if err == nil {
+					t.Fatalf("formatUpdateImagePRBody() should return error")
+				}
+				if !strings.Contains(err.Error(), test.wantErrMsg) {
+					t.Errorf("want error message %s, got %s", test.wantErrMsg, err.Error())
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(test.want, got); diff != "" {
+				t.Errorf("formatUpdateImagePRBody() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
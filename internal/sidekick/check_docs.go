@@ -0,0 +1,106 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sidekick
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/googleapis/librarian/internal/sidekick/internal/api"
+	"github.com/googleapis/librarian/internal/sidekick/internal/config"
+)
+
+func init() {
+	newCommand(
+		"sidekick check-docs",
+		"Checks the cross-reference links and Markdown in the documentation of a client library.",
+		`
+Checks the documentation of a single client library, using the configuration parameters saved in the .sidekick.toml file.
+
+The command resolves the cross-reference links, such as [Secret][google.cloud.secretmanager.v1.Secret],
+against the model and the external packages configured in the codec options. It reports broken links, links
+to elements removed by the skipped-ids or included-ids source options, and malformed Markdown.
+
+The issues are printed to stdout as a JSON array. The command fails if any issues are found.
+`,
+		cmdSidekick,
+		checkDocs,
+	)
+}
+
+// checkDocs checks the documentation for the library in `cmdLine.Output`.
+func checkDocs(rootConfig *config.Config, cmdLine *CommandLine) error {
+	override, err := overrideSources(rootConfig)
+	if err != nil {
+		return err
+	}
+	return checkDocsDir(override, cmdLine.Output, os.Stdout)
+}
+
+func checkDocsDir(rootConfig *config.Config, output string, w io.Writer) error {
+	model, config, err := loadDir(rootConfig, output)
+	if err != nil {
+		return err
+	}
+	if model == nil {
+		return fmt.Errorf("the library in %s does not have a specification to check", output)
+	}
+	issues := api.CheckDocumentation(model, externalPackages(config.Codec))
+	if issues == nil {
+		issues = []api.DocumentationIssue{}
+	}
+	contents, err := json.MarshalIndent(issues, "", "  ")
+	if err != nil {
+		return err
+	}
+	if _, err := fmt.Fprintln(w, string(contents)); err != nil {
+		return err
+	}
+	if len(issues) != 0 {
+		return fmt.Errorf("found %d documentation issues in %s", len(issues), output)
+	}
+	return nil
+}
+
+// externalPackages returns the Protobuf packages mapped to other libraries in
+// the codec options. Links to elements in these packages cannot be verified.
+//
+// Rust maps packages with `package:<name>` options containing
+// `source=<package>`, Dart and Go use `proto:<package>` options.
+func externalPackages(codec map[string]string) []string {
+	packages := map[string]bool{}
+	for key, value := range codec {
+		switch {
+		case strings.HasPrefix(key, "proto:"):
+			packages[strings.TrimPrefix(key, "proto:")] = true
+		case strings.HasPrefix(key, "package:"):
+			for _, element := range strings.Split(value, ",") {
+				if source, ok := strings.CutPrefix(element, "source="); ok {
+					packages[source] = true
+				}
+			}
+		}
+	}
+	var result []string
+	for pkg := range packages {
+		result = append(result, pkg)
+	}
+	sort.Strings(result)
+	return result
+}
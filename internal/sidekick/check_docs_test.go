@@ -0,0 +1,99 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sidekick
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/googleapis/librarian/internal/sidekick/internal/api"
+	"github.com/googleapis/librarian/internal/sidekick/internal/config"
+)
+
+func TestCheckDocsDir(t *testing.T) {
+	for _, test := range []struct {
+		name      string
+		overrides string
+		want      []api.DocumentationIssue
+	}{
+		{
+			name: "no issues",
+			want: []api.DocumentationIssue{},
+		},
+		{
+			name: "broken link",
+			overrides: `
+[[documentation-overrides]]
+id      = ".google.cloud.secretmanager.v1.Secret"
+match   = "A Secret is a logical secret"
+replace = "A [Secret][google.cloud.secretmanager.v1.Missing] is a logical secret"
+`,
+			want: []api.DocumentationIssue{
+				{
+					ID:        ".google.cloud.secretmanager.v1.Secret",
+					Kind:      api.IssueBrokenLink,
+					Reference: "google.cloud.secretmanager.v1.Missing",
+					Message:   "cannot resolve the link target google.cloud.secretmanager.v1.Missing",
+				},
+			},
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			outDir := t.TempDir()
+			contents := fmt.Sprintf(`[general]
+specification-format = "openapi"
+specification-source = %q
+service-config = %q
+%s`, specificationSource, path.Join(testdataDir, secretManagerServiceConfig), test.overrides)
+			if err := os.WriteFile(path.Join(outDir, ".sidekick.toml"), []byte(contents), 0644); err != nil {
+				t.Fatal(err)
+			}
+			var output bytes.Buffer
+			err := checkDocsDir(&config.Config{}, outDir, &output)
+			if len(test.want) == 0 && err != nil {
+				t.Fatal(err)
+			}
+			if len(test.want) != 0 && err == nil {
+				t.Errorf("expected an error with documentation issues")
+			}
+			var got []api.DocumentationIssue
+			if err := json.Unmarshal(output.Bytes(), &got); err != nil {
+				t.Fatalf("cannot parse output %q: %v", output.String(), err)
+			}
+			if diff := cmp.Diff(test.want, got); diff != "" {
+				t.Errorf("mismatch in checkDocsDir output (-want, +got)\n:%s", diff)
+			}
+		})
+	}
+}
+
+func TestExternalPackages(t *testing.T) {
+	codec := map[string]string{
+		"copyright-year":              "2025",
+		"proto:google.cloud.location": "package:google_cloud_location/location.dart",
+		"package:wkt":                 "package=types,path=types,source=google.protobuf",
+		"package:gax":                 "package=gax,path=gax,feature=unstable-sdk-client",
+		"package:iam":                 "package=iam,source=google.iam.v1,source=google.iam.v2",
+	}
+	want := []string{"google.cloud.location", "google.iam.v1", "google.iam.v2", "google.protobuf"}
+	if diff := cmp.Diff(want, externalPackages(codec)); diff != "" {
+		t.Errorf("mismatch in externalPackages (-want, +got)\n:%s", diff)
+	}
+}
@@ -21,6 +21,7 @@ type CommandLine struct {
 	SpecificationFormat string
 	SpecificationSource string
 	ServiceConfig       string
+	GrpcServiceConfig   string
 	Source              map[string]string
 	Output              string
 	Language            string
@@ -33,6 +34,7 @@ var (
 	format          string
 	source          string
 	serviceConfig   string
+	grpcConfig      string
 	sourceOpts      = map[string]string{}
 	output          string
 	flagLanguage    string
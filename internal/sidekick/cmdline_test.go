@@ -22,7 +22,7 @@ import (
 )
 
 func resetArgs() {
-	flagProjectRoot, format, source, serviceConfig, output, flagLanguage = "", "", "", "", "", ""
+	flagProjectRoot, format, source, serviceConfig, grpcConfig, output, flagLanguage = "", "", "", "", "", "", ""
 	sourceOpts, codecOpts = map[string]string{}, map[string]string{}
 	dryrun = false
 }
@@ -35,6 +35,7 @@ func TestParseArgs(t *testing.T) {
 		"-specification-format", "openapi",
 		"-specification-source", specificationSource,
 		"-service-config", secretManagerServiceConfig,
+		"-grpc-service-config", secretManagerGrpcServiceConfig,
 		"-source-option", fmt.Sprintf("googleapis-root=%s", googleapisRoot),
 		"-language", "not-rust",
 		"-output", outputDir,
@@ -58,6 +59,7 @@ func TestParseArgs(t *testing.T) {
 		SpecificationFormat: "openapi",
 		SpecificationSource: specificationSource,
 		ServiceConfig:       secretManagerServiceConfig,
+		GrpcServiceConfig:   secretManagerGrpcServiceConfig,
 		Source: map[string]string{
 			"googleapis-root": googleapisRoot,
 		},
@@ -142,6 +142,7 @@ func (c *command) parseCmdLine(args []string) (*CommandLine, error) {
 		SpecificationFormat: format,
 		SpecificationSource: source,
 		ServiceConfig:       serviceConfig,
+		GrpcServiceConfig:   grpcConfig,
 		Source:              sourceOpts,
 		Language:            flagLanguage,
 		Output:              output,
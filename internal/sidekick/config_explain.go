@@ -0,0 +1,150 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sidekick
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"slices"
+	"strings"
+
+	"github.com/googleapis/librarian/internal/sidekick/internal/config"
+	"github.com/googleapis/librarian/internal/sidekick/internal/options"
+)
+
+var cmdConfig = newCommand(
+	"sidekick config",
+	"Inspects the sidekick configuration.",
+	``,
+	cmdSidekick,
+	nil)
+
+func init() {
+	newCommand(
+		"sidekick config explain",
+		"Prints the codec options supported by a language, and their effective values.",
+		`
+Prints the codec options supported by the language in -language, with their type, default value, and
+description. If -language is not set, the command uses the language in the configuration.
+
+If -output contains a .sidekick.toml file, the command also prints the effective value of each option after
+merging the top-level .sidekick.toml file, the command line, and the local .sidekick.toml file, and where the
+value comes from. Options which are not supported by the language are listed at the end.
+`,
+		cmdConfig,
+		configExplain,
+	)
+}
+
+// codecOptionValue is the effective value of a codec option, and where it
+// is configured.
+type codecOptionValue struct {
+	Key    string
+	Value  string
+	Origin string
+}
+
+// configExplain prints the codec options for the language and library
+// selected in the command line.
+func configExplain(rootConfig *config.Config, cmdLine *CommandLine) error {
+	values := map[string]codecOptionValue{}
+	for key, value := range rootConfig.Codec {
+		origin := "top-level .sidekick.toml"
+		if _, ok := cmdLine.Codec[key]; ok {
+			origin = "command line"
+		}
+		values[key] = codecOptionValue{Key: key, Value: value, Origin: origin}
+	}
+	language := rootConfig.General.Language
+	if cmdLine.Output != "" {
+		filename := path.Join(cmdLine.Output, ".sidekick.toml")
+		local, err := config.MergeConfigAndFile(&config.Config{}, filename)
+		switch {
+		case errors.Is(err, os.ErrNotExist):
+		case err != nil:
+			return err
+		default:
+			for key, value := range local.Codec {
+				values[key] = codecOptionValue{Key: key, Value: value, Origin: filename}
+			}
+			if local.General.Language != "" {
+				language = local.General.Language
+			}
+		}
+	}
+	if cmdLine.Language != "" {
+		language = cmdLine.Language
+	}
+	if language == "" {
+		return fmt.Errorf("no language selected, use -language or run the command in a library with a .sidekick.toml file")
+	}
+	schema, err := codecOptions(language)
+	if err != nil {
+		return err
+	}
+	return explainCodecOptions(os.Stdout, language, schema, values)
+}
+
+func explainCodecOptions(w io.Writer, language string, schema []options.Option, values map[string]codecOptionValue) error {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+
+	var lines []string
+	lines = append(lines, fmt.Sprintf("Codec options for language %q:", language))
+	sorted := slices.Clone(schema)
+	slices.SortStableFunc(sorted, func(a, b options.Option) int { return strings.Compare(a.Name, b.Name) })
+	for _, option := range sorted {
+		traits := []string{string(option.Type)}
+		if option.Default != "" {
+			traits = append(traits, "default: "+option.Default)
+		}
+		if option.Deprecated != "" {
+			traits = append(traits, "deprecated: "+option.Deprecated)
+		}
+		lines = append(lines, "", fmt.Sprintf("%s (%s)", option.Name, strings.Join(traits, ", ")))
+		lines = append(lines, "    "+option.Description)
+		for _, key := range keys {
+			if !option.Matches(key) {
+				continue
+			}
+			v := values[key]
+			lines = append(lines, fmt.Sprintf("    %s = %q (%s)", v.Key, v.Value, v.Origin))
+		}
+	}
+	var unknown []string
+	for _, key := range keys {
+		if _, ok := options.Find(schema, key); ok {
+			continue
+		}
+		v := values[key]
+		line := fmt.Sprintf("    %s = %q (%s)", v.Key, v.Value, v.Origin)
+		if suggestion := options.Suggest(schema, key); suggestion != "" {
+			line += fmt.Sprintf(", did you mean %q?", suggestion)
+		}
+		unknown = append(unknown, line)
+	}
+	if len(unknown) != 0 {
+		lines = append(lines, "", fmt.Sprintf("Options not supported by %q:", language))
+		lines = append(lines, unknown...)
+	}
+	_, err := fmt.Fprintln(w, strings.Join(lines, "\n"))
+	return err
+}
@@ -0,0 +1,127 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sidekick
+
+import (
+	"bytes"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/googleapis/librarian/internal/sidekick/internal/config"
+	"github.com/googleapis/librarian/internal/sidekick/internal/options"
+)
+
+func TestCodecOptions(t *testing.T) {
+	for _, language := range []string{"rust", "rust_storage", "rust+prost", "dart", "go", "sample", "gcloud", "mock-server"} {
+		schema, err := codecOptions(language)
+		if err != nil {
+			t.Fatal(err)
+		}
+		seen := map[string]bool{}
+		for _, option := range schema {
+			if seen[option.Name] {
+				t.Errorf("duplicate option %q for %s", option.Name, language)
+			}
+			seen[option.Name] = true
+			if option.Description == "" || option.Type == "" {
+				t.Errorf("incomplete description for option %q in %s: %v", option.Name, language, option)
+			}
+		}
+		for _, common := range []string{"copyright-year", "api-surface"} {
+			if _, ok := options.Find(schema, common); !ok {
+				t.Errorf("missing common option %q for %s", common, language)
+			}
+		}
+	}
+	if _, err := codecOptions("--invalid--"); err == nil {
+		t.Errorf("expected an error for an unknown language")
+	}
+}
+
+func TestValidateCodecOptions(t *testing.T) {
+	t.Chdir(t.TempDir())
+	cfg := &config.Config{
+		General: config.GeneralConfig{Language: "go"},
+		Codec: map[string]string{
+			"copyright-year": "2025",
+			"module-path":    "example.com/test",
+			"skip-format":    "true",
+		},
+	}
+	if err := validateCodecOptions(cfg); err != nil {
+		t.Error(err)
+	}
+	cfg.Codec["package:wkt"] = "source=google.protobuf"
+	if err := validateCodecOptions(cfg); err == nil {
+		t.Errorf("expected an error for an unknown option which is not inherited")
+	}
+	root := "[codec]\n'package:wkt' = 'source=google.protobuf'\n"
+	if err := os.WriteFile(".sidekick.toml", []byte(root), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := validateCodecOptions(cfg); err != nil {
+		t.Errorf("options inherited from the top-level configuration should be ignored, got=%v", err)
+	}
+	// Options in the command line are merged into the same configuration,
+	// but they are not inherited from the top-level file.
+	cfg.Codec["package:other"] = "source=google.other"
+	if err := validateCodecOptions(cfg); err == nil {
+		t.Errorf("expected an error for an unknown option set in the command line")
+	}
+	delete(cfg.Codec, "package:other")
+	cfg.Codec["modul-path"] = "example.com/test"
+	err := validateCodecOptions(cfg)
+	if err == nil || !strings.Contains(err.Error(), `did you mean "module-path"?`) {
+		t.Errorf("expected an error with a suggestion, got=%v", err)
+	}
+}
+
+func TestExplainCodecOptions(t *testing.T) {
+	schema := []options.Option{
+		{Name: "skip-format", Type: options.Bool, Default: "false", Description: "If true, skip formatting."},
+		{Name: "proto:<package>", Type: options.String, Description: "Maps protobuf packages."},
+		{Name: "module-path", Type: options.String, Description: "The module path.", Deprecated: "use `go.mod`"},
+	}
+	values := map[string]codecOptionValue{
+		"proto:google.type": {Key: "proto:google.type", Value: "example.com/type", Origin: "top-level .sidekick.toml"},
+		"skip-format":       {Key: "skip-format", Value: "true", Origin: "src/test/.sidekick.toml"},
+		"modle-path":        {Key: "modle-path", Value: "example.com/test", Origin: "command line"},
+	}
+	var buffer bytes.Buffer
+	if err := explainCodecOptions(&buffer, "go", schema, values); err != nil {
+		t.Fatal(err)
+	}
+	want := `Codec options for language "go":
+
+module-path (string, deprecated: use ` + "`go.mod`" + `)
+    The module path.
+
+proto:<package> (string)
+    Maps protobuf packages.
+    proto:google.type = "example.com/type" (top-level .sidekick.toml)
+
+skip-format (bool, default: false)
+    If true, skip formatting.
+    skip-format = "true" (src/test/.sidekick.toml)
+
+Options not supported by "go":
+    modle-path = "example.com/test" (command line), did you mean "module-path"?
+`
+	if diff := cmp.Diff(want, buffer.String()); diff != "" {
+		t.Errorf("mismatch (-want, +got):\n%s", diff)
+	}
+}
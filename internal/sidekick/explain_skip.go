@@ -0,0 +1,96 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sidekick
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/googleapis/librarian/internal/sidekick/internal/api"
+	"github.com/googleapis/librarian/internal/sidekick/internal/config"
+)
+
+func init() {
+	newCommand(
+		"sidekick explain-skip",
+		"Reports which elements of a client library are generated, and which are removed by included-ids or skipped-ids.",
+		`
+Explains the effect of the included-ids and skipped-ids source options for a single client library, using the
+configuration parameters saved in the .sidekick.toml file.
+
+The report lists the services, methods, messages, and enums in the package of the library that are included in
+the generated code, the elements that were removed with the reason for their removal, and any selectors that
+do not match any element.
+`,
+		cmdSidekick,
+		explainSkip,
+	)
+}
+
+// explainSkip prints the skip report for the library in `cmdLine.Output`.
+func explainSkip(rootConfig *config.Config, cmdLine *CommandLine) error {
+	override, err := overrideSources(rootConfig)
+	if err != nil {
+		return err
+	}
+	return explainSkipDir(override, cmdLine.Output, os.Stdout)
+}
+
+func explainSkipDir(rootConfig *config.Config, output string, w io.Writer) error {
+	model, config, err := loadDir(rootConfig, output)
+	if err != nil {
+		return err
+	}
+	if model == nil {
+		return fmt.Errorf("the library in %s does not have a specification to explain", output)
+	}
+	report, err := api.ExplainSkip(model, config.Source)
+	if err != nil {
+		return err
+	}
+	return writeSkipReport(w, report)
+}
+
+func writeSkipReport(w io.Writer, report *api.SkipReport) error {
+	if _, err := fmt.Fprintf(w, "Kept (%d):\n", len(report.Kept)); err != nil {
+		return err
+	}
+	for _, id := range report.Kept {
+		if _, err := fmt.Fprintf(w, "  %s\n", id); err != nil {
+			return err
+		}
+	}
+	if _, err := fmt.Fprintf(w, "Removed (%d):\n", len(report.Removed)); err != nil {
+		return err
+	}
+	for _, removed := range report.Removed {
+		if _, err := fmt.Fprintf(w, "  %s: %s\n", removed.ID, removed.Reason); err != nil {
+			return err
+		}
+	}
+	if len(report.UnusedSelectors) == 0 {
+		return nil
+	}
+	if _, err := fmt.Fprintf(w, "Unused selectors (%d):\n", len(report.UnusedSelectors)); err != nil {
+		return err
+	}
+	for _, selector := range report.UnusedSelectors {
+		if _, err := fmt.Fprintf(w, "  %s\n", selector); err != nil {
+			return err
+		}
+	}
+	return nil
+}
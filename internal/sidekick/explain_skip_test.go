@@ -0,0 +1,79 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sidekick
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/googleapis/librarian/internal/sidekick/internal/api"
+	"github.com/googleapis/librarian/internal/sidekick/internal/config"
+)
+
+func TestExplainSkipDir(t *testing.T) {
+	outDir := t.TempDir()
+	contents := fmt.Sprintf(`[general]
+specification-format = "openapi"
+specification-source = %q
+service-config = %q
+
+[source]
+skipped-ids = ".google.cloud.secretmanager.v1.*Version*,.google.cloud.secretmanager.v1.*Missing*"
+`, specificationSource, path.Join(testdataDir, secretManagerServiceConfig))
+	if err := os.WriteFile(path.Join(outDir, ".sidekick.toml"), []byte(contents), 0644); err != nil {
+		t.Fatal(err)
+	}
+	var output bytes.Buffer
+	if err := explainSkipDir(&config.Config{}, outDir, &output); err != nil {
+		t.Fatal(err)
+	}
+	got := output.String()
+	for _, want := range []string{
+		"Kept (",
+		"  .google.cloud.secretmanager.v1.Secret\n",
+		"  .google.cloud.secretmanager.v1.SecretVersion: matches the skipped-ids selector \".google.cloud.secretmanager.v1.*Version*\"\n",
+		"Unused selectors (1):\n  .google.cloud.secretmanager.v1.*Missing*\n",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("missing %q in explainSkipDir output:\n%s", want, got)
+		}
+	}
+}
+
+func TestWriteSkipReport(t *testing.T) {
+	report := &api.SkipReport{
+		Kept: []string{".test.Secret"},
+		Removed: []api.RemovedElement{
+			{ID: ".test.Internal", Reason: `matches the skipped-ids selector "*Internal"`},
+		},
+	}
+	var output bytes.Buffer
+	if err := writeSkipReport(&output, report); err != nil {
+		t.Fatal(err)
+	}
+	want := `Kept (1):
+  .test.Secret
+Removed (1):
+  .test.Internal: matches the skipped-ids selector "*Internal"
+`
+	if diff := cmp.Diff(want, output.String()); diff != "" {
+		t.Errorf("mismatch in writeSkipReport output (-want, +got)\n:%s", diff)
+	}
+}
@@ -45,6 +45,7 @@ func generate(rootConfig *config.Config, cmdLine *CommandLine) error {
 			SpecificationFormat: cmdLine.SpecificationFormat,
 			SpecificationSource: cmdLine.SpecificationSource,
 			ServiceConfig:       cmdLine.ServiceConfig,
+			GrpcServiceConfig:   cmdLine.GrpcServiceConfig,
 		},
 		Source: maps.Clone(cmdLine.Source),
 		Codec:  maps.Clone(cmdLine.Codec),
@@ -0,0 +1,335 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// The kinds of problems reported by CheckDocumentation.
+const (
+	// IssueBrokenLink is a cross-reference link to an element that does not
+	// exist in the model, or in any of the known external packages.
+	IssueBrokenLink = "broken-link"
+	// IssueSkippedTarget is a cross-reference link to an element that exists
+	// in the specification, but was removed from the model, for example using
+	// `skipped-ids`.
+	IssueSkippedTarget = "skipped-target"
+	// IssueMalformedMarkdown is documentation that cannot be rendered
+	// correctly as Markdown.
+	IssueMalformedMarkdown = "malformed-markdown"
+)
+
+// DocumentationIssue is a problem found in the documentation of an element.
+type DocumentationIssue struct {
+	// ID is the element with the problematic documentation.
+	ID string `json:"id"`
+	// Kind is one of IssueBrokenLink, IssueSkippedTarget, or
+	// IssueMalformedMarkdown.
+	Kind string `json:"kind"`
+	// Reference is the target of the cross-reference link, if any.
+	Reference string `json:"reference,omitempty"`
+	// Message describes the problem.
+	Message string `json:"message"`
+}
+
+var (
+	// Matches `[Title][google.package.v1.Thing]`, capturing the target.
+	docCrossReferenceLink = regexp.MustCompile(`\]\[([A-Za-z][A-Za-z0-9_]*(?:\.[A-Za-z][A-Za-z0-9_]*)*)\]`)
+	// Matches `[google.package.v1.Thing][]`, capturing the target.
+	docImpliedCrossReferenceLink = regexp.MustCompile(`\[([A-Za-z][A-Za-z0-9_]*(?:\.[A-Za-z][A-Za-z0-9_]*)*)\]\[\]`)
+	// Matches Markdown link reference definitions, e.g. `[label]: https://...`.
+	docLinkDefinition = regexp.MustCompile(`(?m)^\s{0,3}\[([^\]]+)\]:\s`)
+	// Matches inline code spans.
+	docCodeSpan = regexp.MustCompile("`[^`]*`")
+	// Matches an unterminated cross-reference link, e.g. `[Title][google.`
+	docUnterminatedReference = regexp.MustCompile(`\]\[[A-Za-z0-9_.]*(\s|$)`)
+)
+
+// CheckDocumentation verifies the documentation of all the elements in the
+// model, which must be cross-referenced.
+//
+// Cross-reference links are resolved against the model, using the same scopes
+// as the codecs. Links to elements in `externalPackages`, for example
+// `google.protobuf`, are assumed to be valid.
+//
+// The issues are sorted by element ID.
+func CheckDocumentation(model *API, externalPackages []string) []DocumentationIssue {
+	checker := &docChecker{
+		model:            model,
+		externalPackages: externalPackages,
+		generated:        generatedElements(model),
+		checked:          map[string]bool{},
+	}
+	for _, m := range model.Messages {
+		checker.checkMessage(m)
+	}
+	for _, e := range model.Enums {
+		checker.checkEnum(e)
+	}
+	for _, s := range model.Services {
+		checker.check(s.ID, s.Documentation, s.Scopes())
+		for _, m := range s.Methods {
+			checker.check(m.ID, m.Documentation, s.Scopes())
+		}
+	}
+	sort.SliceStable(checker.issues, func(i, j int) bool { return checker.issues[i].ID < checker.issues[j].ID })
+	return checker.issues
+}
+
+type docChecker struct {
+	model            *API
+	externalPackages []string
+	// The IDs of all the elements included in the model, after removing any
+	// skipped elements.
+	generated map[string]bool
+	// The IDs of the messages and enums already checked.
+	checked map[string]bool
+	issues  []DocumentationIssue
+}
+
+func (c *docChecker) checkMessage(m *Message) {
+	if c.checked[m.ID] {
+		return
+	}
+	c.checked[m.ID] = true
+	c.check(m.ID, m.Documentation, m.Scopes())
+	for _, f := range m.Fields {
+		c.check(m.ID+"."+f.Name, f.Documentation, m.Scopes())
+	}
+	for _, child := range m.Messages {
+		c.checkMessage(child)
+	}
+	for _, e := range m.Enums {
+		c.checkEnum(e)
+	}
+}
+
+func (c *docChecker) checkEnum(e *Enum) {
+	if c.checked[e.ID] {
+		return
+	}
+	c.checked[e.ID] = true
+	c.check(e.ID, e.Documentation, e.Scopes())
+	for _, v := range e.Values {
+		c.check(e.ID+"."+v.Name, v.Documentation, e.Scopes())
+	}
+}
+
+func (c *docChecker) check(id, documentation string, scopes []string) {
+	if documentation == "" {
+		return
+	}
+	text, problems := stripCode(documentation)
+	for _, problem := range problems {
+		c.issues = append(c.issues, DocumentationIssue{ID: id, Kind: IssueMalformedMarkdown, Message: problem})
+	}
+	for _, match := range docUnterminatedReference.FindAllString(text, -1) {
+		c.issues = append(c.issues, DocumentationIssue{
+			ID:      id,
+			Kind:    IssueMalformedMarkdown,
+			Message: fmt.Sprintf("unterminated cross-reference link %q", strings.TrimSpace(match)),
+		})
+	}
+
+	defined := map[string]bool{}
+	for _, match := range docLinkDefinition.FindAllStringSubmatch(text, -1) {
+		defined[match[1]] = true
+	}
+	seen := map[string]bool{}
+	var links []string
+	for _, re := range []*regexp.Regexp{docCrossReferenceLink, docImpliedCrossReferenceLink} {
+		for _, match := range re.FindAllStringSubmatch(text, -1) {
+			link := match[1]
+			if seen[link] || defined[link] {
+				continue
+			}
+			seen[link] = true
+			links = append(links, link)
+		}
+	}
+	sort.Strings(links)
+	for _, link := range links {
+		c.checkLink(id, link, scopes)
+	}
+}
+
+func (c *docChecker) checkLink(id, link string, scopes []string) {
+	var candidates []string
+	for _, s := range scopes {
+		candidates = append(candidates, fmt.Sprintf(".%s.%s", s, link))
+	}
+	candidates = append(candidates, "."+link)
+	for _, candidate := range candidates {
+		target, ok := c.resolve(candidate)
+		if !ok {
+			continue
+		}
+		if c.generated[target] || !c.isLocal(target) {
+			return
+		}
+		c.issues = append(c.issues, DocumentationIssue{
+			ID:        id,
+			Kind:      IssueSkippedTarget,
+			Reference: link,
+			Message:   fmt.Sprintf("the link target %s is not included in the generated code", target),
+		})
+		return
+	}
+	for _, pkg := range c.externalPackages {
+		if link == pkg || strings.HasPrefix(link, pkg+".") {
+			return
+		}
+	}
+	c.issues = append(c.issues, DocumentationIssue{
+		ID:        id,
+		Kind:      IssueBrokenLink,
+		Reference: link,
+		Message:   fmt.Sprintf("cannot resolve the link target %s", link),
+	})
+}
+
+// resolve finds the element referenced by `id`. It returns the ID of the
+// element that must be generated for the link to work, which is the parent
+// for fields and enum values.
+func (c *docChecker) resolve(id string) (string, bool) {
+	state := c.model.State
+	if _, ok := state.MessageByID[id]; ok {
+		return id, true
+	}
+	if _, ok := state.EnumByID[id]; ok {
+		return id, true
+	}
+	if _, ok := state.ServiceByID[id]; ok {
+		return id, true
+	}
+	if _, ok := state.MethodByID[id]; ok {
+		return id, true
+	}
+	idx := strings.LastIndex(id, ".")
+	if idx == -1 {
+		return "", false
+	}
+	parentID, name := id[:idx], id[idx+1:]
+	if m, ok := state.MessageByID[parentID]; ok {
+		for _, f := range m.Fields {
+			if f.Name == name {
+				return parentID, true
+			}
+		}
+		for _, o := range m.OneOfs {
+			if o.Name == name {
+				return parentID, true
+			}
+		}
+	}
+	if e, ok := state.EnumByID[parentID]; ok {
+		for _, v := range e.Values {
+			if v.Name == name {
+				return parentID, true
+			}
+		}
+	}
+	return "", false
+}
+
+// isLocal returns true if the element is defined in the package of the
+// model, as opposed to one of its dependencies.
+func (c *docChecker) isLocal(id string) bool {
+	return isInPackage(c.model, id)
+}
+
+// generatedElements returns the IDs of all the elements reachable from the
+// model.
+func generatedElements(model *API) map[string]bool {
+	generated := map[string]bool{}
+	var addMessage func(m *Message)
+	addMessage = func(m *Message) {
+		generated[m.ID] = true
+		for _, child := range m.Messages {
+			addMessage(child)
+		}
+		for _, e := range m.Enums {
+			generated[e.ID] = true
+		}
+	}
+	for _, m := range model.Messages {
+		addMessage(m)
+	}
+	for _, e := range model.Enums {
+		generated[e.ID] = true
+	}
+	for _, s := range model.Services {
+		generated[s.ID] = true
+		for _, m := range s.Methods {
+			generated[m.ID] = true
+		}
+	}
+	return generated
+}
+
+// stripCode removes the code blocks and code spans from the documentation, as
+// links are not expanded in code. It also reports unterminated code blocks and
+// code spans.
+func stripCode(documentation string) (string, []string) {
+	var problems []string
+	var lines []string
+	var paragraph []string
+	paragraphStart := 0
+	flush := func() {
+		if len(paragraph) == 0 {
+			return
+		}
+		// Code spans may span multiple lines, but not multiple paragraphs.
+		text := docCodeSpan.ReplaceAllString(strings.Join(paragraph, "\n"), "")
+		if strings.Contains(text, "`") {
+			problems = append(problems, fmt.Sprintf("unterminated code span in the paragraph starting in line %d", paragraphStart))
+		}
+		lines = append(lines, strings.Split(text, "\n")...)
+		paragraph = nil
+	}
+	inFence := false
+	fenceStart := 0
+	for i, line := range strings.Split(documentation, "\n") {
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~"):
+			flush()
+			if !inFence {
+				fenceStart = i + 1
+			}
+			inFence = !inFence
+			lines = append(lines, "")
+		case inFence:
+			lines = append(lines, "")
+		case trimmed == "":
+			flush()
+			lines = append(lines, "")
+		default:
+			if len(paragraph) == 0 {
+				paragraphStart = i + 1
+			}
+			paragraph = append(paragraph, line)
+		}
+	}
+	flush()
+	if inFence {
+		problems = append(problems, fmt.Sprintf("unterminated code block starting in line %d", fenceStart))
+	}
+	return strings.Join(lines, "\n"), problems
+}
@@ -0,0 +1,182 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func testDocCheckModel(documentation string) (*API, *Message) {
+	secret := &Message{
+		Name:          "Secret",
+		Package:       "test.v1",
+		ID:            ".test.v1.Secret",
+		Documentation: documentation,
+		Fields: []*Field{
+			{Name: "name", ID: ".test.v1.Secret.name"},
+		},
+	}
+	child := &Message{
+		Name:    "Child",
+		Package: "test.v1",
+		ID:      ".test.v1.Secret.Child",
+	}
+	skipped := &Message{
+		Name:    "Skipped",
+		Package: "test.v1",
+		ID:      ".test.v1.Skipped",
+	}
+	external := &Message{
+		Name:    "Timestamp",
+		Package: "google.protobuf",
+		ID:      ".google.protobuf.Timestamp",
+	}
+	state := &Enum{
+		Name:    "State",
+		Package: "test.v1",
+		ID:      ".test.v1.State",
+		Values:  []*EnumValue{{Name: "ENABLED"}},
+	}
+	method := &Method{Name: "GetSecret", ID: ".test.v1.Service.GetSecret"}
+	service := &Service{
+		Name:    "Service",
+		Package: "test.v1",
+		ID:      ".test.v1.Service",
+		Methods: []*Method{method},
+	}
+	method.Service = service
+	model := NewTestAPI([]*Message{secret, child, skipped, external}, []*Enum{state}, []*Service{service})
+	model.PackageName = "test.v1"
+	// Simulate `skipped-ids` and remove elements which are not generated.
+	model.Messages = []*Message{secret}
+	return model, secret
+}
+
+func TestCheckDocumentationLinks(t *testing.T) {
+	for _, test := range []struct {
+		name          string
+		documentation string
+		want          []DocumentationIssue
+	}{
+		{
+			name:          "valid links",
+			documentation: "See [Secret][test.v1.Secret], [name][test.v1.Secret.name], [Child][], [State.ENABLED][] and [GetSecret][test.v1.Service.GetSecret].",
+		},
+		{
+			name:          "external links",
+			documentation: "See [Timestamp][google.protobuf.Timestamp] and [Location][google.cloud.location.Location].",
+		},
+		{
+			name:          "markdown links",
+			documentation: "See [the guide][guide].\n\n[guide]: https://cloud.google.com/secret-manager",
+		},
+		{
+			name:          "links in code",
+			documentation: "Use `[Foo][test.v1.Foo]`.\n\n```\n[Bar][test.v1.Bar]\n```",
+		},
+		{
+			name:          "broken link",
+			documentation: "See [Missing][test.v1.Missing].",
+			want: []DocumentationIssue{
+				{
+					ID:        ".test.v1.Secret",
+					Kind:      IssueBrokenLink,
+					Reference: "test.v1.Missing",
+					Message:   "cannot resolve the link target test.v1.Missing",
+				},
+			},
+		},
+		{
+			name:          "skipped target",
+			documentation: "See [Skipped][test.v1.Skipped].",
+			want: []DocumentationIssue{
+				{
+					ID:        ".test.v1.Secret",
+					Kind:      IssueSkippedTarget,
+					Reference: "test.v1.Skipped",
+					Message:   "the link target .test.v1.Skipped is not included in the generated code",
+				},
+			},
+		},
+		{
+			name:          "unterminated code block",
+			documentation: "Example:\n\n```\ncode",
+			want: []DocumentationIssue{
+				{
+					ID:      ".test.v1.Secret",
+					Kind:    IssueMalformedMarkdown,
+					Message: "unterminated code block starting in line 3",
+				},
+			},
+		},
+		{
+			name:          "unterminated code span",
+			documentation: "A `code\nspan` is fine.\n\nBut `this is not.",
+			want: []DocumentationIssue{
+				{
+					ID:      ".test.v1.Secret",
+					Kind:    IssueMalformedMarkdown,
+					Message: "unterminated code span in the paragraph starting in line 4",
+				},
+			},
+		},
+		{
+			name:          "unterminated reference",
+			documentation: "See [Secret][test.v1.\nSecret].",
+			want: []DocumentationIssue{
+				{
+					ID:      ".test.v1.Secret",
+					Kind:    IssueMalformedMarkdown,
+					Message: `unterminated cross-reference link "][test.v1."`,
+				},
+			},
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			model, _ := testDocCheckModel(test.documentation)
+			got := CheckDocumentation(model, []string{"google.cloud.location"})
+			if diff := cmp.Diff(test.want, got); diff != "" {
+				t.Errorf("mismatch in CheckDocumentation (-want, +got)\n:%s", diff)
+			}
+		})
+	}
+}
+
+func TestCheckDocumentationElements(t *testing.T) {
+	model, secret := testDocCheckModel("")
+	broken := "See [Missing][test.v1.Missing]."
+	secret.Fields[0].Documentation = broken
+	model.State.EnumByID[".test.v1.State"].Values[0].Documentation = broken
+	model.Services[0].Documentation = broken
+	model.Services[0].Methods[0].Documentation = broken
+	// Elements that are not generated are not checked.
+	model.State.MessageByID[".test.v1.Skipped"].Documentation = broken
+
+	var got []string
+	for _, issue := range CheckDocumentation(model, nil) {
+		got = append(got, issue.ID)
+	}
+	want := []string{
+		".test.v1.Secret.name",
+		".test.v1.Service",
+		".test.v1.Service.GetSecret",
+		".test.v1.State.ENABLED",
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("mismatch in CheckDocumentation (-want, +got)\n:%s", diff)
+	}
+}
@@ -17,68 +17,103 @@ package api
 import (
 	"fmt"
 	"log/slog"
+	"regexp"
+	"sort"
 	"strings"
 
 	"github.com/googleapis/librarian/internal/sidekick/internal/config"
 )
 
+// documentationReport describes the result of applying the documentation
+// overrides to a model.
+type documentationReport struct {
+	// stale contains the pattern overrides that did not change any element.
+	// These entries can be removed from the configuration.
+	stale []config.DocumentationOverride
+}
+
 // PatchDocumentation overrides the documentation of the API model with the provided configuration.
+//
+// Overrides with an ID containing `*` apply to all the elements with a
+// matching ID, an ID of `*` applies to all the elements in the model. Pattern
+// overrides that do not change any element are logged, as they are probably
+// stale.
 func PatchDocumentation(model *API, config *config.Config) error {
+	report, err := patchDocumentationWithReport(model, config)
+	if err != nil {
+		return err
+	}
+	for _, override := range report.stale {
+		slog.Warn("documentation override did not change any element, consider removing it", "id", override.ID, "match", override.Match)
+	}
+	return nil
+}
+
+// patchDocumentationWithReport overrides the documentation of the API model
+// with the provided configuration, and returns a report of the changes.
+func patchDocumentationWithReport(model *API, config *config.Config) (*documentationReport, error) {
+	report := &documentationReport{}
+	var elements []*documentedElement
 	for _, override := range config.CommentOverrides {
-		id := override.ID
-		if msg, ok := model.State.MessageByID[id]; ok {
-			if err := patchElementDocs(&msg.Documentation, &override); err != nil {
-				return err
-			}
-			continue
+		replacer, err := newDocumentationReplacer(&override)
+		if err != nil {
+			return nil, err
 		}
-		if enu, ok := model.State.EnumByID[id]; ok {
-			if err := patchElementDocs(&enu.Documentation, &override); err != nil {
-				return err
+		if !isPatternOverride(&override) {
+			if err := patchSingleElement(model, &override, replacer); err != nil {
+				return nil, err
 			}
 			continue
 		}
-		if svc, ok := model.State.ServiceByID[id]; ok {
-			if err := patchElementDocs(&svc.Documentation, &override); err != nil {
-				return err
-			}
-			continue
+		if elements == nil {
+			elements = documentedElements(model)
 		}
-		idx := strings.LastIndex(id, ".")
-		if idx == -1 {
-			return fmt.Errorf("cannot find element %s to apply comment overrides", id)
+		patched, err := patchMatchingElements(elements, &override, replacer)
+		if err != nil {
+			return nil, err
 		}
-		parentId := id[0:idx]
-		childId := id[idx+1:]
-		if msg, ok := model.State.MessageByID[parentId]; ok {
-			if err := patchFieldDocs(msg, childId, &override); err != nil {
-				return err
-			}
-			continue
+		if patched == 0 {
+			report.stale = append(report.stale, override)
 		}
-		if enu, ok := model.State.EnumByID[parentId]; ok {
-			if err := patchEnumValueDocs(enu, childId, &override); err != nil {
-				return err
-			}
-			continue
-		}
-		if svc, ok := model.State.ServiceByID[parentId]; ok {
-			if err := patchMethodDocs(svc, childId, &override); err != nil {
-				return err
-			}
-			continue
-		}
-		return fmt.Errorf("cannot find element %s to apply comment overrides, only searched for messages, enums and services", id)
 	}
-	return nil
+	return report, nil
 }
 
-func patchFieldDocs(msg *Message, fieldName string, override *config.DocumentationOverride) error {
+func patchSingleElement(model *API, override *config.DocumentationOverride, replacer func(string) string) error {
+	id := override.ID
+	if msg, ok := model.State.MessageByID[id]; ok {
+		return patchElementDocs(&msg.Documentation, override, replacer)
+	}
+	if enu, ok := model.State.EnumByID[id]; ok {
+		return patchElementDocs(&enu.Documentation, override, replacer)
+	}
+	if svc, ok := model.State.ServiceByID[id]; ok {
+		return patchElementDocs(&svc.Documentation, override, replacer)
+	}
+	idx := strings.LastIndex(id, ".")
+	if idx == -1 {
+		return fmt.Errorf("cannot find element %s to apply comment overrides", id)
+	}
+	parentId := id[0:idx]
+	childId := id[idx+1:]
+	if msg, ok := model.State.MessageByID[parentId]; ok {
+		return patchFieldDocs(msg, childId, override, replacer)
+	}
+	if enu, ok := model.State.EnumByID[parentId]; ok {
+		return patchEnumValueDocs(enu, childId, override, replacer)
+	}
+	if svc, ok := model.State.ServiceByID[parentId]; ok {
+		return patchMethodDocs(svc, childId, override, replacer)
+	}
+	return fmt.Errorf("cannot find element %s to apply comment overrides, only searched for messages, enums and services", id)
+}
+
+func patchFieldDocs(msg *Message, fieldName string, override *config.DocumentationOverride, replacer func(string) string) error {
 	for _, field := range msg.Fields {
 		if field.Name != fieldName {
 			continue
 		}
-		if err := patchElementDocs(&field.Documentation, override); err != nil {
+		if err := patchElementDocs(&field.Documentation, override, replacer); err != nil {
 			return err
 		}
 		return nil
@@ -86,12 +121,12 @@ func patchFieldDocs(msg *Message, fieldName string, override *config.Documentati
 	return fmt.Errorf("cannot find field %s in message %s to apply comment override", fieldName, msg.ID)
 }
 
-func patchEnumValueDocs(enu *Enum, name string, override *config.DocumentationOverride) error {
+func patchEnumValueDocs(enu *Enum, name string, override *config.DocumentationOverride, replacer func(string) string) error {
 	for _, v := range enu.Values {
 		if v.Name != name {
 			continue
 		}
-		if err := patchElementDocs(&v.Documentation, override); err != nil {
+		if err := patchElementDocs(&v.Documentation, override, replacer); err != nil {
 			return err
 		}
 		return nil
@@ -99,12 +134,12 @@ func patchEnumValueDocs(enu *Enum, name string, override *config.DocumentationOv
 	return fmt.Errorf("cannot find field %s in message %s to apply comment override", name, enu.ID)
 }
 
-func patchMethodDocs(svc *Service, name string, override *config.DocumentationOverride) error {
+func patchMethodDocs(svc *Service, name string, override *config.DocumentationOverride, replacer func(string) string) error {
 	for _, m := range svc.Methods {
 		if m.Name != name {
 			continue
 		}
-		if err := patchElementDocs(&m.Documentation, override); err != nil {
+		if err := patchElementDocs(&m.Documentation, override, replacer); err != nil {
 			return err
 		}
 		return nil
@@ -112,8 +147,8 @@ func patchMethodDocs(svc *Service, name string, override *config.DocumentationOv
 	return fmt.Errorf("cannot find field %s in message %s to apply comment override", name, svc.ID)
 }
 
-func patchElementDocs(documentation *string, override *config.DocumentationOverride) error {
-	new := strings.ReplaceAll(*documentation, override.Match, override.Replace)
+func patchElementDocs(documentation *string, override *config.DocumentationOverride, replacer func(string) string) error {
+	new := replacer(*documentation)
 	if *documentation == new {
 		slog.Error("comment override mismatch", "id", override.ID, "want", override.Match, "text", *documentation)
 		return fmt.Errorf("comment override for %s did not match", override.ID)
@@ -121,3 +156,92 @@ func patchElementDocs(documentation *string, override *config.DocumentationOverr
 	*documentation = new
 	return nil
 }
+
+// documentedElement is an element of the model which has documentation.
+type documentedElement struct {
+	id            string
+	documentation *string
+}
+
+// documentedElements returns all the elements in the model with their IDs,
+// sorted by ID so pattern overrides are applied in a deterministic order.
+func documentedElements(model *API) []*documentedElement {
+	var elements []*documentedElement
+	for id, msg := range model.State.MessageByID {
+		elements = append(elements, &documentedElement{id: id, documentation: &msg.Documentation})
+		for _, field := range msg.Fields {
+			elements = append(elements, &documentedElement{id: id + "." + field.Name, documentation: &field.Documentation})
+		}
+	}
+	for id, enu := range model.State.EnumByID {
+		elements = append(elements, &documentedElement{id: id, documentation: &enu.Documentation})
+		for _, v := range enu.Values {
+			elements = append(elements, &documentedElement{id: id + "." + v.Name, documentation: &v.Documentation})
+		}
+	}
+	for id, svc := range model.State.ServiceByID {
+		elements = append(elements, &documentedElement{id: id, documentation: &svc.Documentation})
+		for _, m := range svc.Methods {
+			elements = append(elements, &documentedElement{id: id + "." + m.Name, documentation: &m.Documentation})
+		}
+	}
+	sort.Slice(elements, func(i, j int) bool { return elements[i].id < elements[j].id })
+	return elements
+}
+
+// patchMatchingElements applies a pattern override to all the elements with a
+// matching ID. Elements where the override does not match the documentation
+// are left unchanged. Returns the number of elements changed.
+func patchMatchingElements(elements []*documentedElement, override *config.DocumentationOverride, replacer func(string) string) (int, error) {
+	idRegex, err := globToRegexp(override.ID)
+	if err != nil {
+		return 0, fmt.Errorf("invalid id pattern %q in comment override: %w", override.ID, err)
+	}
+	patched := 0
+	for _, element := range elements {
+		if !idRegex.MatchString(element.id) {
+			continue
+		}
+		new := replacer(*element.documentation)
+		if new == *element.documentation {
+			continue
+		}
+		*element.documentation = new
+		patched++
+	}
+	return patched, nil
+}
+
+// isPatternOverride returns true if the override applies to all the elements
+// with a matching ID.
+func isPatternOverride(override *config.DocumentationOverride) bool {
+	return strings.Contains(override.ID, "*")
+}
+
+// globToRegexp converts an ID pattern to a regular expression. In the pattern
+// `*` matches any sequence of characters, including `.`.
+func globToRegexp(pattern string) (*regexp.Regexp, error) {
+	parts := strings.Split(pattern, "*")
+	for i, part := range parts {
+		parts[i] = regexp.QuoteMeta(part)
+	}
+	return regexp.Compile("^" + strings.Join(parts, ".*") + "$")
+}
+
+// newDocumentationReplacer returns a function applying the override to some
+// documentation.
+func newDocumentationReplacer(override *config.DocumentationOverride) (func(string) string, error) {
+	if !override.Regex {
+		return func(documentation string) string {
+			return strings.ReplaceAll(documentation, override.Match, override.Replace)
+		}, nil
+	}
+	re, err := regexp.Compile(override.Match)
+	if err != nil {
+		return nil, fmt.Errorf("invalid regular expression in comment override for %s: %w", override.ID, err)
+	}
+	replace := override.Replace
+	return func(documentation string) string {
+		return re.ReplaceAllString(documentation, replace)
+	}, nil
+}
@@ -15,6 +15,7 @@
 package api
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
@@ -330,3 +331,150 @@ func TestPatchCommentsMethod(t *testing.T) {
 		t.Errorf("mismatch in enums (-want, +got)\n:%s", diff)
 	}
 }
+
+func TestPatchCommentsPattern(t *testing.T) {
+	model := testPatchCommentsModel()
+	other := &Message{
+		Name:          "Other",
+		Package:       "other",
+		ID:            ".other.Other",
+		Documentation: Input,
+	}
+	model.State.MessageByID[other.ID] = other
+	cfg := config.Config{
+		CommentOverrides: []config.DocumentationOverride{
+			{
+				ID:      ".test.*",
+				Match:   Match,
+				Replace: Replace,
+			},
+		},
+	}
+	report, err := patchDocumentationWithReport(model, &cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(report.stale) != 0 {
+		t.Errorf("expected no stale overrides, got %v", report.stale)
+	}
+	for _, got := range []string{
+		model.State.MessageByID[".test.Message0"].Documentation,
+		model.State.MessageByID[".test.Message0"].Fields[0].Documentation,
+		model.State.EnumByID[".test.Enum0"].Documentation,
+		model.State.EnumByID[".test.Enum0"].Values[0].Documentation,
+		model.State.ServiceByID[".test.Service0"].Documentation,
+		model.State.ServiceByID[".test.Service0"].Methods[0].Documentation,
+	} {
+		if diff := cmp.Diff(Want, got); diff != "" {
+			t.Errorf("mismatch in documentation (-want, +got)\n:%s", diff)
+		}
+	}
+	if diff := cmp.Diff(Input, other.Documentation); diff != "" {
+		t.Errorf("elements outside the pattern should not change (-want, +got)\n:%s", diff)
+	}
+}
+
+func TestPatchCommentsPatternScopes(t *testing.T) {
+	for _, test := range []struct {
+		id   string
+		want int
+	}{
+		{"*", 6},
+		{".test.*.Field0", 1},
+		{".test.Service0*", 2},
+		{".test.Enum0.*", 1},
+		{".*.Message0", 1},
+		{".other.*", 0},
+	} {
+		t.Run(test.id, func(t *testing.T) {
+			model := testPatchCommentsModel()
+			cfg := config.Config{
+				CommentOverrides: []config.DocumentationOverride{
+					{ID: test.id, Match: Match, Replace: Replace},
+				},
+			}
+			if err := PatchDocumentation(model, &cfg); err != nil {
+				t.Fatal(err)
+			}
+			got := 0
+			for _, element := range documentedElements(model) {
+				if *element.documentation == Want {
+					got++
+				}
+			}
+			if got != test.want {
+				t.Errorf("mismatch in the number of patched elements, want=%d, got=%d", test.want, got)
+			}
+		})
+	}
+}
+
+func TestPatchCommentsRegex(t *testing.T) {
+	model := testPatchCommentsModel()
+	cfg := config.Config{
+		CommentOverrides: []config.DocumentationOverride{
+			{
+				ID:      ".test.Message0",
+				Match:   `(?m)^  ([\w ]+ model:)$`,
+				Replace: `* $1`,
+				Regex:   true,
+			},
+			{
+				ID:      "*",
+				Match:   `More (\w+)`,
+				Replace: `Other $1`,
+				Regex:   true,
+			},
+		},
+	}
+	if err := PatchDocumentation(model, &cfg); err != nil {
+		t.Fatal(err)
+	}
+	want := strings.ReplaceAll(Want, "More things", "Other things")
+	if diff := cmp.Diff(want, model.State.MessageByID[".test.Message0"].Documentation); diff != "" {
+		t.Errorf("mismatch in documentation (-want, +got)\n:%s", diff)
+	}
+	want = strings.ReplaceAll(Input, "More things", "Other things")
+	if diff := cmp.Diff(want, model.State.EnumByID[".test.Enum0"].Documentation); diff != "" {
+		t.Errorf("mismatch in documentation (-want, +got)\n:%s", diff)
+	}
+}
+
+func TestPatchCommentsStale(t *testing.T) {
+	model := testPatchCommentsModel()
+	stale := []config.DocumentationOverride{
+		{ID: ".test.*", Match: "NOT A STRING WE WILL FIND", Replace: Replace},
+		{ID: ".missing.*", Match: Match, Replace: Replace},
+	}
+	cfg := config.Config{
+		CommentOverrides: []config.DocumentationOverride{
+			stale[0],
+			{ID: ".test.Message0", Match: Match, Replace: Replace},
+			stale[1],
+		},
+	}
+	report, err := patchDocumentationWithReport(model, &cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := &documentationReport{
+		stale: stale,
+	}
+	if diff := cmp.Diff(want, report, cmp.AllowUnexported(documentationReport{})); diff != "" {
+		t.Errorf("mismatch in report (-want, +got)\n:%s", diff)
+	}
+}
+
+func TestPatchCommentsInvalidRegex(t *testing.T) {
+	for _, id := range []string{".test.Message0", ".test.*"} {
+		model := testPatchCommentsModel()
+		cfg := config.Config{
+			CommentOverrides: []config.DocumentationOverride{
+				{ID: id, Match: "(unterminated", Replace: Replace, Regex: true},
+			},
+		}
+		if err := PatchDocumentation(model, &cfg); err == nil {
+			t.Errorf("expected an error with an invalid regular expression for %q", id)
+		}
+	}
+}
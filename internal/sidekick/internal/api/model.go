@@ -15,8 +15,10 @@
 package api
 
 import (
+	"fmt"
 	"slices"
 	"strings"
+	"time"
 )
 
 // Typez represent different field types that may be found in messages.
@@ -244,6 +246,9 @@ type Method struct {
 	// `google.api.MethodSettings.auto_populated_fields` entry in
 	// `google.api.Publishing.method_settings` in the service config file.
 	AutoPopulated []*Field
+	// RetryPolicy contains the default timeout and retry policy for the
+	// method, if any, as defined in the gRPC service config.
+	RetryPolicy *RetryPolicy
 	// Model is the model this method belongs to, mustache templates use this field to
 	// navigate the data structure.
 	Model *API
@@ -393,6 +398,28 @@ type OperationInfo struct {
 	Codec any
 }
 
+// RetryPolicy contains the default timeout and retry policy for a method.
+//
+// The policy is defined in the `*_grpc_service_config.json` files, see
+// https://github.com/grpc/grpc/blob/master/doc/service_config.md for the
+// format. Methods without retryable status codes should not be retried.
+type RetryPolicy struct {
+	// Timeout is the default timeout for the method, zero if not set.
+	Timeout time.Duration
+	// MaxAttempts is the maximum number of attempts, including the original
+	// request.
+	MaxAttempts int
+	// InitialBackoff is the delay before the first retry.
+	InitialBackoff time.Duration
+	// MaxBackoff is the maximum delay between retries.
+	MaxBackoff time.Duration
+	// BackoffMultiplier is the growth factor for the delay between retries.
+	BackoffMultiplier float64
+	// RetryableCodes are the status codes that can be retried, e.g.
+	// `UNAVAILABLE`.
+	RetryableCodes []string
+}
+
 // RoutingInfo contains normalized routing info.
 //
 // The routing information format is documented in:
@@ -532,6 +559,30 @@ func (p *PathTemplate) WithVerb(v string) *PathTemplate {
 	return p
 }
 
+// TemplateAsString formats the path template using the `google.api.http`
+// syntax, e.g. `/v1/{name=projects/*/secrets/*}:access`.
+func (p *PathTemplate) TemplateAsString() string {
+	var segments []string
+	for _, s := range p.Segments {
+		switch {
+		case s.Literal != nil:
+			segments = append(segments, *s.Literal)
+		case s.Variable != nil:
+			name := strings.Join(s.Variable.FieldPath, ".")
+			if len(s.Variable.Segments) == 0 || (len(s.Variable.Segments) == 1 && s.Variable.Segments[0] == SingleSegmentWildcard) {
+				segments = append(segments, fmt.Sprintf("{%s}", name))
+			} else {
+				segments = append(segments, fmt.Sprintf("{%s=%s}", name, strings.Join(s.Variable.Segments, "/")))
+			}
+		}
+	}
+	path := "/" + strings.Join(segments, "/")
+	if p.Verb != nil {
+		path += ":" + *p.Verb
+	}
+	return path
+}
+
 // WithLiteral adds a literal to the path variable.
 func (v *PathVariable) WithLiteral(l string) *PathVariable {
 	v.Segments = append(v.Segments, l)
@@ -195,6 +195,39 @@ func TestRoutingInfoVariantTemplateAsString(t *testing.T) {
 	}
 }
 
+func TestPathTemplateAsString(t *testing.T) {
+	for _, test := range []struct {
+		template *PathTemplate
+		want     string
+	}{
+		{
+			template: NewPathTemplate().
+				WithLiteral("v1").
+				WithVariableNamed("name"),
+			want: "/v1/{name}",
+		},
+		{
+			template: NewPathTemplate().
+				WithLiteral("v1").
+				WithVariable(NewPathVariable("secret", "name").
+					WithLiteral("projects").WithMatch().
+					WithLiteral("secrets").WithMatch()).
+				WithVerb("access"),
+			want: "/v1/{secret.name=projects/*/secrets/*}:access",
+		},
+		{
+			template: NewPathTemplate().
+				WithLiteral("v1").
+				WithVariable(NewPathVariable("name").WithMatchRecursive()),
+			want: "/v1/{name=**}",
+		},
+	} {
+		if got := test.template.TemplateAsString(); got != test.want {
+			t.Errorf("TemplateAsString() = %q, want %q", got, test.want)
+		}
+	}
+}
+
 func TestPathTemplateBuilder(t *testing.T) {
 	got := NewPathTemplate().
 		WithLiteral("v1").
@@ -16,7 +16,10 @@ package api
 
 import (
 	"fmt"
+	"log/slog"
+	"regexp"
 	"slices"
+	"sort"
 	"strings"
 )
 
@@ -24,12 +27,24 @@ import (
 //
 // The elements to be pruned are determined by the `options` map.
 //
-// The `included-ids` key is a comma-separated list of fully-qualified IDs.
+// The `included-ids` key is a comma-separated list of ID selectors.
 // If this key is present, then any element that is not a dependency of one of
-// the listed IDs is pruned.
+// the selected IDs is pruned. Selecting some methods of a service includes the
+// service, but only with the selected methods.
 //
-// The `skipped-ids` key is a comma-separated list of fully-qualified IDs.
-// If this key is present, then any element with an ID in this list is pruned.
+// The `skipped-ids` key is a comma-separated list of ID selectors.
+// If this key is present, then any selected element is pruned.
+//
+// Each selector is one of:
+//   - a fully-qualified ID, e.g. `.google.cloud.secretmanager.v1.Secret`.
+//   - a glob, where `*` matches any sequence of characters, e.g.
+//     `.google.cloud.secretmanager.v1.*Internal*`.
+//   - a regular expression between slashes, e.g. `/\.Get[A-Z]\w*$/`. The
+//     expression cannot contain commas.
+//
+// A selector starting with `!` removes the matching elements from the
+// selection, for example `.test.Service.*,!.test.Service.GetSecret` selects
+// all the methods in `.test.Service` except `GetSecret`.
 //
 // It is an error to specify both `included-ids` and `skipped-ids`.
 func SkipModelElements(model *API, options map[string]string) error {
@@ -40,7 +55,12 @@ func SkipModelElements(model *API, options map[string]string) error {
 	}
 
 	if included_ok {
-		includedIds, err := FindDependencies(model, strings.Split(included_ids, ","))
+		selection, err := selectIDs(model, included_ids)
+		if err != nil {
+			return err
+		}
+		warnUnusedSelectors("included-ids", selection.unused)
+		includedIds, err := FindDependencies(model, selection.ids())
 		if err != nil {
 			return err
 		}
@@ -49,16 +69,249 @@ func SkipModelElements(model *API, options map[string]string) error {
 	}
 
 	if skipped_ok {
-		skippedIDs := map[string]bool{}
-		for _, id := range strings.Split(skipped_ids, ",") {
-			skippedIDs[id] = true
+		selection, err := selectIDs(model, skipped_ids)
+		if err != nil {
+			return err
 		}
-		skip := func(id string) bool { return skippedIDs[id] }
+		warnUnusedSelectors("skipped-ids", selection.unused)
+		skip := func(id string) bool { return selection.selected[id] != "" }
 		skipModelElementsImpl(model, skip)
 	}
 	return nil
 }
 
+func warnUnusedSelectors(option string, unused []string) {
+	for _, selector := range unused {
+		slog.Warn("selector does not match any element, consider removing it", "option", option, "selector", selector)
+	}
+}
+
+// idSelector selects elements of the model by ID.
+type idSelector struct {
+	// The selector as written in the configuration.
+	raw     string
+	negated bool
+	// Set for fully-qualified IDs.
+	exact string
+	// Set for globs and regular expressions.
+	re *regexp.Regexp
+}
+
+func parseSelector(raw string) (*idSelector, error) {
+	selector := &idSelector{raw: raw}
+	text := raw
+	if strings.HasPrefix(text, "!") {
+		selector.negated = true
+		text = text[1:]
+	}
+	switch {
+	case len(text) > 2 && strings.HasPrefix(text, "/") && strings.HasSuffix(text, "/"):
+		re, err := regexp.Compile(text[1 : len(text)-1])
+		if err != nil {
+			return nil, fmt.Errorf("invalid regular expression in selector %q: %w", raw, err)
+		}
+		selector.re = re
+	case strings.Contains(text, "*"):
+		re, err := globToRegexp(text)
+		if err != nil {
+			return nil, fmt.Errorf("invalid glob in selector %q: %w", raw, err)
+		}
+		selector.re = re
+	default:
+		selector.exact = text
+	}
+	return selector, nil
+}
+
+func (s *idSelector) matches(id string) bool {
+	if s.re != nil {
+		return s.re.MatchString(id)
+	}
+	return s.exact == id
+}
+
+// idSelection is the result of applying a list of selectors to a model.
+type idSelection struct {
+	// The selected IDs, mapped to the first selector that selected them.
+	selected map[string]string
+	// The glob, regular expression, and negated selectors that did not match
+	// any element in the model.
+	unused []string
+}
+
+// ids returns the selected IDs in sorted order.
+func (s *idSelection) ids() []string {
+	var ids []string
+	for id := range s.selected {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// selectIDs applies a comma-separated list of selectors to all the services,
+// methods, messages, and enums in the model.
+//
+// Fully-qualified IDs are always selected, even if they are not found in the
+// model. This lets callers report unknown IDs.
+func selectIDs(model *API, list string) (*idSelection, error) {
+	var selectors []*idSelector
+	for _, raw := range strings.Split(list, ",") {
+		raw = strings.TrimSpace(raw)
+		if raw == "" {
+			continue
+		}
+		selector, err := parseSelector(raw)
+		if err != nil {
+			return nil, err
+		}
+		selectors = append(selectors, selector)
+	}
+	candidates := modelIDs(model)
+	used := map[*idSelector]bool{}
+	selection := &idSelection{selected: map[string]string{}}
+	for _, selector := range selectors {
+		if selector.exact != "" && !selector.negated {
+			selection.selected[selector.exact] = selector.raw
+		}
+	}
+	for _, id := range candidates {
+		for _, selector := range selectors {
+			if !selector.negated && selector.matches(id) {
+				used[selector] = true
+				if _, ok := selection.selected[id]; !ok {
+					selection.selected[id] = selector.raw
+				}
+			}
+		}
+	}
+	for _, id := range candidates {
+		for _, selector := range selectors {
+			if selector.negated && selector.matches(id) {
+				used[selector] = true
+				delete(selection.selected, id)
+			}
+		}
+	}
+	for _, selector := range selectors {
+		// Unknown IDs are reported by the caller, if needed.
+		isID := selector.exact != "" && !selector.negated
+		if !used[selector] && !isID {
+			selection.unused = append(selection.unused, selector.raw)
+		}
+	}
+	return selection, nil
+}
+
+// modelIDs returns the IDs of all the services, methods, messages, and enums
+// in the model, in sorted order.
+func modelIDs(model *API) []string {
+	var ids []string
+	for id := range model.State.ServiceByID {
+		ids = append(ids, id)
+	}
+	for id := range model.State.MethodByID {
+		ids = append(ids, id)
+	}
+	for id := range model.State.MessageByID {
+		ids = append(ids, id)
+	}
+	for id := range model.State.EnumByID {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// SkipReport describes which elements of the model are generated, and which
+// elements were removed by the `included-ids` or `skipped-ids` options.
+type SkipReport struct {
+	// Kept contains the IDs of the elements included in the model.
+	Kept []string
+	// Removed contains the elements removed from the model.
+	Removed []RemovedElement
+	// UnusedSelectors contains the selectors that do not match any element.
+	UnusedSelectors []string
+}
+
+// RemovedElement is an element removed from the model.
+type RemovedElement struct {
+	ID string
+	// Reason explains why the element was removed.
+	Reason string
+}
+
+// ExplainSkip describes the effect of the `included-ids` and `skipped-ids`
+// options on a model that has been pruned with SkipModelElements.
+//
+// Only the elements in the model's package are reported, the elements of
+// dependencies are never generated.
+func ExplainSkip(model *API, options map[string]string) (*SkipReport, error) {
+	report := &SkipReport{}
+	var selection *idSelection
+	var option string
+	if list, ok := options["included-ids"]; ok {
+		option = "included-ids"
+		s, err := selectIDs(model, list)
+		if err != nil {
+			return nil, err
+		}
+		selection = s
+	}
+	if list, ok := options["skipped-ids"]; ok {
+		option = "skipped-ids"
+		s, err := selectIDs(model, list)
+		if err != nil {
+			return nil, err
+		}
+		selection = s
+	}
+	if selection != nil {
+		report.UnusedSelectors = selection.unused
+	}
+
+	generated := generatedElements(model)
+	for _, id := range modelIDs(model) {
+		if !isInPackage(model, id) {
+			continue
+		}
+		if generated[id] {
+			report.Kept = append(report.Kept, id)
+			continue
+		}
+		removed := RemovedElement{ID: id}
+		switch {
+		case option == "skipped-ids" && selection.selected[id] != "":
+			removed.Reason = fmt.Sprintf("matches the skipped-ids selector %q", selection.selected[id])
+		case option == "included-ids":
+			removed.Reason = "not required by any element selected by included-ids"
+		default:
+			removed.Reason = "its parent was removed"
+		}
+		report.Removed = append(report.Removed, removed)
+	}
+	return report, nil
+}
+
+// isInPackage returns true if the element is defined in the package of the
+// model.
+func isInPackage(model *API, id string) bool {
+	state := model.State
+	if m, ok := state.MessageByID[id]; ok {
+		return m.Package == model.PackageName
+	}
+	if e, ok := state.EnumByID[id]; ok {
+		return e.Package == model.PackageName
+	}
+	if s, ok := state.ServiceByID[id]; ok {
+		return s.Package == model.PackageName
+	}
+	if m, ok := state.MethodByID[id]; ok && m.Service != nil {
+		return m.Service.Package == model.PackageName
+	}
+	return true
+}
+
 func skipModelElementsImpl(model *API, skip func(id string) bool) {
 	for _, m := range model.Messages {
 		skipMessageElements(m, skip)
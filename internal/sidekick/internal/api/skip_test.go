@@ -366,3 +366,252 @@ func TestIncludeMethods(t *testing.T) {
 		t.Errorf("mismatch in methods (-want, +got)\n:%s", diff)
 	}
 }
+
+func TestSkipSelectors(t *testing.T) {
+	for _, test := range []struct {
+		name    string
+		options map[string]string
+		want    []string
+	}{
+		{
+			name:    "glob",
+			options: map[string]string{"skipped-ids": ".test.*Internal*"},
+			want:    []string{".test.Secret", ".test.SecretVersion", ".test.Service", ".test.Service.DeleteSecret", ".test.Service.GetSecret"},
+		},
+		{
+			name:    "regex",
+			options: map[string]string{"skipped-ids": `/^\.test\.Secret\w*$/`},
+			want:    []string{".test.InternalState", ".test.Service", ".test.Service.DeleteSecret", ".test.Service.GetSecret"},
+		},
+		{
+			name:    "negated glob",
+			options: map[string]string{"skipped-ids": ".test.*Internal*,!.test.InternalState"},
+			want:    []string{".test.InternalState", ".test.Secret", ".test.SecretVersion", ".test.Service", ".test.Service.DeleteSecret", ".test.Service.GetSecret"},
+		},
+		{
+			name:    "include glob",
+			options: map[string]string{"included-ids": ".test.Secret*"},
+			want:    []string{".test.Secret", ".test.Secret.InternalDetails", ".test.SecretVersion"},
+		},
+		{
+			name:    "include with negation",
+			options: map[string]string{"included-ids": ".test.Secret*,!.test.Secret.*"},
+			want:    []string{".test.Secret", ".test.SecretVersion"},
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			model := newSelectorTestAPI()
+			if err := SkipModelElements(model, test.options); err != nil {
+				t.Fatal(err)
+			}
+			var got []string
+			for id := range generatedElements(model) {
+				got = append(got, id)
+			}
+			if diff := cmp.Diff(test.want, got, cmpopts.SortSlices(func(a, b string) bool { return a < b })); diff != "" {
+				t.Errorf("mismatch in generated elements (-want, +got)\n:%s", diff)
+			}
+		})
+	}
+}
+
+func TestIncludeServiceWithSelectedMethods(t *testing.T) {
+	model := newSelectorTestAPI()
+	if err := SkipModelElements(model, map[string]string{
+		"included-ids": ".test.Service.*,!.test.Service.DeleteSecret",
+	}); err != nil {
+		t.Fatal(err)
+	}
+	var gotServices []string
+	for _, s := range model.Services {
+		gotServices = append(gotServices, s.ID)
+	}
+	if diff := cmp.Diff([]string{".test.Service"}, gotServices); diff != "" {
+		t.Errorf("mismatch in services (-want, +got)\n:%s", diff)
+	}
+	var gotMethods []string
+	for _, m := range model.Services[0].Methods {
+		gotMethods = append(gotMethods, m.ID)
+	}
+	if diff := cmp.Diff([]string{".test.Service.GetSecret"}, gotMethods); diff != "" {
+		t.Errorf("mismatch in methods (-want, +got)\n:%s", diff)
+	}
+	var gotMessages []string
+	for _, m := range model.Messages {
+		gotMessages = append(gotMessages, m.ID)
+	}
+	if diff := cmp.Diff([]string{".test.Secret"}, gotMessages); diff != "" {
+		t.Errorf("mismatch in messages (-want, +got)\n:%s", diff)
+	}
+}
+
+func TestSelectIDs(t *testing.T) {
+	for _, test := range []struct {
+		name       string
+		list       string
+		want       map[string]string
+		wantUnused []string
+	}{
+		{
+			name: "exact",
+			list: ".test.Secret, .test.Unknown",
+			want: map[string]string{
+				".test.Secret":  ".test.Secret",
+				".test.Unknown": ".test.Unknown",
+			},
+		},
+		{
+			name: "first selector wins",
+			list: ".test.Secret*,/Version$/",
+			want: map[string]string{
+				".test.Secret":                 ".test.Secret*",
+				".test.Secret.InternalDetails": ".test.Secret*",
+				".test.SecretVersion":          ".test.Secret*",
+			},
+			wantUnused: []string{},
+		},
+		{
+			name: "unused",
+			list: ".test.*Internal*,.test.Missing*,!/Deprecated/",
+			want: map[string]string{
+				".test.InternalState":          ".test.*Internal*",
+				".test.Secret.InternalDetails": ".test.*Internal*",
+			},
+			wantUnused: []string{".test.Missing*", "!/Deprecated/"},
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := selectIDs(newSelectorTestAPI(), test.list)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(test.want, got.selected); diff != "" {
+				t.Errorf("mismatch in selected IDs (-want, +got)\n:%s", diff)
+			}
+			if diff := cmp.Diff(test.wantUnused, got.unused, cmpopts.EquateEmpty()); diff != "" {
+				t.Errorf("mismatch in unused selectors (-want, +got)\n:%s", diff)
+			}
+		})
+	}
+}
+
+func TestSelectIDsInvalidRegex(t *testing.T) {
+	model := newSelectorTestAPI()
+	if err := SkipModelElements(model, map[string]string{"skipped-ids": "/(/"}); err == nil {
+		t.Errorf("expected an error with an invalid regular expression")
+	}
+}
+
+func TestExplainSkip(t *testing.T) {
+	model := newSelectorTestAPI()
+	options := map[string]string{
+		"skipped-ids": ".test.Secret,.test.*Deprecated*",
+	}
+	if err := SkipModelElements(model, options); err != nil {
+		t.Fatal(err)
+	}
+	got, err := ExplainSkip(model, options)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := &SkipReport{
+		Kept: []string{
+			".test.InternalState",
+			".test.SecretVersion",
+			".test.Service",
+			".test.Service.DeleteSecret",
+			".test.Service.GetSecret",
+		},
+		Removed: []RemovedElement{
+			{ID: ".test.Secret", Reason: `matches the skipped-ids selector ".test.Secret"`},
+			{ID: ".test.Secret.InternalDetails", Reason: "its parent was removed"},
+		},
+		UnusedSelectors: []string{".test.*Deprecated*"},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("mismatch in ExplainSkip (-want, +got)\n:%s", diff)
+	}
+}
+
+func TestExplainSkipIncluded(t *testing.T) {
+	model := newSelectorTestAPI()
+	options := map[string]string{
+		"included-ids": ".test.Service.GetSecret",
+	}
+	if err := SkipModelElements(model, options); err != nil {
+		t.Fatal(err)
+	}
+	got, err := ExplainSkip(model, options)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := &SkipReport{
+		Kept: []string{
+			".test.Secret",
+			".test.Service",
+			".test.Service.GetSecret",
+		},
+		Removed: []RemovedElement{
+			{ID: ".test.InternalState", Reason: "not required by any element selected by included-ids"},
+			{ID: ".test.Secret.InternalDetails", Reason: "not required by any element selected by included-ids"},
+			{ID: ".test.SecretVersion", Reason: "not required by any element selected by included-ids"},
+			{ID: ".test.Service.DeleteSecret", Reason: "not required by any element selected by included-ids"},
+		},
+	}
+	if diff := cmp.Diff(want, got, cmpopts.EquateEmpty()); diff != "" {
+		t.Errorf("mismatch in ExplainSkip (-want, +got)\n:%s", diff)
+	}
+}
+
+func newSelectorTestAPI() *API {
+	secret := &Message{
+		Name:    "Secret",
+		Package: "test",
+		ID:      ".test.Secret",
+	}
+	details := &Message{
+		Name:    "InternalDetails",
+		Package: "test",
+		ID:      ".test.Secret.InternalDetails",
+	}
+	version := &Message{
+		Name:    "SecretVersion",
+		Package: "test",
+		ID:      ".test.SecretVersion",
+	}
+	state := &Enum{
+		Name:    "InternalState",
+		Package: "test",
+		ID:      ".test.InternalState",
+	}
+	empty := &Message{
+		Name:    "Empty",
+		Package: "google.protobuf",
+		ID:      ".google.protobuf.Empty",
+	}
+	service := &Service{
+		Name:    "Service",
+		Package: "test",
+		ID:      ".test.Service",
+		Methods: []*Method{
+			{
+				Name:         "GetSecret",
+				ID:           ".test.Service.GetSecret",
+				InputTypeID:  ".test.Secret",
+				OutputTypeID: ".test.Secret",
+			},
+			{
+				Name:         "DeleteSecret",
+				ID:           ".test.Service.DeleteSecret",
+				InputTypeID:  ".test.Secret",
+				OutputTypeID: ".google.protobuf.Empty",
+			},
+		},
+	}
+	model := NewTestAPI([]*Message{secret, details, version, empty}, []*Enum{state}, []*Service{service})
+	CrossReference(model)
+	// NewTestAPI adds nested messages at the top level too, the parsers do
+	// not.
+	model.Messages = []*Message{secret, version}
+	return model
+}
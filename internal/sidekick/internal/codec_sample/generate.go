@@ -20,13 +20,30 @@ import (
 	"github.com/googleapis/librarian/internal/sidekick/internal/api"
 	"github.com/googleapis/librarian/internal/sidekick/internal/config"
 	"github.com/googleapis/librarian/internal/sidekick/internal/language"
+	"github.com/googleapis/librarian/internal/sidekick/internal/license"
 )
 
 //go:embed all:templates
 var templates embed.FS
 
+// modelAnnotations contains the annotations used by the sample templates.
+type modelAnnotations struct {
+	CopyrightYear   string
+	CopyrightHolder string
+	BoilerPlate     []string
+}
+
 // Generate generates code from the model.
 func Generate(model *api.API, outdir string, cfg *config.Config) error {
+	header, err := license.NewHeaderFromOptions(cfg.Codec)
+	if err != nil {
+		return err
+	}
+	model.Codec = &modelAnnotations{
+		CopyrightYear:   cfg.Codec["copyright-year"],
+		CopyrightHolder: header.Holder,
+		BoilerPlate:     header.Bulk,
+	}
 	// A template provide converts a template name into the contents.
 	provider := func(name string) (string, error) {
 		contents, err := templates.ReadFile(name)
@@ -37,5 +54,5 @@ func Generate(model *api.API, outdir string, cfg *config.Config) error {
 	}
 	// The list of files to generate, just load them from the embedded templates.
 	generatedFiles := language.WalkTemplatesDir(templates, "templates/readme")
-	return language.GenerateFromModel(outdir, model, provider, generatedFiles)
+	return language.GenerateFromModelWithOverrides(outdir, model, provider, generatedFiles, cfg.Codec)
 }
@@ -19,6 +19,7 @@ import (
 	"os/exec"
 	"path"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/googleapis/librarian/internal/sidekick/internal/config"
@@ -69,3 +70,70 @@ func TestFromProtobuf(t *testing.T) {
 		t.Errorf("generated files should not be executable %s: %o", filename, stat.Mode())
 	}
 }
+
+func TestLicenseHeader(t *testing.T) {
+	outDir := t.TempDir()
+	cfg := &config.Config{
+		General: config.GeneralConfig{
+			SpecificationFormat: "openapi",
+			ServiceConfig:       path.Join(testdataDir, "googleapis/google/cloud/secretmanager/v1/secretmanager_v1.yaml"),
+			SpecificationSource: path.Join(testdataDir, "openapi/secretmanager_openapi_v1.json"),
+		},
+		Codec: map[string]string{
+			"copyright-year":   "2038",
+			"copyright-holder": "Example Inc.",
+			"license":          "MIT",
+		},
+	}
+	model, err := parser.CreateModel(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := Generate(model, outDir, cfg); err != nil {
+		t.Fatal(err)
+	}
+	contents, err := os.ReadFile(path.Join(outDir, "README.md"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"Copyright 2038 Example Inc.\n", " SPDX-License-Identifier: MIT\n"} {
+		if !strings.Contains(string(contents), want) {
+			t.Errorf("missing %q in README.md:\n%s", want, contents)
+		}
+	}
+}
+
+func TestTemplateOverrides(t *testing.T) {
+	templateDir := t.TempDir()
+	if err := os.MkdirAll(path.Join(templateDir, "readme"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path.Join(templateDir, "readme", "README.md.mustache"), []byte("# Custom - {{{Title}}}\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	outDir := t.TempDir()
+	cfg := &config.Config{
+		General: config.GeneralConfig{
+			SpecificationFormat: "openapi",
+			ServiceConfig:       path.Join(testdataDir, "googleapis/google/cloud/secretmanager/v1/secretmanager_v1.yaml"),
+			SpecificationSource: path.Join(testdataDir, "openapi/secretmanager_openapi_v1.json"),
+		},
+		Codec: map[string]string{
+			"template-dir": templateDir,
+		},
+	}
+	model, err := parser.CreateModel(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := Generate(model, outDir, cfg); err != nil {
+		t.Fatal(err)
+	}
+	contents, err := os.ReadFile(path.Join(outDir, "README.md"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "# Custom - Secret Manager API\n"; string(contents) != want {
+		t.Errorf("mismatch in README.md, got=%q, want=%q", contents, want)
+	}
+}
@@ -45,10 +45,17 @@ const (
 // comments upstream, and then getting a new version of the services
 // specification. The exception may be when the fixes take a long time, or are
 // specific to one language.
+//
+// The ID may contain `*`, which matches any sequence of characters. Such an
+// override applies to every element with a matching ID whose documentation
+// matches, and an ID of `*` applies to all the elements in the model.
 type DocumentationOverride struct {
 	ID      string `toml:"id"`
 	Match   string `toml:"match"`
 	Replace string `toml:"replace"`
+	// If true, Match is a regular expression (using RE2 syntax) and Replace
+	// may refer to its submatches, for example, `$1`.
+	Regex bool `toml:"regex,omitempty"`
 }
 
 // Config is the main configuration struct.
@@ -72,6 +79,7 @@ type GeneralConfig struct {
 	SpecificationFormat string   `toml:"specification-format,omitempty"`
 	SpecificationSource string   `toml:"specification-source,omitempty"`
 	ServiceConfig       string   `toml:"service-config,omitempty"`
+	GrpcServiceConfig   string   `toml:"grpc-service-config,omitempty"`
 	IgnoredDirectories  []string `toml:"ignored-directories,omitempty"`
 }
 
@@ -144,10 +152,11 @@ func mergeConfigs(rootConfig, local *Config) *Config {
 		merged.Source[k] = v
 	}
 
-	// Ignore `SpecificationSource` and `ServiceConfig` at the top-level
-	// configuration. It makes no sense to set those globally.
+	// Ignore `SpecificationSource`, `ServiceConfig`, and `GrpcServiceConfig`
+	// at the top-level configuration. It makes no sense to set those globally.
 	merged.General.SpecificationSource = local.General.SpecificationSource
 	merged.General.ServiceConfig = local.General.ServiceConfig
+	merged.General.GrpcServiceConfig = local.General.GrpcServiceConfig
 	if local.General.SpecificationFormat != "" {
 		merged.General.SpecificationFormat = local.General.SpecificationFormat
 	}
@@ -272,6 +281,10 @@ func getLatestSha(query string) (string, error) {
 
 // WriteSidekickToml writes the configuration to a .sidekick.toml file.
 func WriteSidekickToml(outDir string, config *Config) error {
+	header, err := license.NewHeaderFromOptions(config.Codec)
+	if err != nil {
+		return err
+	}
 	if err := os.MkdirAll(outDir, 0777); err != nil {
 		return err
 	}
@@ -282,7 +295,7 @@ func WriteSidekickToml(outDir string, config *Config) error {
 	defer f.Close()
 
 	year := config.Codec["copyright-year"]
-	for _, line := range license.LicenseHeader(year) {
+	for _, line := range header.Lines(year) {
 		if line == "" {
 			fmt.Fprintln(f, "#")
 		} else {
@@ -300,6 +300,7 @@ func TestMergeIgnoreRootSourceAndServiceConfig(t *testing.T) {
 			SpecificationFormat: "root-specification-format",
 			SpecificationSource: "root-specification-source",
 			ServiceConfig:       "root-service-config",
+			GrpcServiceConfig:   "root-grpc-service-config",
 		},
 	}
 
@@ -43,9 +43,12 @@ type modelAnnotations struct {
 	MainFileName      string
 	SourcePackageName string
 	CopyrightYear     string
-	BoilerPlate       []string
-	DefaultHost       string
-	DocLines          []string
+	CopyrightHolder   string
+	// The SPDX ID of the license, empty for custom license headers.
+	License     string
+	BoilerPlate []string
+	DefaultHost string
+	DocLines    []string
 	// A reference to an optional hand-written part file.
 	PartFileReference   string
 	PackageDependencies []packageDependency
@@ -265,6 +268,10 @@ func (annotate *annotateModel) annotateModel(options map[string]string) error {
 			annotate.dependencyConstraints[strings.TrimPrefix(key, "package:")] = definition
 		}
 	}
+	header, err := license.NewHeaderFromOptions(options)
+	if err != nil {
+		return err
+	}
 
 	// Register any missing WKTs.
 	registerMissingWkt(annotate.state)
@@ -300,12 +307,14 @@ func (annotate *annotateModel) annotateModel(options map[string]string) error {
 	packageDependencies := calculateDependencies(annotate.imports, annotate.dependencyConstraints)
 
 	ann := &modelAnnotations{
-		Parent:         model,
-		PackageName:    packageName(model, packageNameOverride),
-		PackageVersion: packageVersion,
-		MainFileName:   strcase.ToSnake(model.Name),
-		CopyrightYear:  generationYear,
-		BoilerPlate: append(license.LicenseHeaderBulk(),
+		Parent:          model,
+		PackageName:     packageName(model, packageNameOverride),
+		PackageVersion:  packageVersion,
+		MainFileName:    strcase.ToSnake(model.Name),
+		CopyrightYear:   generationYear,
+		CopyrightHolder: header.Holder,
+		License:         header.License,
+		BoilerPlate: append(header.Bulk,
 			"",
 			" Code generated by sidekick. DO NOT EDIT."),
 		DefaultHost: func() string {
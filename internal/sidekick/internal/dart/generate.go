@@ -16,11 +16,15 @@ package dart
 
 import (
 	"embed"
+	"fmt"
 	"path/filepath"
+	"slices"
+	"strconv"
 
 	"github.com/googleapis/librarian/internal/sidekick/internal/api"
 	"github.com/googleapis/librarian/internal/sidekick/internal/config"
 	"github.com/googleapis/librarian/internal/sidekick/internal/language"
+	"github.com/googleapis/librarian/internal/sidekick/internal/license"
 )
 
 //go:embed templates
@@ -34,7 +38,7 @@ func Generate(model *api.API, outdir string, config *config.Config) error {
 	}
 
 	provider := templatesProvider()
-	err := language.GenerateFromModel(outdir, model, provider, generatedFiles(model))
+	err := generate(outdir, model, provider, annotate, config.Codec)
 	if err == nil {
 		// Check if we're configured to skip formatting.
 		skipFormat := config.Codec["skip-format"]
@@ -45,6 +49,20 @@ func Generate(model *api.API, outdir string, config *config.Config) error {
 	return err
 }
 
+func generate(outdir string, model *api.API, provider language.TemplateProvider, annotate *annotateModel, options map[string]string) error {
+	var snippets *language.SnippetFiles
+	if value, ok := options[language.GenerateSnippetsOption]; ok {
+		generateSnippets, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("cannot convert `%s` value %q to boolean: %w", language.GenerateSnippetsOption, value, err)
+		}
+		if generateSnippets {
+			snippets = &language.SnippetFiles{Language: "dart", Dir: snippetsDir, Snippets: annotate.snippets()}
+		}
+	}
+	return language.GenerateFromModelAndSnippets(outdir, model, provider, generatedFiles(model), snippets, options)
+}
+
 func templatesProvider() language.TemplateProvider {
 	return func(name string) (string, error) {
 		name = filepath.ToSlash(name)
@@ -61,6 +79,12 @@ func generatedFiles(model *api.API) []language.GeneratedFile {
 	mainFileName := codec.MainFileName
 
 	files := language.WalkTemplatesDir(dartTemplates, "templates")
+	if codec.License != license.DefaultLicense {
+		// The LICENSE file contains the Apache-2.0 license text.
+		files = slices.DeleteFunc(files, func(f language.GeneratedFile) bool {
+			return filepath.Base(f.TemplatePath) == "LICENSE.txt.mustache"
+		})
+	}
 
 	for index, fileInfo := range files {
 		// Replace 'main.dart' with '{servicename}.dart'
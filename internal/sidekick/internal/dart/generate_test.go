@@ -20,11 +20,13 @@ import (
 	"os/exec"
 	"path"
 	"path/filepath"
+	"slices"
 	"strings"
 	"testing"
 
 	"github.com/googleapis/librarian/internal/sidekick/internal/api"
 	"github.com/googleapis/librarian/internal/sidekick/internal/config"
+	"github.com/googleapis/librarian/internal/sidekick/internal/language"
 	"github.com/googleapis/librarian/internal/sidekick/internal/parser"
 )
 
@@ -93,6 +95,29 @@ func TestGeneratedFiles(t *testing.T) {
 	}
 }
 
+func TestGeneratedFilesLicense(t *testing.T) {
+	for _, test := range []struct {
+		license     string
+		wantLicense bool
+	}{
+		{license: "", wantLicense: true},
+		{license: "Apache-2.0", wantLicense: true},
+		{license: "MIT", wantLicense: false},
+	} {
+		model := api.NewTestAPI([]*api.Message{}, []*api.Enum{}, []*api.Service{})
+		annotate := newAnnotateModel(model)
+		if err := annotate.annotateModel(map[string]string{"license": test.license}); err != nil {
+			t.Fatal(err)
+		}
+		got := slices.ContainsFunc(generatedFiles(model), func(f language.GeneratedFile) bool {
+			return filepath.Base(f.OutputPath) == "LICENSE"
+		})
+		if got != test.wantLicense {
+			t.Errorf("generatedFiles() contains LICENSE = %v, want %v for license %q", got, test.wantLicense, test.license)
+		}
+	}
+}
+
 func TestTemplatesAvailable(t *testing.T) {
 	var count = 0
 	fs.WalkDir(dartTemplates, "templates", func(path string, d fs.DirEntry, err error) error {
@@ -0,0 +1,80 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dart
+
+import (
+	"slices"
+
+	"github.com/googleapis/librarian/internal/sidekick/internal/language"
+	"github.com/googleapis/librarian/internal/sidekick/internal/license"
+	"github.com/googleapis/librarian/internal/sidekick/internal/options"
+	"github.com/googleapis/librarian/internal/sidekick/internal/surface"
+)
+
+// CodecOptions describes the codec options supported by the Dart codec.
+var CodecOptions = slices.Concat([]options.Option{
+	{
+		Name:        "package-name-override",
+		Type:        options.String,
+		Description: "The name of the generated package, instead of the name derived from the specification package.",
+	},
+	{
+		Name:        "version",
+		Type:        options.String,
+		Description: "The version of the generated package.",
+	},
+	{
+		Name:        "part-file",
+		Type:        options.String,
+		Description: "A handwritten file included as a `part` of the main library file.",
+	},
+	{
+		Name:        "dev-dependencies",
+		Type:        options.List,
+		Description: "The packages added to `dev_dependencies` in `pubspec.yaml`.",
+	},
+	{
+		Name:        "not-for-publication",
+		Type:        options.Bool,
+		Default:     "false",
+		Description: "If true, the package is not published, e.g. because it is only used in tests.",
+	},
+	{
+		Name:        "repository-url",
+		Type:        options.String,
+		Description: "The repository URL in `pubspec.yaml`.",
+	},
+	{
+		Name:        "proto:<package>",
+		Type:        options.String,
+		Description: "The Dart import for the messages and enums in the protobuf `<package>`.",
+	},
+	{
+		Name:        "prefix:<package>",
+		Type:        options.String,
+		Description: "The import prefix for the Dart library of the protobuf `<package>`.",
+	},
+	{
+		Name:        "package:<name>",
+		Type:        options.String,
+		Description: "The version constraint for the Dart package `<name>`, used if the package is a dependency.",
+	},
+	{
+		Name:        "skip-format",
+		Type:        options.Bool,
+		Default:     "false",
+		Description: "If true, the generated files are not formatted.",
+	},
+}, license.Options, language.TemplateOptions, language.SnippetOptions, surface.Options)
@@ -0,0 +1,193 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dart
+
+import (
+	"fmt"
+	"path/filepath"
+	"slices"
+
+	"github.com/googleapis/librarian/internal/sidekick/internal/api"
+	"github.com/googleapis/librarian/internal/sidekick/internal/language"
+	"github.com/iancoleman/strcase"
+)
+
+// The snippets are generated in the conventional directory for Dart examples.
+const snippetsDir = "example"
+
+type snippetAnnotation struct {
+	RegionTag string
+	Model     *api.API
+	Service   *api.Service
+	Method    *api.Method
+	Imports   []string
+	// The names of the client class and the client method.
+	ClientName  string
+	MethodName  string
+	RequestType string
+	// The fields set in the request, in the order they are set.
+	RequiredFields []*snippetField
+	LongRunning    bool
+	ReturnsEmpty   bool
+	// If true, the service has a `getOperation()` method to poll long-running
+	// operations.
+	CanPoll bool
+	// The request and response fields used to iterate over paginated
+	// responses. These are empty if the method is not paginated.
+	PageTokenName     string
+	NextPageTokenName string
+	ItemsName         string
+}
+
+// Paginated returns true if the snippet iterates over the pages of the
+// response.
+func (s *snippetAnnotation) Paginated() bool {
+	return s.PageTokenName != ""
+}
+
+type snippetField struct {
+	Name  string
+	Value string
+}
+
+// snippets returns the samples for each generated method.
+func (annotate *annotateModel) snippets() []language.Snippet {
+	model := annotate.model
+	ann := model.Codec.(*modelAnnotations)
+	var snippets []language.Snippet
+	for _, s := range model.Services {
+		svcAnn := s.Codec.(*serviceAnnotations)
+		canPoll := slices.ContainsFunc(svcAnn.Methods, func(m *api.Method) bool {
+			return m.Codec.(*methodAnnotation).IsLROGetOperation
+		})
+		for _, m := range svcAnn.Methods {
+			methodAnn := m.Codec.(*methodAnnotation)
+			if methodAnn.IsLROGetOperation {
+				// Requires an existing operation, there is nothing to
+				// demonstrate in isolation.
+				continue
+			}
+			imports := map[string]bool{
+				fmt.Sprintf("package:%s/%s.dart", ann.PackageName, ann.MainFileName): true,
+				httpImport: true,
+			}
+			var fields []*snippetField
+			for _, f := range language.SnippetRequiredFields(m, annotate.state) {
+				fields = append(fields, &snippetField{
+					Name:  fieldName(f),
+					Value: annotate.snippetValue(m, f, imports),
+				})
+			}
+			regionTag := language.SnippetRegionTag(model, s, m)
+			snippet := &snippetAnnotation{
+				RegionTag:      regionTag,
+				Model:          model,
+				Service:        s,
+				Method:         m,
+				Imports:        calculateImports(imports),
+				ClientName:     svcAnn.Name,
+				MethodName:     methodAnn.Name,
+				RequestType:    methodAnn.RequestType,
+				RequiredFields: fields,
+				LongRunning:    m.OperationInfo != nil,
+				ReturnsEmpty:   m.ReturnsEmpty,
+				CanPoll:        canPoll,
+			}
+			if m.Pagination != nil && m.OutputType.Pagination != nil {
+				snippet.PageTokenName = fieldName(m.Pagination)
+				snippet.NextPageTokenName = fieldName(m.OutputType.Pagination.NextPageToken)
+				snippet.ItemsName = fieldName(m.OutputType.Pagination.PageableItem)
+			}
+			snippets = append(snippets, language.Snippet{
+				RegionTag:    regionTag,
+				Service:      s,
+				Method:       m,
+				TemplatePath: "templates/snippets/snippet.mustache",
+				OutputPath:   filepath.Join(snippetsDir, strcase.ToSnake(s.Name)+"_"+strcase.ToSnake(m.Name)+".dart"),
+				Context:      snippet,
+			})
+		}
+	}
+	return snippets
+}
+
+// snippetValue returns a Dart expression suitable to initialize `f`. Any
+// imports required by the expression are added to `imports`.
+func (annotate *annotateModel) snippetValue(m *api.Method, f *api.Field, imports map[string]bool) string {
+	if f.Typez == api.MESSAGE_TYPE {
+		if msg, ok := annotate.state.MessageByID[f.TypezID]; ok && msg.IsMap {
+			key := annotate.snippetValue(m, msg.Fields[0], imports)
+			value := annotate.snippetValue(m, msg.Fields[1], imports)
+			return fmt.Sprintf("{%s: %s}", key, value)
+		}
+	}
+	value := annotate.snippetSingularValue(m, f, imports)
+	if f.Repeated {
+		return fmt.Sprintf("[%s]", value)
+	}
+	return value
+}
+
+func (annotate *annotateModel) snippetSingularValue(m *api.Method, f *api.Field, imports map[string]bool) string {
+	switch f.Typez {
+	case api.MESSAGE_TYPE:
+		msg, ok := annotate.state.MessageByID[f.TypezID]
+		if !ok {
+			return "null"
+		}
+		return annotate.snippetTypeName(msg.Package, messageName(msg), imports) + "()"
+	case api.ENUM_TYPE:
+		e, ok := annotate.state.EnumByID[f.TypezID]
+		if !ok || len(e.Values) == 0 {
+			return "null"
+		}
+		// Prefer a value other than the default, which is typically
+		// `UNSPECIFIED`.
+		value := e.Values[0]
+		if index := slices.IndexFunc(e.Values, func(v *api.EnumValue) bool { return v.Number != 0 }); index != -1 {
+			value = e.Values[index]
+		}
+		return annotate.snippetTypeName(e.Package, enumName(e), imports) + "." + enumValueName(value)
+	case api.STRING_TYPE:
+		return fmt.Sprintf("'%s'", language.SnippetStringValue(m, f))
+	case api.BYTES_TYPE:
+		imports[typedDataImport] = true
+		return "Uint8List.fromList([1, 2, 3])"
+	case api.BOOL_TYPE:
+		return "true"
+	case api.FLOAT_TYPE, api.DOUBLE_TYPE:
+		return "42.0"
+	default:
+		return "42"
+	}
+}
+
+// snippetTypeName returns the name of a type used in a snippet, and adds any
+// import needed to reference it.
+func (annotate *annotateModel) snippetTypeName(packageName, name string, imports map[string]bool) string {
+	if annotate.model.PackageName == packageName {
+		return name
+	}
+	dartImport, ok := annotate.packageMapping[packageName]
+	if !ok {
+		return name
+	}
+	if prefix, ok := annotate.packagePrefixes[packageName]; ok {
+		imports[dartImport+" as "+prefix] = true
+		return prefix + "." + name
+	}
+	imports[dartImport] = true
+	return name
+}
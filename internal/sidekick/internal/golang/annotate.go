@@ -0,0 +1,818 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package golang
+
+import (
+	"fmt"
+	"log/slog"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/googleapis/librarian/internal/sidekick/internal/api"
+	"github.com/googleapis/librarian/internal/sidekick/internal/language"
+	"github.com/googleapis/librarian/internal/sidekick/internal/license"
+)
+
+const (
+	operationID = ".google.longrunning.Operation"
+	emptyID     = ".google.protobuf.Empty"
+
+	defaultGoVersion = "1.23"
+)
+
+type modelAnnotations struct {
+	Parent *api.API
+	// The Go package name (e.g. secretmanager).
+	PackageName string
+	// The Go module path, used in the generated go.mod file.
+	ModulePath string
+	// The minimum Go version, used in the generated go.mod file.
+	GoVersion       string
+	CopyrightYear   string
+	CopyrightHolder string
+	BoilerPlate     []string
+	DocLines        []string
+	// The imports needed by the file containing the messages and enums.
+	TypesImports []string
+	// The imports needed by the file containing the service clients.
+	ClientImports []string
+	// The modules required by the generated go.mod file.
+	Requires []moduleRequirement
+	// If true, at least one method returns a long-running operation.
+	HasLROs bool
+}
+
+// HasServices returns true if the model has services.
+func (m *modelAnnotations) HasServices() bool {
+	return len(m.Parent.Services) > 0
+}
+
+// HasTypes returns true if the model has messages or enums to generate.
+func (m *modelAnnotations) HasTypes() bool {
+	return len(m.Parent.Messages) > 0 || len(m.Parent.Enums) > 0
+}
+
+// HasDocLines returns true if the API has a description.
+func (m *modelAnnotations) HasDocLines() bool {
+	return len(m.DocLines) > 0
+}
+
+// HasTypesImports returns true if the types file imports other packages.
+func (m *modelAnnotations) HasTypesImports() bool {
+	return len(m.TypesImports) > 0
+}
+
+// HasRequires returns true if the generated go.mod requires other modules.
+func (m *modelAnnotations) HasRequires() bool {
+	return len(m.Requires) > 0
+}
+
+type moduleRequirement struct {
+	Path    string
+	Version string
+}
+
+type serviceAnnotations struct {
+	// The name of the client type, e.g. SecretManagerServiceClient.
+	Name string
+	// The name of the function returning a new client.
+	ConstructorName string
+	DocLines        []string
+	Methods         []*api.Method
+	DefaultEndpoint string
+}
+
+type methodAnnotation struct {
+	// The method name using Go naming conventions.
+	Name     string
+	DocLines []string
+	// The name of the client type the method belongs to.
+	ClientName string
+	// The Go type of the request, without the pointer.
+	RequestType string
+	// The Go type of the response, without the pointer.
+	ResponseType string
+	// The Go type returned by the method, without the pointer. This is an
+	// `OperationPoller` for long-running operations.
+	ResultType   string
+	ReturnsValue bool
+	HTTPMethod   string
+	// Statements validating the request fields used in the path.
+	PathChecks []string
+	// The Go expression computing the request path.
+	PathExpr string
+	// Statements adding the query parameters to `query`.
+	QueryLines []string
+	// The Go expression for the request body, or `nil`.
+	BodyExpr string
+
+	IsLRO bool
+	// The prefix of the path used to poll the operation, e.g. `/v1/`.
+	OperationPathPrefix string
+	LROResponseType     string
+	LROMetadataType     string
+
+	IsPageable bool
+	// The name of the method returning an iterator over all the items.
+	IteratorName string
+	// The Go type of each item.
+	PageItemType string
+	// The field in the response containing the items.
+	PageItemsField string
+	// Statements setting the page token of `req` to the next page token in
+	// `resp`, returning once there are no more pages.
+	NextPageLines []string
+}
+
+type messageAnnotation struct {
+	Name           string
+	DocLines       []string
+	OmitGeneration bool
+}
+
+type fieldAnnotation struct {
+	Name     string
+	Type     string
+	Tag      string
+	DocLines []string
+}
+
+type enumAnnotation struct {
+	Name     string
+	DocLines []string
+}
+
+type enumValueAnnotation struct {
+	Name     string
+	EnumType string
+	DocLines []string
+}
+
+type annotateModel struct {
+	// The API model we're annotating.
+	model *api.API
+	// Mappings from IDs to types.
+	state *api.APIState
+	// The mapping from protobuf packages to Go import paths.
+	packageMapping map[string]string
+	// The imports needed by the types file.
+	typesImports map[string]bool
+	// The imports needed by the client file.
+	clientImports map[string]bool
+	hasLROs       bool
+}
+
+func newAnnotateModel(model *api.API) *annotateModel {
+	return &annotateModel{
+		model:          model,
+		state:          model.State,
+		packageMapping: map[string]string{},
+		typesImports:   map[string]bool{},
+		clientImports:  map[string]bool{},
+	}
+}
+
+// annotateModel creates a struct used as input for Mustache templates.
+// Fields and methods defined in this struct directly correspond to Mustache
+// tags. For example, the Mustache tag {{#Services}} uses the
+// [Template.Services] field.
+func (annotate *annotateModel) annotateModel(options map[string]string) error {
+	var (
+		packageNameOverride string
+		modulePath          string
+		goVersion           = defaultGoVersion
+		generationYear      string
+		requires            []moduleRequirement
+	)
+	for key, definition := range options {
+		switch {
+		case key == "package-name-override":
+			packageNameOverride = definition
+		case key == "module-path":
+			modulePath = definition
+		case key == "go-version":
+			goVersion = definition
+		case key == "copyright-year":
+			generationYear = definition
+		case strings.HasPrefix(key, "require:"):
+			// "require:cloud.google.com/go/longrunning" = "v0.6.7"
+			requires = append(requires, moduleRequirement{
+				Path:    strings.TrimPrefix(key, "require:"),
+				Version: definition,
+			})
+		case strings.HasPrefix(key, "proto:"):
+			// "proto:google.cloud.location" = "example.com/location"
+			keys := strings.Split(key, ":")
+			if len(keys) != 2 {
+				return fmt.Errorf("key should be in the format proto:<proto-package>, got=%q", key)
+			}
+			annotate.packageMapping[keys[1]] = definition
+		}
+	}
+	sort.Slice(requires, func(i, j int) bool { return requires[i].Path < requires[j].Path })
+	header, err := license.NewHeaderFromOptions(options)
+	if err != nil {
+		return err
+	}
+
+	model := annotate.model
+	for _, e := range model.Enums {
+		annotate.annotateEnum(e)
+	}
+	for _, m := range model.Messages {
+		annotate.annotateMessage(m)
+	}
+	for _, s := range model.Services {
+		annotate.annotateService(s)
+	}
+
+	goPackage := packageName(model, packageNameOverride)
+	if modulePath == "" {
+		modulePath = goPackage
+	}
+	model.Codec = &modelAnnotations{
+		Parent:          model,
+		PackageName:     goPackage,
+		ModulePath:      modulePath,
+		GoVersion:       goVersion,
+		CopyrightYear:   generationYear,
+		CopyrightHolder: header.Holder,
+		BoilerPlate: append(header.Bulk,
+			"",
+			" Code generated by sidekick. DO NOT EDIT."),
+		DocLines:      formatDocComments(model.Description),
+		TypesImports:  sortedImports(annotate.typesImports),
+		ClientImports: sortedImports(annotate.clientImports),
+		Requires:      requires,
+		HasLROs:       annotate.hasLROs,
+	}
+	return nil
+}
+
+func sortedImports(imports map[string]bool) []string {
+	var result []string
+	for imp := range imports {
+		result = append(result, fmt.Sprintf("%q", imp))
+	}
+	sort.Strings(result)
+	return result
+}
+
+func docLines(documentation string, deprecated bool) []string {
+	lines := formatDocComments(documentation)
+	if deprecated {
+		if len(lines) > 0 {
+			lines = append(lines, "//")
+		}
+		lines = append(lines, "// Deprecated: this element is deprecated in the service definition.")
+	}
+	return lines
+}
+
+func (annotate *annotateModel) annotateService(s *api.Service) {
+	name := goName(s.Name)
+	if !strings.HasSuffix(name, "Client") {
+		name += "Client"
+	}
+	methods := language.FilterSlice(s.Methods, func(m *api.Method) bool {
+		return shouldGenerateMethod(m) && annotate.annotateMethod(m, name)
+	})
+	if len(methods) > 0 {
+		// Every method uses a context and builds query parameters.
+		annotate.clientImports["context"] = true
+		annotate.clientImports["net/url"] = true
+	}
+	s.Codec = &serviceAnnotations{
+		Name:            name,
+		ConstructorName: "New" + name,
+		DocLines:        docLines(s.Documentation, s.Deprecated),
+		Methods:         methods,
+		DefaultEndpoint: "https://" + s.DefaultHost,
+	}
+}
+
+// annotateMethod annotates a method, returning false if the method cannot be
+// generated.
+func (annotate *annotateModel) annotateMethod(m *api.Method, clientName string) bool {
+	imports := map[string]bool{}
+	requestType, ok := annotate.messageTypeName(m.InputType, imports)
+	if !ok {
+		slog.Warn("skipping method with unsupported request type", "method", m.ID, "type", m.InputTypeID)
+		return false
+	}
+	isLRO := m.OperationInfo != nil && m.OutputTypeID == operationID
+	var responseType string
+	if !m.ReturnsEmpty && !isLRO {
+		responseType, ok = annotate.messageTypeName(m.OutputType, imports)
+		if !ok {
+			slog.Warn("skipping method with unsupported response type", "method", m.ID, "type", m.OutputTypeID)
+			return false
+		}
+	}
+	errorReturn := "return nil, "
+	if m.ReturnsEmpty && !isLRO {
+		errorReturn = "return "
+	}
+
+	binding := m.PathInfo.Bindings[0]
+	pathChecks, pathExpr, ok := annotate.pathExpression(m, binding.PathTemplate, errorReturn, imports)
+	if !ok {
+		return false
+	}
+	var queryLines []string
+	for _, field := range language.QueryParams(m, binding) {
+		queryLines = annotate.buildQueryLines(queryLines, "req.", "", field, imports)
+	}
+
+	bodyExpr := "nil"
+	switch m.PathInfo.BodyFieldPath {
+	case "":
+	case "*":
+		bodyExpr = "req"
+	default:
+		bodyExpr = "req." + goName(m.PathInfo.BodyFieldPath)
+	}
+
+	ann := &methodAnnotation{
+		Name:         goName(m.Name),
+		DocLines:     docLines(m.Documentation, m.Deprecated),
+		ClientName:   clientName,
+		RequestType:  requestType,
+		ResponseType: responseType,
+		ResultType:   responseType,
+		ReturnsValue: !m.ReturnsEmpty,
+		HTTPMethod:   strings.ToUpper(binding.Verb),
+		PathChecks:   pathChecks,
+		PathExpr:     pathExpr,
+		QueryLines:   queryLines,
+		BodyExpr:     bodyExpr,
+	}
+	if isLRO {
+		annotate.annotateLRO(m, ann, imports)
+	} else {
+		annotate.annotatePagination(m, ann, imports)
+	}
+	m.Codec = ann
+	for imp := range imports {
+		annotate.clientImports[imp] = true
+	}
+	return true
+}
+
+func (annotate *annotateModel) annotateLRO(m *api.Method, ann *methodAnnotation, imports map[string]bool) {
+	resolve := func(id string) string {
+		if id == "" || id == emptyID {
+			return "struct{}"
+		}
+		message, ok := annotate.state.MessageByID[id]
+		if !ok {
+			return "json.RawMessage"
+		}
+		name, ok := annotate.messageTypeName(message, imports)
+		if !ok {
+			return "json.RawMessage"
+		}
+		return name
+	}
+	annotate.hasLROs = true
+	ann.IsLRO = true
+	ann.ReturnsValue = true
+	ann.LROResponseType = resolve(m.OperationInfo.ResponseTypeID)
+	ann.LROMetadataType = resolve(m.OperationInfo.MetadataTypeID)
+	if ann.LROResponseType == "json.RawMessage" || ann.LROMetadataType == "json.RawMessage" {
+		imports["encoding/json"] = true
+	}
+	ann.ResultType = fmt.Sprintf("OperationPoller[%s, %s]", ann.LROResponseType, ann.LROMetadataType)
+	// Operations are polled using the `GetOperation` method of the same API
+	// version, for example `GET /v1/{name=projects/*/locations/*/operations/*}`.
+	ann.OperationPathPrefix = "/"
+	if segments := m.PathInfo.Bindings[0].PathTemplate.Segments; len(segments) > 0 && segments[0].Literal != nil {
+		ann.OperationPathPrefix = "/" + *segments[0].Literal + "/"
+	}
+}
+
+func (annotate *annotateModel) annotatePagination(m *api.Method, ann *methodAnnotation, imports map[string]bool) {
+	if m.Pagination == nil || m.OutputType == nil || m.OutputType.Pagination == nil {
+		return
+	}
+	items := m.OutputType.Pagination.PageableItem
+	next := m.OutputType.Pagination.NextPageToken
+	if items == nil || next == nil || !items.Repeated || items.Map {
+		return
+	}
+	itemType := strings.TrimPrefix(annotate.fieldType(items, imports), "[]")
+
+	nextExpr := "resp." + fieldName(next)
+	var lines []string
+	if next.Optional {
+		lines = append(lines,
+			"token := \"\"",
+			fmt.Sprintf("if %s != nil {", nextExpr),
+			fmt.Sprintf("token = *%s", nextExpr),
+			"}")
+	} else {
+		lines = append(lines, fmt.Sprintf("token := %s", nextExpr))
+	}
+	lines = append(lines, "if token == \"\" {", "return", "}")
+	if m.Pagination.Optional {
+		lines = append(lines, fmt.Sprintf("req.%s = &token", fieldName(m.Pagination)))
+	} else {
+		lines = append(lines, fmt.Sprintf("req.%s = token", fieldName(m.Pagination)))
+	}
+
+	imports["iter"] = true
+	ann.IsPageable = true
+	ann.IteratorName = ann.Name + "Iter"
+	ann.PageItemType = itemType
+	ann.PageItemsField = fieldName(items)
+	ann.NextPageLines = lines
+}
+
+// pathExpression returns the statements validating the path fields, and the
+// Go expression computing the path of the request.
+func (annotate *annotateModel) pathExpression(m *api.Method, template *api.PathTemplate, errorReturn string, imports map[string]bool) ([]string, string, bool) {
+	var checks []string
+	var parts []string
+	literal := ""
+	flush := func() {
+		if literal != "" {
+			parts = append(parts, fmt.Sprintf("%q", literal))
+			literal = ""
+		}
+	}
+	for _, segment := range template.Segments {
+		switch {
+		case segment.Literal != nil:
+			literal += "/" + *segment.Literal
+		case segment.Variable != nil:
+			literal += "/"
+			flush()
+			fieldChecks, expr, ok := annotate.pathVariable(m, segment.Variable.FieldPath, errorReturn, imports)
+			if !ok {
+				return nil, "", false
+			}
+			checks = append(checks, fieldChecks...)
+			parts = append(parts, fmt.Sprintf("escapePath(%s)", expr))
+		}
+	}
+	if template.Verb != nil {
+		literal += ":" + *template.Verb
+	}
+	flush()
+	if len(parts) == 0 {
+		return checks, `"/"`, true
+	}
+	return checks, strings.Join(parts, " + "), true
+}
+
+// pathVariable returns the statements validating that the field at fieldPath
+// is set, and the Go expression with its value as a string.
+func (annotate *annotateModel) pathVariable(m *api.Method, fieldPath []string, errorReturn string, imports map[string]bool) ([]string, string, bool) {
+	var checks []string
+	message := m.InputType
+	expr := "req"
+	fullName := strings.Join(fieldPath, ".")
+	for i, name := range fieldPath {
+		var field *api.Field
+		if message != nil {
+			for _, f := range message.Fields {
+				if f.Name == name {
+					field = f
+					break
+				}
+			}
+		}
+		if field == nil {
+			slog.Warn("skipping method with unknown path field", "method", m.ID, "field", fullName)
+			return nil, "", false
+		}
+		expr += "." + fieldName(field)
+		last := i == len(fieldPath)-1
+		if !last {
+			if field.Typez != api.MESSAGE_TYPE {
+				slog.Warn("skipping method with invalid path field", "method", m.ID, "field", fullName)
+				return nil, "", false
+			}
+			checks = append(checks,
+				fmt.Sprintf("if %s == nil {", expr),
+				fmt.Sprintf("%sfmt.Errorf(\"missing required field %%q\", %q)", errorReturn, fullName),
+				"}")
+			message = annotate.state.MessageByID[field.TypezID]
+			continue
+		}
+		imports["fmt"] = true
+		goType := annotate.fieldType(field, imports)
+		if strings.HasPrefix(goType, "*") {
+			checks = append(checks,
+				fmt.Sprintf("if %s == nil {", expr),
+				fmt.Sprintf("%sfmt.Errorf(\"missing required field %%q\", %q)", errorReturn, fullName),
+				"}")
+			expr = "*" + expr
+			goType = strings.TrimPrefix(goType, "*")
+		}
+		switch {
+		case goType == "string":
+			checks = append(checks,
+				fmt.Sprintf("if %s == \"\" {", expr),
+				fmt.Sprintf("%sfmt.Errorf(\"missing required field %%q\", %q)", errorReturn, fullName),
+				"}")
+		case field.Typez == api.ENUM_TYPE:
+			expr = fmt.Sprintf("string(%s)", expr)
+		default:
+			expr = fmt.Sprintf("fmt.Sprint(%s)", expr)
+		}
+	}
+	return checks, expr, true
+}
+
+// buildQueryLines builds the statements adding a field to the query
+// parameters.
+//
+// Docs on the format are at
+// https://github.com/googleapis/googleapis/blob/master/google/api/http.proto.
+//
+// Generally:
+//   - primitives, lists of primitives and enums are supported
+//   - repeated fields are passed as repeated parameters
+//   - messages need to be unrolled and fields passed individually
+func (annotate *annotateModel) buildQueryLines(result []string, refPrefix, paramPrefix string, field *api.Field, imports map[string]bool) []string {
+	ref := refPrefix + fieldName(field)
+	param := paramPrefix + field.JSONName
+	goType := annotate.fieldType(field, imports)
+
+	if field.Map || strings.HasPrefix(goType, "map[") || goType == "any" || goType == "[]any" || goType == "json.RawMessage" {
+		slog.Warn("unsupported query parameter", "field", field.ID)
+		return result
+	}
+	if field.Repeated {
+		elementType := strings.TrimPrefix(goType, "[]")
+		value, ok := annotate.queryValue("v", elementType, field, imports)
+		if !ok {
+			slog.Warn("unsupported repeated query parameter", "field", field.ID)
+			return result
+		}
+		return append(result,
+			fmt.Sprintf("for _, v := range %s {", ref),
+			fmt.Sprintf("query.Add(%q, %s)", param, value),
+			"}")
+	}
+	if field.Typez == api.MESSAGE_TYPE && strings.HasPrefix(goType, "*") && !strings.HasPrefix(goType, "*"+"[") {
+		if message, ok := annotate.state.MessageByID[field.TypezID]; ok && isLocalType(goType) {
+			result = append(result, fmt.Sprintf("if %s != nil {", ref))
+			for _, f := range message.Fields {
+				result = annotate.buildQueryLines(result, ref+".", param+".", f, imports)
+			}
+			return append(result, "}")
+		}
+	}
+	if strings.HasPrefix(goType, "*") {
+		value, ok := annotate.queryValue("*"+ref, strings.TrimPrefix(goType, "*"), field, imports)
+		if !ok {
+			slog.Warn("unsupported query parameter", "field", field.ID)
+			return result
+		}
+		return append(result,
+			fmt.Sprintf("if %s != nil {", ref),
+			fmt.Sprintf("query.Add(%q, %s)", param, value),
+			"}")
+	}
+	value, ok := annotate.queryValue(ref, goType, field, imports)
+	if !ok {
+		slog.Warn("unsupported query parameter", "field", field.ID)
+		return result
+	}
+	var condition string
+	switch {
+	case goType == "bool":
+		condition = ref
+	case goType == "[]byte":
+		condition = fmt.Sprintf("len(%s) > 0", ref)
+	case goType == "string" || field.Typez == api.ENUM_TYPE:
+		condition = fmt.Sprintf("%s != \"\"", ref)
+	default:
+		condition = fmt.Sprintf("%s != 0", ref)
+	}
+	return append(result,
+		fmt.Sprintf("if %s {", condition),
+		fmt.Sprintf("query.Add(%q, %s)", param, value),
+		"}")
+}
+
+// isLocalType returns true if goType is a (pointer to a) message generated in
+// this package.
+func isLocalType(goType string) bool {
+	return !strings.Contains(goType, ".")
+}
+
+// queryValue returns a Go expression converting expr, of type goType, to a
+// query parameter value.
+func (annotate *annotateModel) queryValue(expr, goType string, field *api.Field, imports map[string]bool) (string, bool) {
+	switch {
+	case goType == "string":
+		return expr, true
+	case goType == "json.Number":
+		return fmt.Sprintf("%s.String()", expr), true
+	case goType == "[]byte":
+		imports["encoding/base64"] = true
+		return fmt.Sprintf("base64.StdEncoding.EncodeToString(%s)", expr), true
+	case field.Typez == api.ENUM_TYPE:
+		return fmt.Sprintf("string(%s)", expr), true
+	case strings.HasPrefix(goType, "*") || strings.HasPrefix(goType, "[]") || strings.HasPrefix(goType, "map[") || strings.Contains(goType, "{"):
+		return "", false
+	case field.Typez == api.MESSAGE_TYPE && !isWrapperType(field.TypezID):
+		return "", false
+	default:
+		imports["fmt"] = true
+		return fmt.Sprintf("fmt.Sprint(%s)", expr), true
+	}
+}
+
+func isWrapperType(id string) bool {
+	return strings.HasPrefix(id, ".google.protobuf.") && strings.HasSuffix(id, "Value") &&
+		id != ".google.protobuf.Value" && id != ".google.protobuf.ListValue"
+}
+
+func (annotate *annotateModel) annotateMessage(m *api.Message) {
+	for _, f := range m.Fields {
+		annotate.annotateField(f)
+	}
+	for _, e := range m.Enums {
+		annotate.annotateEnum(e)
+	}
+	for _, child := range m.Messages {
+		annotate.annotateMessage(child)
+	}
+	m.Codec = &messageAnnotation{
+		Name:           messageName(m),
+		DocLines:       docLines(m.Documentation, m.Deprecated),
+		OmitGeneration: m.IsMap,
+	}
+}
+
+func (annotate *annotateModel) annotateField(field *api.Field) {
+	goType := annotate.fieldType(field, annotate.typesImports)
+	options := ",omitempty"
+	if is64BitInteger(field.Typez) && !field.Repeated && !field.Map ||
+		field.TypezID == ".google.protobuf.Int64Value" || field.TypezID == ".google.protobuf.UInt64Value" {
+		// The JSON encoding of 64-bit integers is a string.
+		options += ",string"
+	}
+	field.Codec = &fieldAnnotation{
+		Name:     fieldName(field),
+		Type:     goType,
+		Tag:      fmt.Sprintf("`json:\"%s%s\"`", field.JSONName, options),
+		DocLines: docLines(field.Documentation, field.Deprecated),
+	}
+}
+
+func (annotate *annotateModel) annotateEnum(e *api.Enum) {
+	for _, ev := range e.Values {
+		ev.Codec = &enumValueAnnotation{
+			Name:     enumValueName(e, ev),
+			EnumType: enumName(e),
+			DocLines: docLines(ev.Documentation, ev.Deprecated),
+		}
+	}
+	e.Codec = &enumAnnotation{
+		Name:     enumName(e),
+		DocLines: docLines(e.Documentation, e.Deprecated),
+	}
+}
+
+func is64BitInteger(typez api.Typez) bool {
+	switch typez {
+	case api.INT64_TYPE, api.UINT64_TYPE, api.SINT64_TYPE, api.FIXED64_TYPE, api.SFIXED64_TYPE:
+		return true
+	}
+	return false
+}
+
+// fieldType returns the Go type of a field, recording any imports it needs.
+func (annotate *annotateModel) fieldType(f *api.Field, imports map[string]bool) string {
+	if f.Map {
+		message, ok := annotate.state.MessageByID[f.TypezID]
+		if !ok || len(message.Fields) != 2 {
+			slog.Error("unable to lookup map type", "id", f.TypezID)
+			return "map[string]any"
+		}
+		key := annotate.elementType(message.Fields[0], imports)
+		value := annotate.elementType(message.Fields[1], imports)
+		if is64BitInteger(message.Fields[1].Typez) {
+			// encoding/json does not support the `string` option for map
+			// values.
+			imports["encoding/json"] = true
+			value = "json.Number"
+		}
+		return "map[" + key + "]" + value
+	}
+	if f.Repeated {
+		return "[]" + annotate.elementType(f, imports)
+	}
+	goType := annotate.elementType(f, imports)
+	if (f.Optional || f.IsOneOf) && f.Typez != api.MESSAGE_TYPE && f.Typez != api.BYTES_TYPE {
+		return "*" + goType
+	}
+	return goType
+}
+
+// elementType returns the Go type of a single element of a field.
+func (annotate *annotateModel) elementType(f *api.Field, imports map[string]bool) string {
+	switch f.Typez {
+	case api.BOOL_TYPE:
+		return "bool"
+	case api.INT32_TYPE, api.SINT32_TYPE, api.SFIXED32_TYPE:
+		return "int32"
+	case api.UINT32_TYPE, api.FIXED32_TYPE:
+		return "uint32"
+	case api.INT64_TYPE, api.SINT64_TYPE, api.SFIXED64_TYPE:
+		if f.Repeated || f.Map {
+			// The JSON encoding of 64-bit integers is a string, and
+			// encoding/json does not support the `string` option for slices
+			// and maps.
+			imports["encoding/json"] = true
+			return "json.Number"
+		}
+		return "int64"
+	case api.UINT64_TYPE, api.FIXED64_TYPE:
+		if f.Repeated || f.Map {
+			imports["encoding/json"] = true
+			return "json.Number"
+		}
+		return "uint64"
+	case api.FLOAT_TYPE:
+		return "float32"
+	case api.DOUBLE_TYPE:
+		return "float64"
+	case api.STRING_TYPE:
+		return "string"
+	case api.BYTES_TYPE:
+		return "[]byte"
+	case api.ENUM_TYPE:
+		e, ok := annotate.state.EnumByID[f.TypezID]
+		if !ok {
+			slog.Error("unable to lookup type", "id", f.TypezID)
+			return "string"
+		}
+		if e.Package != annotate.model.PackageName {
+			if ref, ok := annotate.externalRef(e.Package, enumName(e), imports); ok {
+				return ref
+			}
+			return "string"
+		}
+		return enumName(e)
+	case api.MESSAGE_TYPE:
+		if wkt, ok := wellKnownTypes[f.TypezID]; ok {
+			return wkt
+		}
+		message, ok := annotate.state.MessageByID[f.TypezID]
+		if !ok {
+			slog.Error("unable to lookup type", "id", f.TypezID)
+			imports["encoding/json"] = true
+			return "json.RawMessage"
+		}
+		name, ok := annotate.messageTypeName(message, imports)
+		if !ok {
+			imports["encoding/json"] = true
+			return "json.RawMessage"
+		}
+		return "*" + name
+	default:
+		slog.Error("unhandled fieldType", "type", f.Typez, "id", f.TypezID)
+		return "any"
+	}
+}
+
+// messageTypeName returns the name of the Go type for a message, qualified
+// with the package name if needed. It returns false if the message is defined
+// in a package without a Go mapping.
+func (annotate *annotateModel) messageTypeName(message *api.Message, imports map[string]bool) (string, bool) {
+	if message == nil {
+		return "", false
+	}
+	if message.Package == annotate.model.PackageName || message.Package == "" {
+		return messageName(message), true
+	}
+	return annotate.externalRef(message.Package, messageName(message), imports)
+}
+
+func (annotate *annotateModel) externalRef(protoPackage, name string, imports map[string]bool) (string, bool) {
+	importPath, ok := annotate.packageMapping[protoPackage]
+	if !ok {
+		return "", false
+	}
+	imports[importPath] = true
+	return path.Base(importPath) + "." + name, true
+}
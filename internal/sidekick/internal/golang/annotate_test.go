@@ -0,0 +1,532 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package golang
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/googleapis/librarian/internal/sidekick/internal/api"
+)
+
+func TestAnnotateModel(t *testing.T) {
+	model := api.NewTestAPI([]*api.Message{}, []*api.Enum{}, []*api.Service{})
+	model.PackageName = "google.cloud.test.v1"
+	model.Description = "A test API."
+	annotate := newAnnotateModel(model)
+	if err := annotate.annotateModel(map[string]string{}); err != nil {
+		t.Fatal(err)
+	}
+	codec := model.Codec.(*modelAnnotations)
+	if diff := cmp.Diff("test", codec.PackageName); diff != "" {
+		t.Errorf("mismatch in Codec.PackageName (-want, +got)\n:%s", diff)
+	}
+	if diff := cmp.Diff("test", codec.ModulePath); diff != "" {
+		t.Errorf("mismatch in Codec.ModulePath (-want, +got)\n:%s", diff)
+	}
+	if diff := cmp.Diff(defaultGoVersion, codec.GoVersion); diff != "" {
+		t.Errorf("mismatch in Codec.GoVersion (-want, +got)\n:%s", diff)
+	}
+	if diff := cmp.Diff([]string{"// A test API."}, codec.DocLines); diff != "" {
+		t.Errorf("mismatch in Codec.DocLines (-want, +got)\n:%s", diff)
+	}
+	if codec.HasServices() || codec.HasTypes() || codec.HasLROs {
+		t.Errorf("expected an empty model, got %+v", codec)
+	}
+}
+
+func TestAnnotateModel_Options(t *testing.T) {
+	model := api.NewTestAPI([]*api.Message{}, []*api.Enum{}, []*api.Service{})
+	annotate := newAnnotateModel(model)
+	err := annotate.annotateModel(map[string]string{
+		"package-name-override":              "sm",
+		"module-path":                        "example.com/sm",
+		"go-version":                         "1.24",
+		"copyright-year":                     "2038",
+		"require:example.com/zeta":           "v0.2.0",
+		"require:example.com/alpha":          "v1.0.0",
+		"proto:google.cloud.location":        "example.com/location",
+		"not-a-go-option-but-still-accepted": "true",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	codec := model.Codec.(*modelAnnotations)
+	want := &modelAnnotations{
+		PackageName:     "sm",
+		ModulePath:      "example.com/sm",
+		GoVersion:       "1.24",
+		CopyrightYear:   "2038",
+		CopyrightHolder: "Google LLC",
+		Requires: []moduleRequirement{
+			{Path: "example.com/alpha", Version: "v1.0.0"},
+			{Path: "example.com/zeta", Version: "v0.2.0"},
+		},
+	}
+	if diff := cmp.Diff(want, codec, cmpopts.IgnoreFields(modelAnnotations{}, "Parent", "BoilerPlate"), cmpopts.EquateEmpty()); diff != "" {
+		t.Errorf("mismatch in Codec (-want, +got)\n:%s", diff)
+	}
+	if diff := cmp.Diff(map[string]string{"google.cloud.location": "example.com/location"}, annotate.packageMapping); diff != "" {
+		t.Errorf("mismatch in packageMapping (-want, +got)\n:%s", diff)
+	}
+}
+
+func TestFieldAnnotations(t *testing.T) {
+	enum := &api.Enum{Name: "State", ID: ".test.State", Package: "test"}
+	child := &api.Message{Name: "Child", ID: ".test.Child", Package: "test"}
+	external := &api.Message{Name: "Location", ID: ".google.cloud.location.Location", Package: "google.cloud.location"}
+	mapEntry := &api.Message{
+		Name:    "CountsEntry",
+		ID:      ".test.Parent.CountsEntry",
+		Package: "test",
+		IsMap:   true,
+		Fields: []*api.Field{
+			{Name: "key", JSONName: "key", Typez: api.STRING_TYPE},
+			{Name: "value", JSONName: "value", Typez: api.INT64_TYPE},
+		},
+	}
+	message := &api.Message{
+		Name:    "Parent",
+		ID:      ".test.Parent",
+		Package: "test",
+		Fields: []*api.Field{
+			{Name: "name", JSONName: "name", Typez: api.STRING_TYPE},
+			{Name: "display_name", JSONName: "displayName", Typez: api.STRING_TYPE, Optional: true},
+			{Name: "size", JSONName: "size", Typez: api.INT64_TYPE},
+			{Name: "ids", JSONName: "ids", Typez: api.UINT64_TYPE, Repeated: true},
+			{Name: "counts", JSONName: "counts", Typez: api.MESSAGE_TYPE, TypezID: mapEntry.ID, Map: true},
+			{Name: "state", JSONName: "state", Typez: api.ENUM_TYPE, TypezID: enum.ID},
+			{Name: "child", JSONName: "child", Typez: api.MESSAGE_TYPE, TypezID: child.ID, Optional: true},
+			{Name: "location", JSONName: "location", Typez: api.MESSAGE_TYPE, TypezID: external.ID, Optional: true},
+			{Name: "update_time", JSONName: "updateTime", Typez: api.MESSAGE_TYPE, TypezID: ".google.protobuf.Timestamp", Optional: true},
+			{Name: "data", JSONName: "data", Typez: api.BYTES_TYPE, IsOneOf: true},
+			{Name: "old", JSONName: "old", Typez: api.BOOL_TYPE, Deprecated: true, Documentation: "Do not use."},
+		},
+	}
+	model := api.NewTestAPI([]*api.Message{message, child, external, mapEntry}, []*api.Enum{enum}, []*api.Service{})
+	model.PackageName = "test"
+	annotate := newAnnotateModel(model)
+	if err := annotate.annotateModel(map[string]string{}); err != nil {
+		t.Fatal(err)
+	}
+
+	want := []*fieldAnnotation{
+		{Name: "Name", Type: "string", Tag: "`json:\"name,omitempty\"`"},
+		{Name: "DisplayName", Type: "*string", Tag: "`json:\"displayName,omitempty\"`"},
+		{Name: "Size", Type: "int64", Tag: "`json:\"size,omitempty,string\"`"},
+		{Name: "Ids", Type: "[]json.Number", Tag: "`json:\"ids,omitempty\"`"},
+		{Name: "Counts", Type: "map[string]json.Number", Tag: "`json:\"counts,omitempty\"`"},
+		{Name: "State", Type: "State", Tag: "`json:\"state,omitempty\"`"},
+		{Name: "Child", Type: "*Child", Tag: "`json:\"child,omitempty\"`"},
+		{Name: "Location", Type: "json.RawMessage", Tag: "`json:\"location,omitempty\"`"},
+		{Name: "UpdateTime", Type: "string", Tag: "`json:\"updateTime,omitempty\"`"},
+		{Name: "Data", Type: "[]byte", Tag: "`json:\"data,omitempty\"`"},
+		{
+			Name:     "Old",
+			Type:     "bool",
+			Tag:      "`json:\"old,omitempty\"`",
+			DocLines: []string{"// Do not use.", "//", "// Deprecated: this element is deprecated in the service definition."},
+		},
+	}
+	var got []*fieldAnnotation
+	for _, f := range message.Fields {
+		got = append(got, f.Codec.(*fieldAnnotation))
+	}
+	if diff := cmp.Diff(want, got, cmpopts.EquateEmpty()); diff != "" {
+		t.Errorf("mismatch in field annotations (-want, +got)\n:%s", diff)
+	}
+	codec := model.Codec.(*modelAnnotations)
+	if diff := cmp.Diff([]string{`"encoding/json"`}, codec.TypesImports); diff != "" {
+		t.Errorf("mismatch in Codec.TypesImports (-want, +got)\n:%s", diff)
+	}
+	if !mapEntry.Codec.(*messageAnnotation).OmitGeneration {
+		t.Errorf("map entries should not be generated")
+	}
+}
+
+func TestFieldAnnotations_MappedPackage(t *testing.T) {
+	external := &api.Message{Name: "Location", ID: ".google.cloud.location.Location", Package: "google.cloud.location"}
+	message := &api.Message{
+		Name:    "Parent",
+		ID:      ".test.Parent",
+		Package: "test",
+		Fields: []*api.Field{
+			{Name: "location", JSONName: "location", Typez: api.MESSAGE_TYPE, TypezID: external.ID, Optional: true},
+		},
+	}
+	model := api.NewTestAPI([]*api.Message{message, external}, []*api.Enum{}, []*api.Service{})
+	model.PackageName = "test"
+	annotate := newAnnotateModel(model)
+	if err := annotate.annotateModel(map[string]string{"proto:google.cloud.location": "example.com/location"}); err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff("*location.Location", message.Fields[0].Codec.(*fieldAnnotation).Type); diff != "" {
+		t.Errorf("mismatch in field type (-want, +got)\n:%s", diff)
+	}
+	codec := model.Codec.(*modelAnnotations)
+	if diff := cmp.Diff([]string{`"example.com/location"`}, codec.TypesImports); diff != "" {
+		t.Errorf("mismatch in Codec.TypesImports (-want, +got)\n:%s", diff)
+	}
+}
+
+func TestEnumAnnotations(t *testing.T) {
+	value := &api.EnumValue{Name: "ENABLED", Documentation: "The secret is enabled."}
+	enum := &api.Enum{Name: "State", ID: ".test.State", Package: "test", Values: []*api.EnumValue{value}}
+	model := api.NewTestAPI([]*api.Message{}, []*api.Enum{enum}, []*api.Service{})
+	annotate := newAnnotateModel(model)
+	if err := annotate.annotateModel(map[string]string{}); err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(&enumAnnotation{Name: "State"}, enum.Codec, cmpopts.EquateEmpty()); diff != "" {
+		t.Errorf("mismatch in enum annotations (-want, +got)\n:%s", diff)
+	}
+	wantValue := &enumValueAnnotation{
+		Name:     "State_ENABLED",
+		EnumType: "State",
+		DocLines: []string{"// The secret is enabled."},
+	}
+	if diff := cmp.Diff(wantValue, value.Codec); diff != "" {
+		t.Errorf("mismatch in enum value annotations (-want, +got)\n:%s", diff)
+	}
+}
+
+func newTestService() (*api.API, map[string]*api.Method) {
+	enum := &api.Enum{Name: "View", ID: ".test.View", Package: "test"}
+	filter := &api.Message{
+		Name:    "Filter",
+		ID:      ".test.Filter",
+		Package: "test",
+		Fields: []*api.Field{
+			{Name: "min_size", JSONName: "minSize", Typez: api.INT32_TYPE},
+		},
+	}
+	secret := &api.Message{
+		Name:    "Secret",
+		ID:      ".test.Secret",
+		Package: "test",
+		Fields: []*api.Field{
+			{Name: "name", JSONName: "name", Typez: api.STRING_TYPE},
+		},
+	}
+	pageToken := &api.Field{Name: "page_token", JSONName: "pageToken", Typez: api.STRING_TYPE}
+	listRequest := &api.Message{
+		Name:    "ListSecretsRequest",
+		ID:      ".test.ListSecretsRequest",
+		Package: "test",
+		Fields: []*api.Field{
+			{Name: "parent", JSONName: "parent", Typez: api.STRING_TYPE},
+			{Name: "page_size", JSONName: "pageSize", Typez: api.INT32_TYPE},
+			pageToken,
+			{Name: "view", JSONName: "view", Typez: api.ENUM_TYPE, TypezID: enum.ID},
+			{Name: "tags", JSONName: "tags", Typez: api.STRING_TYPE, Repeated: true},
+			{Name: "filter", JSONName: "filter", Typez: api.MESSAGE_TYPE, TypezID: filter.ID, Optional: true},
+		},
+	}
+	secrets := &api.Field{Name: "secrets", JSONName: "secrets", Typez: api.MESSAGE_TYPE, TypezID: secret.ID, Repeated: true}
+	nextPageToken := &api.Field{Name: "next_page_token", JSONName: "nextPageToken", Typez: api.STRING_TYPE}
+	listResponse := &api.Message{
+		Name:    "ListSecretsResponse",
+		ID:      ".test.ListSecretsResponse",
+		Package: "test",
+		Fields:  []*api.Field{secrets, nextPageToken},
+		Pagination: &api.PaginationInfo{
+			NextPageToken: nextPageToken,
+			PageableItem:  secrets,
+		},
+	}
+	getRequest := &api.Message{
+		Name:    "GetSecretRequest",
+		ID:      ".test.GetSecretRequest",
+		Package: "test",
+		Fields: []*api.Field{
+			{Name: "name", JSONName: "name", Typez: api.STRING_TYPE},
+		},
+	}
+	createRequest := &api.Message{
+		Name:    "CreateSecretRequest",
+		ID:      ".test.CreateSecretRequest",
+		Package: "test",
+		Fields: []*api.Field{
+			{Name: "parent", JSONName: "parent", Typez: api.STRING_TYPE},
+			{Name: "secret", JSONName: "secret", Typez: api.MESSAGE_TYPE, TypezID: secret.ID, Optional: true},
+		},
+	}
+	operation := &api.Message{Name: "Operation", ID: operationID, Package: "google.longrunning"}
+
+	list := &api.Method{
+		Name:         "ListSecrets",
+		ID:           ".test.Service.ListSecrets",
+		InputTypeID:  listRequest.ID,
+		InputType:    listRequest,
+		OutputTypeID: listResponse.ID,
+		OutputType:   listResponse,
+		Pagination:   pageToken,
+		PathInfo: &api.PathInfo{
+			Bindings: []*api.PathBinding{{
+				Verb: "GET",
+				PathTemplate: api.NewPathTemplate().
+					WithLiteral("v1").
+					WithVariableNamed("parent").
+					WithLiteral("secrets"),
+				QueryParameters: map[string]bool{"page_size": true, "page_token": true, "view": true, "tags": true, "filter": true},
+			}},
+		},
+	}
+	get := &api.Method{
+		Name:         "GetSecret",
+		ID:           ".test.Service.GetSecret",
+		InputTypeID:  getRequest.ID,
+		InputType:    getRequest,
+		OutputTypeID: secret.ID,
+		OutputType:   secret,
+		PathInfo: &api.PathInfo{
+			Bindings: []*api.PathBinding{{
+				Verb:         "GET",
+				PathTemplate: api.NewPathTemplate().WithLiteral("v1").WithVariableNamed("name"),
+			}},
+		},
+	}
+	deleteMethod := &api.Method{
+		Name:         "DeleteSecret",
+		ID:           ".test.Service.DeleteSecret",
+		InputTypeID:  getRequest.ID,
+		InputType:    getRequest,
+		OutputTypeID: emptyID,
+		ReturnsEmpty: true,
+		PathInfo: &api.PathInfo{
+			Bindings: []*api.PathBinding{{
+				Verb:         "DELETE",
+				PathTemplate: api.NewPathTemplate().WithLiteral("v1").WithVariableNamed("name"),
+			}},
+		},
+	}
+	create := &api.Method{
+		Name:         "CreateSecret",
+		ID:           ".test.Service.CreateSecret",
+		InputTypeID:  createRequest.ID,
+		InputType:    createRequest,
+		OutputTypeID: operationID,
+		OutputType:   operation,
+		OperationInfo: &api.OperationInfo{
+			ResponseTypeID: secret.ID,
+			MetadataTypeID: emptyID,
+		},
+		PathInfo: &api.PathInfo{
+			Bindings: []*api.PathBinding{{
+				Verb:         "POST",
+				PathTemplate: api.NewPathTemplate().WithLiteral("v1").WithVariableNamed("parent").WithLiteral("secrets"),
+			}},
+			BodyFieldPath: "secret",
+		},
+	}
+	service := &api.Service{
+		Name:        "SecretService",
+		ID:          ".test.SecretService",
+		Package:     "test",
+		DefaultHost: "test.googleapis.com",
+		Methods:     []*api.Method{list, get, deleteMethod, create},
+	}
+	model := api.NewTestAPI(
+		[]*api.Message{filter, secret, listRequest, listResponse, getRequest, createRequest, operation},
+		[]*api.Enum{enum},
+		[]*api.Service{service})
+	model.PackageName = "test"
+	return model, map[string]*api.Method{
+		"list":   list,
+		"get":    get,
+		"delete": deleteMethod,
+		"create": create,
+	}
+}
+
+func TestServiceAnnotations(t *testing.T) {
+	model, _ := newTestService()
+	annotate := newAnnotateModel(model)
+	if err := annotate.annotateModel(map[string]string{}); err != nil {
+		t.Fatal(err)
+	}
+	service := model.Services[0]
+	got := service.Codec.(*serviceAnnotations)
+	want := &serviceAnnotations{
+		Name:            "SecretServiceClient",
+		ConstructorName: "NewSecretServiceClient",
+		DefaultEndpoint: "https://test.googleapis.com",
+		Methods:         service.Methods,
+	}
+	if diff := cmp.Diff(want, got, cmpopts.IgnoreFields(serviceAnnotations{}, "Methods"), cmpopts.EquateEmpty()); diff != "" {
+		t.Errorf("mismatch in service annotations (-want, +got)\n:%s", diff)
+	}
+	if len(got.Methods) != 4 {
+		t.Errorf("expected 4 methods, got %d", len(got.Methods))
+	}
+	codec := model.Codec.(*modelAnnotations)
+	if !codec.HasLROs {
+		t.Errorf("expected HasLROs to be true")
+	}
+	wantImports := []string{`"context"`, `"fmt"`, `"iter"`, `"net/url"`}
+	if diff := cmp.Diff(wantImports, codec.ClientImports); diff != "" {
+		t.Errorf("mismatch in Codec.ClientImports (-want, +got)\n:%s", diff)
+	}
+}
+
+func TestMethodAnnotations(t *testing.T) {
+	model, methods := newTestService()
+	annotate := newAnnotateModel(model)
+	if err := annotate.annotateModel(map[string]string{}); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, test := range []struct {
+		method string
+		want   *methodAnnotation
+	}{
+		{
+			method: "list",
+			want: &methodAnnotation{
+				Name:         "ListSecrets",
+				ClientName:   "SecretServiceClient",
+				RequestType:  "ListSecretsRequest",
+				ResponseType: "ListSecretsResponse",
+				ResultType:   "ListSecretsResponse",
+				ReturnsValue: true,
+				HTTPMethod:   "GET",
+				PathChecks: []string{
+					`if req.Parent == "" {`,
+					`return nil, fmt.Errorf("missing required field %q", "parent")`,
+					`}`,
+				},
+				PathExpr: `"/v1/" + escapePath(req.Parent) + "/secrets"`,
+				QueryLines: []string{
+					`if req.PageSize != 0 {`,
+					`query.Add("pageSize", fmt.Sprint(req.PageSize))`,
+					`}`,
+					`if req.PageToken != "" {`,
+					`query.Add("pageToken", req.PageToken)`,
+					`}`,
+					`if req.View != "" {`,
+					`query.Add("view", string(req.View))`,
+					`}`,
+					`for _, v := range req.Tags {`,
+					`query.Add("tags", v)`,
+					`}`,
+					`if req.Filter != nil {`,
+					`if req.Filter.MinSize != 0 {`,
+					`query.Add("filter.minSize", fmt.Sprint(req.Filter.MinSize))`,
+					`}`,
+					`}`,
+				},
+				BodyExpr:       "nil",
+				IsPageable:     true,
+				IteratorName:   "ListSecretsIter",
+				PageItemType:   "*Secret",
+				PageItemsField: "Secrets",
+				NextPageLines: []string{
+					`token := resp.NextPageToken`,
+					`if token == "" {`,
+					`return`,
+					`}`,
+					`req.PageToken = token`,
+				},
+			},
+		},
+		{
+			method: "delete",
+			want: &methodAnnotation{
+				Name:        "DeleteSecret",
+				ClientName:  "SecretServiceClient",
+				RequestType: "GetSecretRequest",
+				HTTPMethod:  "DELETE",
+				PathChecks: []string{
+					`if req.Name == "" {`,
+					`return fmt.Errorf("missing required field %q", "name")`,
+					`}`,
+				},
+				PathExpr: `"/v1/" + escapePath(req.Name)`,
+				BodyExpr: "nil",
+			},
+		},
+		{
+			method: "create",
+			want: &methodAnnotation{
+				Name:         "CreateSecret",
+				ClientName:   "SecretServiceClient",
+				RequestType:  "CreateSecretRequest",
+				ResultType:   "OperationPoller[Secret, struct{}]",
+				ReturnsValue: true,
+				HTTPMethod:   "POST",
+				PathChecks: []string{
+					`if req.Parent == "" {`,
+					`return nil, fmt.Errorf("missing required field %q", "parent")`,
+					`}`,
+				},
+				PathExpr:            `"/v1/" + escapePath(req.Parent) + "/secrets"`,
+				BodyExpr:            "req.Secret",
+				IsLRO:               true,
+				OperationPathPrefix: "/v1/",
+				LROResponseType:     "Secret",
+				LROMetadataType:     "struct{}",
+			},
+		},
+	} {
+		t.Run(test.method, func(t *testing.T) {
+			got := methods[test.method].Codec.(*methodAnnotation)
+			if diff := cmp.Diff(test.want, got, cmpopts.EquateEmpty()); diff != "" {
+				t.Errorf("mismatch in method annotations (-want, +got)\n:%s", diff)
+			}
+		})
+	}
+}
+
+func TestMethodAnnotations_SkipUnmappedTypes(t *testing.T) {
+	external := &api.Message{Name: "Location", ID: ".google.cloud.location.Location", Package: "google.cloud.location"}
+	request := &api.Message{Name: "Request", ID: ".test.Request", Package: "test"}
+	method := &api.Method{
+		Name:         "GetLocation",
+		ID:           ".test.Service.GetLocation",
+		InputTypeID:  request.ID,
+		InputType:    request,
+		OutputTypeID: external.ID,
+		OutputType:   external,
+		PathInfo: &api.PathInfo{
+			Bindings: []*api.PathBinding{{
+				Verb:         "GET",
+				PathTemplate: api.NewPathTemplate().WithLiteral("v1").WithLiteral("location"),
+			}},
+		},
+	}
+	service := &api.Service{Name: "Service", ID: ".test.Service", Package: "test", Methods: []*api.Method{method}}
+	model := api.NewTestAPI([]*api.Message{request, external}, []*api.Enum{}, []*api.Service{service})
+	model.PackageName = "test"
+
+	annotate := newAnnotateModel(model)
+	if err := annotate.annotateModel(map[string]string{}); err != nil {
+		t.Fatal(err)
+	}
+	if got := service.Codec.(*serviceAnnotations).Methods; len(got) != 0 {
+		t.Errorf("expected the method with an unmapped response to be skipped, got %v", got)
+	}
+
+	annotate = newAnnotateModel(model)
+	if err := annotate.annotateModel(map[string]string{"proto:google.cloud.location": "example.com/location"}); err != nil {
+		t.Fatal(err)
+	}
+	if got := service.Codec.(*serviceAnnotations).Methods; len(got) != 1 {
+		t.Fatalf("expected the method with a mapped response to be generated, got %v", got)
+	}
+	if diff := cmp.Diff("location.Location", method.Codec.(*methodAnnotation).ResponseType); diff != "" {
+		t.Errorf("mismatch in ResponseType (-want, +got)\n:%s", diff)
+	}
+}
@@ -0,0 +1,73 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package golang
+
+import (
+	"embed"
+	"path/filepath"
+
+	"github.com/googleapis/librarian/internal/sidekick/internal/api"
+	"github.com/googleapis/librarian/internal/sidekick/internal/config"
+	"github.com/googleapis/librarian/internal/sidekick/internal/language"
+)
+
+//go:embed templates
+var goTemplates embed.FS
+
+// Generate generates Go code from the model.
+func Generate(model *api.API, outdir string, config *config.Config) error {
+	annotate := newAnnotateModel(model)
+	if err := annotate.annotateModel(config.Codec); err != nil {
+		return err
+	}
+
+	provider := templatesProvider()
+	files := generatedFiles(model)
+	err := language.GenerateFromModelWithOverrides(outdir, model, provider, files, config.Codec)
+	if err == nil {
+		// Check if we're configured to skip formatting.
+		skipFormat := config.Codec["skip-format"]
+		if skipFormat != "true" {
+			err = language.FormatGoFiles(outdir, files)
+		}
+	}
+	return err
+}
+
+func templatesProvider() language.TemplateProvider {
+	return func(name string) (string, error) {
+		name = filepath.ToSlash(name)
+		contents, err := goTemplates.ReadFile(name)
+		if err != nil {
+			return "", err
+		}
+		return string(contents), nil
+	}
+}
+
+func generatedFiles(model *api.API) []language.GeneratedFile {
+	codec := model.Codec.(*modelAnnotations)
+	files := language.WalkTemplatesDir(goTemplates, "templates")
+	return language.FilterSlice(files, func(f language.GeneratedFile) bool {
+		switch filepath.Base(f.OutputPath) {
+		case "client.go", "transport.go":
+			return codec.HasServices()
+		case "types.go":
+			return codec.HasTypes()
+		default:
+			return true
+		}
+	})
+}
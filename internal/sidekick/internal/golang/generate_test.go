@@ -0,0 +1,134 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package golang
+
+import (
+	"io/fs"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/googleapis/librarian/internal/sidekick/internal/api"
+	"github.com/googleapis/librarian/internal/sidekick/internal/config"
+	"github.com/googleapis/librarian/internal/sidekick/internal/parser"
+)
+
+var (
+	testdataDir, _ = filepath.Abs("../../testdata")
+)
+
+func TestFromOpenAPI(t *testing.T) {
+	outDir := t.TempDir()
+
+	cfg := &config.Config{
+		General: config.GeneralConfig{
+			SpecificationFormat: "openapi",
+			ServiceConfig:       path.Join(testdataDir, "googleapis/google/cloud/secretmanager/v1/secretmanager_v1.yaml"),
+			SpecificationSource: path.Join(testdataDir, "openapi/secretmanager_openapi_v1.json"),
+		},
+		Codec: map[string]string{
+			"copyright-year": "2025",
+			"module-path":    "example.com/secretmanager",
+		},
+	}
+	model, err := parser.CreateModel(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := Generate(model, outDir, cfg); err != nil {
+		t.Fatal(err)
+	}
+	for _, expected := range []string{"go.mod", "README.md", "doc.go", "types.go", "client.go", "transport.go"} {
+		filename := path.Join(outDir, expected)
+		stat, err := os.Stat(filename)
+		if os.IsNotExist(err) {
+			t.Errorf("missing %s: %s", filename, err)
+			continue
+		}
+		if stat.Mode().Perm()|0666 != 0666 {
+			t.Errorf("generated files should not be executable %s: %o", filename, stat.Mode())
+		}
+	}
+
+	// The generated code only depends on the standard library, so it can be
+	// verified without network access.
+	goTool, err := exec.LookPath("go")
+	if err != nil {
+		t.Skip("skipping build of generated code because go is not installed")
+	}
+	cmd := exec.Command(goTool, "vet", "./...")
+	cmd.Dir = outDir
+	cmd.Env = append(os.Environ(), "GOFLAGS=-mod=mod", "GOWORK=off")
+	if output, err := cmd.CombinedOutput(); err != nil {
+		t.Errorf("go vet failed on the generated code: %v\n%s", err, output)
+	}
+}
+
+func TestGeneratedFiles(t *testing.T) {
+	for _, test := range []struct {
+		name     string
+		services []*api.Service
+		want     []string
+	}{
+		{
+			name: "without services",
+			want: []string{"README.md", "doc.go", "go.mod", "types.go"},
+		},
+		{
+			name:     "with services",
+			services: []*api.Service{{Name: "Service", ID: ".test.Service"}},
+			want:     []string{"README.md", "client.go", "doc.go", "go.mod", "transport.go", "types.go"},
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			message := &api.Message{Name: "Message", ID: ".test.Message", Package: "test"}
+			model := api.NewTestAPI([]*api.Message{message}, []*api.Enum{}, test.services)
+			annotate := newAnnotateModel(model)
+			if err := annotate.annotateModel(map[string]string{}); err != nil {
+				t.Fatal(err)
+			}
+			var got []string
+			for _, f := range generatedFiles(model) {
+				got = append(got, filepath.Base(f.OutputPath))
+			}
+			if diff := cmp.Diff(test.want, got); diff != "" {
+				t.Errorf("generatedFiles() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestTemplatesAvailable(t *testing.T) {
+	var count = 0
+	fs.WalkDir(goTemplates, "templates", func(path string, d fs.DirEntry, err error) error {
+		if filepath.Ext(path) != ".mustache" {
+			return nil
+		}
+		if strings.Count(d.Name(), ".") == 1 {
+			// skip partials
+			return nil
+		}
+		count++
+		return nil
+	})
+
+	if count == 0 {
+		t.Errorf("no go templates found")
+	}
+}
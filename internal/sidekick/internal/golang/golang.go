@@ -0,0 +1,224 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package golang
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+
+	"github.com/googleapis/librarian/internal/sidekick/internal/api"
+)
+
+// nestedChar is used to concatenate a message and a child message or enum,
+// following the conventions of protoc-gen-go.
+var nestedChar = "_"
+
+// initialisms are the words which Go naming conventions spell in all caps.
+//
+// See https://go.dev/wiki/CodeReviewComments#initialisms.
+var initialisms = map[string]bool{
+	"ACL":   true,
+	"API":   true,
+	"CPU":   true,
+	"DNS":   true,
+	"GPU":   true,
+	"HTTP":  true,
+	"HTTPS": true,
+	"ID":    true,
+	"IP":    true,
+	"JSON":  true,
+	"KMS":   true,
+	"RPC":   true,
+	"SQL":   true,
+	"SSH":   true,
+	"TCP":   true,
+	"TLS":   true,
+	"TTL":   true,
+	"UDP":   true,
+	"URI":   true,
+	"URL":   true,
+	"UUID":  true,
+	"VM":    true,
+	"XML":   true,
+}
+
+// wellKnownTypes maps the protobuf well-known types to the Go type used to
+// represent their JSON encoding.
+var wellKnownTypes = map[string]string{
+	".google.protobuf.Any":         "map[string]any",
+	".google.protobuf.BoolValue":   "*bool",
+	".google.protobuf.BytesValue":  "[]byte",
+	".google.protobuf.DoubleValue": "*float64",
+	".google.protobuf.Duration":    "string",
+	".google.protobuf.Empty":       "struct{}",
+	".google.protobuf.FieldMask":   "string",
+	".google.protobuf.FloatValue":  "*float32",
+	".google.protobuf.Int32Value":  "*int32",
+	".google.protobuf.Int64Value":  "*int64",
+	".google.protobuf.ListValue":   "[]any",
+	".google.protobuf.StringValue": "*string",
+	".google.protobuf.Struct":      "map[string]any",
+	".google.protobuf.Timestamp":   "string",
+	".google.protobuf.UInt32Value": "*uint32",
+	".google.protobuf.UInt64Value": "*uint64",
+	".google.protobuf.Value":       "any",
+}
+
+// goName converts a name in snake_case, camelCase or PascalCase to an
+// exported Go identifier, spelling initialisms in all caps.
+func goName(name string) string {
+	var builder strings.Builder
+	for _, word := range splitWords(name) {
+		upper := strings.ToUpper(word)
+		if initialisms[upper] {
+			builder.WriteString(upper)
+			continue
+		}
+		runes := []rune(strings.ToLower(word))
+		runes[0] = unicode.ToUpper(runes[0])
+		builder.WriteString(string(runes))
+	}
+	result := builder.String()
+	if result == "" || !unicode.IsLetter([]rune(result)[0]) {
+		result = "X" + result
+	}
+	return result
+}
+
+// splitWords splits an identifier at underscores, dashes, dots and at
+// lowercase to uppercase transitions.
+func splitWords(name string) []string {
+	var words []string
+	var current []rune
+	flush := func() {
+		if len(current) > 0 {
+			words = append(words, string(current))
+			current = nil
+		}
+	}
+	runes := []rune(name)
+	for i, r := range runes {
+		switch {
+		case r == '_' || r == '-' || r == '.' || r == ' ':
+			flush()
+			continue
+		case unicode.IsUpper(r) && i > 0 && (unicode.IsLower(runes[i-1]) || unicode.IsDigit(runes[i-1])):
+			flush()
+		case unicode.IsUpper(r) && i > 0 && unicode.IsUpper(runes[i-1]) && i+1 < len(runes) && unicode.IsLower(runes[i+1]):
+			// Split "HTTPServer" into "HTTP" and "Server".
+			flush()
+		}
+		current = append(current, r)
+	}
+	flush()
+	return words
+}
+
+func messageName(m *api.Message) string {
+	name := goName(m.Name)
+	if m.Parent != nil {
+		return messageName(m.Parent) + nestedChar + name
+	}
+	return name
+}
+
+func enumName(e *api.Enum) string {
+	name := goName(e.Name)
+	if e.Parent != nil {
+		return messageName(e.Parent) + nestedChar + name
+	}
+	return name
+}
+
+func enumValueName(e *api.Enum, ev *api.EnumValue) string {
+	return enumName(e) + nestedChar + ev.Name
+}
+
+func fieldName(f *api.Field) string {
+	return goName(f.Name)
+}
+
+// packageName returns the Go package name for the API, which is the last
+// element of the protobuf package that is not a version, for example
+// `secretmanager` for `google.cloud.secretmanager.v1`.
+func packageName(model *api.API, packageNameOverride string) string {
+	if packageNameOverride != "" {
+		return packageNameOverride
+	}
+	elements := strings.Split(model.PackageName, ".")
+	for i := len(elements) - 1; i >= 0; i-- {
+		if elements[i] == "" || versionRegex.MatchString(elements[i]) {
+			continue
+		}
+		return sanitizePackageName(elements[i])
+	}
+	return sanitizePackageName(model.Name)
+}
+
+var versionRegex = regexp.MustCompile(`^v\d+(p\d+)?((alpha|beta)\d*)?$`)
+
+func sanitizePackageName(name string) string {
+	name = strings.ToLower(name)
+	name = strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return r
+		}
+		return -1
+	}, name)
+	if name == "" {
+		return "api"
+	}
+	return name
+}
+
+// commentRefsRegex matches Google API documentation reference links; it supports
+// both regular references as well as implicit references.
+//
+// - `[Code][google.rpc.Code]`
+// - `[google.rpc.Code][]`.
+var commentRefsRegex = regexp.MustCompile(`\[([\w\d\._]+)\]\[([\d\w\._]*)\]`)
+
+func formatDocComments(documentation string) []string {
+	lines := strings.Split(documentation, "\n")
+	for i, line := range lines {
+		line = strings.TrimRightFunc(line, unicode.IsSpace)
+		lines[i] = commentRefsRegex.ReplaceAllString(line, "$1")
+	}
+	for len(lines) > 0 && len(lines[len(lines)-1]) == 0 {
+		lines = lines[:len(lines)-1]
+	}
+	for len(lines) > 0 && len(lines[0]) == 0 {
+		lines = lines[1:]
+	}
+	for i, line := range lines {
+		if len(line) == 0 {
+			lines[i] = "//"
+		} else {
+			lines[i] = "// " + line
+		}
+	}
+	return lines
+}
+
+func shouldGenerateMethod(m *api.Method) bool {
+	if m.ClientSideStreaming || m.ServerSideStreaming || m.PathInfo == nil {
+		return false
+	}
+	if len(m.PathInfo.Bindings) == 0 {
+		return false
+	}
+	return m.PathInfo.Bindings[0].PathTemplate != nil
+}
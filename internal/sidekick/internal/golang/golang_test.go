@@ -0,0 +1,119 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package golang
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/googleapis/librarian/internal/sidekick/internal/api"
+)
+
+func TestGoName(t *testing.T) {
+	for _, test := range []struct {
+		input string
+		want  string
+	}{
+		{"secret_id", "SecretID"},
+		{"secretId", "SecretID"},
+		{"SecretManagerService", "SecretManagerService"},
+		{"http_rule", "HTTPRule"},
+		{"HTTPServer", "HTTPServer"},
+		{"kms_key_name", "KMSKeyName"},
+		{"ttl", "TTL"},
+		{"uri", "URI"},
+		{"page_size", "PageSize"},
+		{"etag", "Etag"},
+		{"v1_config", "V1Config"},
+		{"1st", "X1st"},
+		{"", "X"},
+	} {
+		if got := goName(test.input); got != test.want {
+			t.Errorf("goName(%q) = %q, want %q", test.input, got, test.want)
+		}
+	}
+}
+
+func TestNestedNames(t *testing.T) {
+	parent := &api.Message{Name: "Secret", ID: ".test.Secret"}
+	child := &api.Message{Name: "Replication", ID: ".test.Secret.Replication", Parent: parent}
+	enum := &api.Enum{Name: "State", ID: ".test.Secret.State", Parent: parent}
+	value := &api.EnumValue{Name: "ENABLED", Parent: enum}
+
+	if got, want := messageName(child), "Secret_Replication"; got != want {
+		t.Errorf("messageName() = %q, want %q", got, want)
+	}
+	if got, want := enumName(enum), "Secret_State"; got != want {
+		t.Errorf("enumName() = %q, want %q", got, want)
+	}
+	if got, want := enumValueName(enum, value), "Secret_State_ENABLED"; got != want {
+		t.Errorf("enumValueName() = %q, want %q", got, want)
+	}
+}
+
+func TestPackageName(t *testing.T) {
+	for _, test := range []struct {
+		packageName string
+		override    string
+		want        string
+	}{
+		{"google.cloud.secretmanager.v1", "", "secretmanager"},
+		{"google.cloud.functions.v2beta", "", "functions"},
+		{"google.cloud.bigquery.v2p1alpha1", "", "bigquery"},
+		{"google.type", "", "type"},
+		{"google.cloud.secret-manager.v1", "", "secretmanager"},
+		{"google.cloud.secretmanager.v1", "sm", "sm"},
+	} {
+		model := api.NewTestAPI([]*api.Message{}, []*api.Enum{}, []*api.Service{})
+		model.PackageName = test.packageName
+		if got := packageName(model, test.override); got != test.want {
+			t.Errorf("packageName(%q, %q) = %q, want %q", test.packageName, test.override, got, test.want)
+		}
+	}
+}
+
+func TestFormatDocComments(t *testing.T) {
+	input := `
+Lists [SecretVersions][google.cloud.secretmanager.v1.SecretVersion].
+
+See [google.rpc.Code][] for details.   
+`
+	want := []string{
+		"// Lists SecretVersions.",
+		"//",
+		"// See google.rpc.Code for details.",
+	}
+	if diff := cmp.Diff(want, formatDocComments(input)); diff != "" {
+		t.Errorf("mismatch in formatDocComments (-want, +got)\n:%s", diff)
+	}
+}
+
+func TestShouldGenerateMethod(t *testing.T) {
+	binding := &api.PathBinding{Verb: "GET", PathTemplate: api.NewPathTemplate().WithLiteral("v1")}
+	for _, test := range []struct {
+		name   string
+		method *api.Method
+		want   bool
+	}{
+		{"unary", &api.Method{PathInfo: &api.PathInfo{Bindings: []*api.PathBinding{binding}}}, true},
+		{"no bindings", &api.Method{PathInfo: &api.PathInfo{}}, false},
+		{"no path info", &api.Method{}, false},
+		{"streaming", &api.Method{ServerSideStreaming: true, PathInfo: &api.PathInfo{Bindings: []*api.PathBinding{binding}}}, false},
+	} {
+		if got := shouldGenerateMethod(test.method); got != test.want {
+			t.Errorf("shouldGenerateMethod(%s) = %v, want %v", test.name, got, test.want)
+		}
+	}
+}
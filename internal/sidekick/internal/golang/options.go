@@ -0,0 +1,60 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package golang
+
+import (
+	"slices"
+
+	"github.com/googleapis/librarian/internal/sidekick/internal/language"
+	"github.com/googleapis/librarian/internal/sidekick/internal/license"
+	"github.com/googleapis/librarian/internal/sidekick/internal/options"
+	"github.com/googleapis/librarian/internal/sidekick/internal/surface"
+)
+
+// CodecOptions describes the codec options supported by the Go codec.
+var CodecOptions = slices.Concat([]options.Option{
+	{
+		Name:        "package-name-override",
+		Type:        options.String,
+		Description: "The name of the Go package. By default, the last element of the protobuf package which is not a version.",
+	},
+	{
+		Name:        "module-path",
+		Type:        options.String,
+		Description: "The module path in the generated `go.mod` file.",
+	},
+	{
+		Name:        "go-version",
+		Type:        options.String,
+		Default:     defaultGoVersion,
+		Description: "The `go` directive in the generated `go.mod` file.",
+	},
+	{
+		Name:        "require:<module>",
+		Type:        options.String,
+		Description: "Adds a `require` directive for `<module>`, using the option value as the version.",
+	},
+	{
+		Name:        "proto:<package>",
+		Type:        options.String,
+		Description: "The Go import path for the messages and enums in the protobuf `<package>`.",
+	},
+	{
+		Name:        "skip-format",
+		Type:        options.Bool,
+		Default:     "false",
+		Description: "If true, the generated files are not formatted.",
+	},
+}, license.Options, language.TemplateOptions, surface.Options)
@@ -0,0 +1,46 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package language
+
+import (
+	"fmt"
+	"go/format"
+	"os"
+	"path/filepath"
+)
+
+// FormatGoFiles formats the generated Go files in outdir using the same rules
+// as `gofmt`. Other generated files are left untouched. Errors usually
+// indicate a problem in the templates.
+func FormatGoFiles(outdir string, generatedFiles []GeneratedFile) error {
+	for _, f := range generatedFiles {
+		if filepath.Ext(f.OutputPath) != ".go" {
+			continue
+		}
+		path := filepath.Join(outdir, f.OutputPath)
+		contents, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		formatted, err := format.Source(contents)
+		if err != nil {
+			return fmt.Errorf("cannot format %s: %w", path, err)
+		}
+		if err := os.WriteFile(path, formatted, 0666); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,62 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package language
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestFormatGoFiles(t *testing.T) {
+	dir := t.TempDir()
+	files := []GeneratedFile{
+		{TemplatePath: "test.go.mustache", OutputPath: "test.go"},
+		{TemplatePath: "README.md.mustache", OutputPath: "README.md"},
+	}
+	unformatted := "package test\nfunc  F( ) {\n}\n"
+	for _, f := range files {
+		if err := os.WriteFile(filepath.Join(dir, f.OutputPath), []byte(unformatted), 0666); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := FormatGoFiles(dir, files); err != nil {
+		t.Fatal(err)
+	}
+	for _, test := range []struct {
+		filename string
+		want     string
+	}{
+		{"test.go", "package test\n\nfunc F() {\n}\n"},
+		{"README.md", unformatted},
+	} {
+		got, err := os.ReadFile(filepath.Join(dir, test.filename))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if diff := cmp.Diff(test.want, string(got)); diff != "" {
+			t.Errorf("mismatch in FormatGoFiles for %s (-want, +got)\n:%s", test.filename, diff)
+		}
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, "test.go"), []byte("package test\nfunc {\n"), 0666); err != nil {
+		t.Fatal(err)
+	}
+	if err := FormatGoFiles(dir, files); err == nil {
+		t.Errorf("expected an error formatting invalid Go code")
+	}
+}
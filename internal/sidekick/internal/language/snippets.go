@@ -0,0 +1,250 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package language
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/cbroglie/mustache"
+	"github.com/googleapis/librarian/internal/sidekick/internal/api"
+	"github.com/googleapis/librarian/internal/sidekick/internal/options"
+	"github.com/iancoleman/strcase"
+)
+
+const (
+	// GenerateSnippetsOption is the codec option to generate one snippet per
+	// method.
+	GenerateSnippetsOption = "generate-snippets"
+	// SnippetIndexFile is the name of the snippet index, generated in the
+	// same directory as the snippets.
+	SnippetIndexFile = "snippet_index.json"
+)
+
+// SnippetOptions describes the codec options for the codecs generating
+// snippets.
+var SnippetOptions = []options.Option{
+	{
+		Name:        GenerateSnippetsOption,
+		Type:        options.Bool,
+		Default:     "false",
+		Description: "If true, generate a sample for each method, and the snippet index.",
+	},
+}
+
+var versionRegex = regexp.MustCompile(`^v[0-9]+`)
+
+// Snippet represents the code sample for a single method.
+type Snippet struct {
+	// The region tag, documentation tooling uses it to find and embed the
+	// sample.
+	RegionTag string
+	// The service and method demonstrated by the sample.
+	Service *api.Service
+	Method  *api.Method
+	// The name of the template file, relative to the Codec's filesystem root.
+	TemplatePath string
+	// The name of the output file, relative to the output directory.
+	OutputPath string
+	// The input to the mustache template. Typically a codec-specific struct
+	// referencing the method and its annotations.
+	Context any
+}
+
+// SnippetFiles is the set of snippets generated for a model.
+type SnippetFiles struct {
+	// The name of the language, e.g. `rust`, recorded in the index.
+	Language string
+	// The directory for the snippet index, relative to the output directory.
+	// The snippets should be generated in the same directory, or its
+	// subdirectories.
+	Dir      string
+	Snippets []Snippet
+}
+
+// SnippetIndex lists the generated snippets, docs tooling uses this file to
+// embed the samples.
+type SnippetIndex struct {
+	// The name of the language, e.g. `rust`.
+	Language string `json:"language"`
+	// The specification package name, e.g. `google.cloud.secretmanager.v1`.
+	Package  string              `json:"package"`
+	Snippets []SnippetIndexEntry `json:"snippets"`
+}
+
+// SnippetIndexEntry describes a single snippet in the index.
+type SnippetIndexEntry struct {
+	RegionTag string `json:"regionTag"`
+	// The snippet file, relative to the index file.
+	File string `json:"file"`
+	// The fully qualified name of the service, e.g.
+	// `google.cloud.secretmanager.v1.SecretManagerService`.
+	Service string `json:"service"`
+	// The name of the method, e.g. `CreateSecret`.
+	Method      string `json:"method"`
+	Description string `json:"description,omitempty"`
+	Paginated   bool   `json:"paginated,omitempty"`
+	LongRunning bool   `json:"longRunning,omitempty"`
+}
+
+// SnippetRegionTag returns the region tag for the sample of a method.
+//
+// The tags follow the format used for samples in other Google Cloud client
+// libraries: `{api}_{version}_generated_{Service}_{Method}`, for example
+// `secretmanager_v1_generated_SecretManagerService_CreateSecret`.
+func SnippetRegionTag(model *api.API, s *api.Service, m *api.Method) string {
+	short := strings.Split(s.DefaultHost, ".")[0]
+	if short == "" {
+		short = strings.ToLower(model.Name)
+	}
+	components := []string{short}
+	pkg := strings.Split(s.Package, ".")
+	if version := pkg[len(pkg)-1]; versionRegex.MatchString(version) {
+		components = append(components, version)
+	}
+	components = append(components, "generated", s.Name, m.Name)
+	return strings.Join(components, "_")
+}
+
+// SnippetRequiredFields returns the fields a sample must set to make a valid
+// request.
+//
+// These are the fields annotated as `REQUIRED`, and any fields used in the
+// request path, in the order they appear in the request message.
+func SnippetRequiredFields(m *api.Method, state *api.APIState) []*api.Field {
+	if m.InputType == nil {
+		return nil
+	}
+	var pathParams []*api.Field
+	if m.PathInfo != nil && len(m.PathInfo.Bindings) != 0 && m.PathInfo.Bindings[0].PathTemplate != nil {
+		pathParams = PathParams(m, state)
+	}
+	return FilterSlice(m.InputType.Fields, func(f *api.Field) bool {
+		if slices.Contains(f.Behavior, api.FIELD_BEHAVIOR_OUTPUT_ONLY) {
+			return false
+		}
+		return f.DocumentAsRequired() || slices.Contains(pathParams, f)
+	})
+}
+
+// SnippetStringValue returns a sample value for a string field.
+//
+// For fields used in the request path the value matches the path template,
+// for example, `projects/my-project/secrets/my-secret`. Other fields get a
+// value derived from their name, such as `my-secret-id`.
+func SnippetStringValue(m *api.Method, f *api.Field) string {
+	if m.PathInfo != nil && len(m.PathInfo.Bindings) != 0 && m.PathInfo.Bindings[0].PathTemplate != nil {
+		for _, s := range m.PathInfo.Bindings[0].PathTemplate.Segments {
+			if s.Variable == nil || len(s.Variable.FieldPath) != 1 || s.Variable.FieldPath[0] != f.Name {
+				continue
+			}
+			return snippetPathValue(f, s.Variable.Segments)
+		}
+	}
+	return "my-" + strcase.ToKebab(f.Name)
+}
+
+func snippetPathValue(f *api.Field, segments []string) string {
+	var values []string
+	previous := ""
+	for _, s := range segments {
+		switch s {
+		case api.SingleSegmentWildcard, api.MultiSegmentWildcard:
+			values = append(values, "my-"+singular(previous, f))
+		default:
+			values = append(values, s)
+		}
+		previous = s
+	}
+	return strings.Join(values, "/")
+}
+
+func singular(collection string, f *api.Field) string {
+	switch {
+	case collection == "":
+		return strcase.ToKebab(f.Name)
+	case strings.HasSuffix(collection, "ies"):
+		return strcase.ToKebab(strings.TrimSuffix(collection, "ies") + "y")
+	default:
+		return strcase.ToKebab(strings.TrimSuffix(collection, "s"))
+	}
+}
+
+// GenerateSnippets renders each snippet using its own context, and writes the
+// snippet index for them.
+func GenerateSnippets(outdir string, model *api.API, provider TemplateProvider, files *SnippetFiles) error {
+	if outdir == "" {
+		wd, _ := os.Getwd()
+		outdir = wd
+	}
+	index := SnippetIndex{
+		Language: files.Language,
+		Package:  model.PackageName,
+		Snippets: []SnippetIndexEntry{},
+	}
+	var errs []error
+	for _, snippet := range files.Snippets {
+		templateContents, err := provider(snippet.TemplatePath)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		destination := filepath.Join(outdir, snippet.OutputPath)
+		os.MkdirAll(filepath.Dir(destination), 0777) // Ignore errors
+		nestedProvider := mustacheProvider{
+			impl:    provider,
+			dirname: filepath.Dir(snippet.TemplatePath),
+		}
+		s, err := mustache.RenderPartials(templateContents, &nestedProvider, snippet.Context)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if err := os.WriteFile(destination, []byte(s), 0666); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		file, err := filepath.Rel(files.Dir, snippet.OutputPath)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		index.Snippets = append(index.Snippets, SnippetIndexEntry{
+			RegionTag:   snippet.RegionTag,
+			File:        filepath.ToSlash(file),
+			Service:     strings.TrimPrefix(snippet.Service.ID, "."),
+			Method:      snippet.Method.Name,
+			Description: strings.Split(snippet.Method.Documentation, "\n")[0],
+			Paginated:   snippet.Method.Pagination != nil,
+			LongRunning: snippet.Method.OperationInfo != nil,
+		})
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("errors generating snippets: %w", errors.Join(errs...))
+	}
+	contents, err := json.MarshalIndent(index, "", "  ")
+	if err != nil {
+		return err
+	}
+	destination := filepath.Join(outdir, files.Dir, SnippetIndexFile)
+	os.MkdirAll(filepath.Dir(destination), 0777) // Ignore errors
+	return os.WriteFile(destination, append(contents, '\n'), 0666)
+}
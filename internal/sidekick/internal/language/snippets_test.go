@@ -0,0 +1,224 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package language
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/googleapis/librarian/internal/sidekick/internal/api"
+)
+
+func snippetTestModel() *api.API {
+	parent := &api.Field{
+		Name:     "parent",
+		JSONName: "parent",
+		ID:       ".test.v1.CreateWidgetRequest.parent",
+		Typez:    api.STRING_TYPE,
+	}
+	widgetID := &api.Field{
+		Name:     "widget_id",
+		JSONName: "widgetId",
+		ID:       ".test.v1.CreateWidgetRequest.widget_id",
+		Typez:    api.STRING_TYPE,
+		Behavior: []api.FieldBehavior{api.FIELD_BEHAVIOR_REQUIRED},
+	}
+	validateOnly := &api.Field{
+		Name:     "validate_only",
+		JSONName: "validateOnly",
+		ID:       ".test.v1.CreateWidgetRequest.validate_only",
+		Typez:    api.BOOL_TYPE,
+	}
+	etag := &api.Field{
+		Name:     "etag",
+		JSONName: "etag",
+		ID:       ".test.v1.CreateWidgetRequest.etag",
+		Typez:    api.STRING_TYPE,
+		Behavior: []api.FieldBehavior{api.FIELD_BEHAVIOR_REQUIRED, api.FIELD_BEHAVIOR_OUTPUT_ONLY},
+	}
+	request := &api.Message{
+		Name:    "CreateWidgetRequest",
+		ID:      ".test.v1.CreateWidgetRequest",
+		Package: "test.v1",
+		Fields:  []*api.Field{validateOnly, parent, etag, widgetID},
+	}
+	response := &api.Message{
+		Name:    "Widget",
+		ID:      ".test.v1.Widget",
+		Package: "test.v1",
+	}
+	method := &api.Method{
+		Name:          "CreateWidget",
+		ID:            ".test.v1.WidgetService.CreateWidget",
+		Documentation: "Creates a widget.\n\nMore details.",
+		InputTypeID:   request.ID,
+		InputType:     request,
+		OutputTypeID:  response.ID,
+		OutputType:    response,
+		PathInfo: &api.PathInfo{
+			Bindings: []*api.PathBinding{
+				{
+					Verb: "POST",
+					PathTemplate: api.NewPathTemplate().
+						WithLiteral("v1").
+						WithVariable(api.NewPathVariable("parent").
+							WithLiteral("projects").WithMatch().
+							WithLiteral("policies").WithMatch()).
+						WithLiteral("widgets"),
+				},
+			},
+		},
+	}
+	service := &api.Service{
+		Name:        "WidgetService",
+		ID:          ".test.v1.WidgetService",
+		Package:     "test.v1",
+		DefaultHost: "widgets.googleapis.com",
+		Methods:     []*api.Method{method},
+	}
+	return api.NewTestAPI([]*api.Message{request, response}, []*api.Enum{}, []*api.Service{service})
+}
+
+func TestSnippetRegionTag(t *testing.T) {
+	model := snippetTestModel()
+	service := model.Services[0]
+	method := service.Methods[0]
+	if got, want := SnippetRegionTag(model, service, method), "widgets_v1_generated_WidgetService_CreateWidget"; got != want {
+		t.Errorf("SnippetRegionTag() = %q, want = %q", got, want)
+	}
+
+	service.DefaultHost = ""
+	service.Package = "test"
+	if got, want := SnippetRegionTag(model, service, method), "test_generated_WidgetService_CreateWidget"; got != want {
+		t.Errorf("SnippetRegionTag() = %q, want = %q", got, want)
+	}
+}
+
+func TestSnippetRequiredFields(t *testing.T) {
+	model := snippetTestModel()
+	method := model.Services[0].Methods[0]
+	got := MapSlice(SnippetRequiredFields(method, model.State), func(f *api.Field) string { return f.Name })
+	want := []string{"parent", "widget_id"}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("mismatch (-want, +got):\n%s", diff)
+	}
+}
+
+func TestSnippetStringValue(t *testing.T) {
+	model := snippetTestModel()
+	method := model.Services[0].Methods[0]
+	want := map[string]string{
+		"parent":        "projects/my-project/policies/my-policy",
+		"validate_only": "my-validate-only",
+		"etag":          "my-etag",
+		"widget_id":     "my-widget-id",
+	}
+	for _, field := range method.InputType.Fields {
+		if got := SnippetStringValue(method, field); got != want[field.Name] {
+			t.Errorf("SnippetStringValue(%s) = %q, want = %q", field.Name, got, want[field.Name])
+		}
+	}
+}
+
+func TestGenerateSnippets(t *testing.T) {
+	model := snippetTestModel()
+	service := model.Services[0]
+	method := service.Methods[0]
+	provider := func(name string) (string, error) {
+		return "// [START {{RegionTag}}]\n{{Method.Name}}\n// [END {{RegionTag}}]\n", nil
+	}
+	regionTag := SnippetRegionTag(model, service, method)
+	outDir := t.TempDir()
+	files := &SnippetFiles{
+		Language: "test",
+		Dir:      "examples",
+		Snippets: []Snippet{
+			{
+				RegionTag:    regionTag,
+				Service:      service,
+				Method:       method,
+				TemplatePath: "templates/snippet.mustache",
+				OutputPath:   filepath.Join("examples", "widget_service", "create_widget.txt"),
+				Context: map[string]any{
+					"RegionTag": regionTag,
+					"Method":    method,
+				},
+			},
+		},
+	}
+	if err := GenerateSnippets(outDir, model, provider, files); err != nil {
+		t.Fatal(err)
+	}
+	got, err := os.ReadFile(filepath.Join(outDir, "examples", "widget_service", "create_widget.txt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "// [START widgets_v1_generated_WidgetService_CreateWidget]\nCreateWidget\n// [END widgets_v1_generated_WidgetService_CreateWidget]\n"
+	if diff := cmp.Diff(want, string(got)); diff != "" {
+		t.Errorf("mismatch in snippet (-want, +got):\n%s", diff)
+	}
+
+	contents, err := os.ReadFile(filepath.Join(outDir, "examples", SnippetIndexFile))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var gotIndex SnippetIndex
+	if err := json.Unmarshal(contents, &gotIndex); err != nil {
+		t.Fatal(err)
+	}
+	wantIndex := SnippetIndex{
+		Language: "test",
+		Package:  "test.v1",
+		Snippets: []SnippetIndexEntry{
+			{
+				RegionTag:   "widgets_v1_generated_WidgetService_CreateWidget",
+				File:        "widget_service/create_widget.txt",
+				Service:     "test.v1.WidgetService",
+				Method:      "CreateWidget",
+				Description: "Creates a widget.",
+			},
+		},
+	}
+	if diff := cmp.Diff(wantIndex, gotIndex); diff != "" {
+		t.Errorf("mismatch in snippet index (-want, +got):\n%s", diff)
+	}
+}
+
+func TestGenerateSnippetsErrors(t *testing.T) {
+	model := snippetTestModel()
+	service := model.Services[0]
+	method := service.Methods[0]
+	provider := func(name string) (string, error) {
+		return "", os.ErrNotExist
+	}
+	files := &SnippetFiles{
+		Language: "test",
+		Dir:      "examples",
+		Snippets: []Snippet{
+			{
+				Service:      service,
+				Method:       method,
+				TemplatePath: "templates/snippet.mustache",
+				OutputPath:   filepath.Join("examples", "create_widget.txt"),
+			},
+		},
+	}
+	if err := GenerateSnippets(t.TempDir(), model, provider, files); err == nil {
+		t.Errorf("expected an error with missing templates")
+	}
+}
@@ -0,0 +1,234 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package language
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/googleapis/librarian/internal/sidekick/internal/api"
+	"github.com/googleapis/librarian/internal/sidekick/internal/options"
+	toml "github.com/pelletier/go-toml/v2"
+)
+
+const (
+	// TemplateDirOption is the codec option with the directory of the
+	// template overrides.
+	TemplateDirOption = "template-dir"
+	// TemplateManifest is the name of the file, in the template overrides
+	// directory, listing any extra files to generate.
+	TemplateManifest = "sidekick-templates.toml"
+	// The directory containing the embedded templates in all codecs.
+	embeddedRoot = "templates"
+)
+
+// TemplateOptions describes the codec options to override the templates,
+// common to all codecs.
+var TemplateOptions = []options.Option{
+	{
+		Name:        TemplateDirOption,
+		Type:        options.String,
+		Description: "A directory with templates to use instead of the embedded templates.",
+	},
+}
+
+// TemplateOverrides layers a directory of user templates over the embedded
+// templates of a codec.
+//
+// The directory mirrors the layout of the codec's `templates` directory. Any
+// template, including partials, found in the directory is used instead of the
+// embedded template with the same path. For example, with the Rust codec
+// `crate/src/lib.rs.mustache` overrides `templates/crate/src/lib.rs.mustache`.
+//
+// The directory may also contain a manifest, named `sidekick-templates.toml`,
+// listing extra files to generate:
+//
+//	[[files]]
+//	template = "extra/CONTRIBUTING.md.mustache"
+//	output   = "CONTRIBUTING.md"
+//
+// A nil `*TemplateOverrides` is valid, and uses the embedded templates.
+type TemplateOverrides struct {
+	dir        string
+	extraFiles []GeneratedFile
+	// The templates read from `dir`, relative to `dir`.
+	overridden map[string]bool
+}
+
+type templateManifest struct {
+	Files []struct {
+		Template string `toml:"template"`
+		Output   string `toml:"output"`
+	} `toml:"files"`
+}
+
+// NewTemplateOverrides loads the template overrides in `dir`. It returns nil
+// if `dir` is empty.
+func NewTemplateOverrides(dir string) (*TemplateOverrides, error) {
+	if dir == "" {
+		return nil, nil
+	}
+	stat, err := os.Stat(dir)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read template overrides directory: %w", err)
+	}
+	if !stat.IsDir() {
+		return nil, fmt.Errorf("the template overrides path %s is not a directory", dir)
+	}
+	overrides := &TemplateOverrides{dir: dir, overridden: map[string]bool{}}
+	contents, err := os.ReadFile(filepath.Join(dir, TemplateManifest))
+	if errors.Is(err, fs.ErrNotExist) {
+		return overrides, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var manifest templateManifest
+	if err := toml.Unmarshal(contents, &manifest); err != nil {
+		return nil, fmt.Errorf("error reading template manifest %s: %w", filepath.Join(dir, TemplateManifest), err)
+	}
+	for _, f := range manifest.Files {
+		if f.Template == "" || f.Output == "" {
+			return nil, fmt.Errorf("the entries in %s require both a `template` and an `output`, got=%v", TemplateManifest, f)
+		}
+		if _, err := os.Stat(filepath.Join(dir, filepath.FromSlash(f.Template))); err != nil {
+			return nil, fmt.Errorf("missing template for extra file %s: %w", f.Output, err)
+		}
+		overrides.extraFiles = append(overrides.extraFiles, GeneratedFile{
+			TemplatePath: filepath.Join(embeddedRoot, filepath.FromSlash(f.Template)),
+			OutputPath:   filepath.FromSlash(f.Output),
+		})
+	}
+	return overrides, nil
+}
+
+// GenerateFromModelWithOverrides is like GenerateFromModel, but it layers the
+// template overrides configured in the `template-dir` codec option, if any,
+// over the embedded templates.
+func GenerateFromModelWithOverrides(outdir string, model *api.API, provider TemplateProvider, generatedFiles []GeneratedFile, options map[string]string) error {
+	return GenerateFromModelAndSnippets(outdir, model, provider, generatedFiles, nil, options)
+}
+
+// GenerateFromModelAndSnippets is like GenerateFromModelWithOverrides, but it
+// also generates the snippets in `snippets`, if not nil, using the same
+// template overrides.
+func GenerateFromModelAndSnippets(outdir string, model *api.API, provider TemplateProvider, generatedFiles []GeneratedFile, snippets *SnippetFiles, options map[string]string) error {
+	overrides, err := NewTemplateOverrides(options[TemplateDirOption])
+	if err != nil {
+		return err
+	}
+	provider = overrides.Provider(provider)
+	if err := GenerateFromModel(outdir, model, provider, overrides.GeneratedFiles(generatedFiles)); err != nil {
+		return err
+	}
+	if snippets != nil {
+		if err := GenerateSnippets(outdir, model, provider, snippets); err != nil {
+			return err
+		}
+	}
+	overrides.LogOverridden()
+	return nil
+}
+
+// Provider returns a template provider which reads the templates from the
+// overrides directory, and falls back to `embedded` for any templates not
+// found there.
+func (o *TemplateOverrides) Provider(embedded TemplateProvider) TemplateProvider {
+	if o == nil {
+		return embedded
+	}
+	return func(name string) (string, error) {
+		relative := strings.TrimPrefix(filepath.ToSlash(name), embeddedRoot+"/")
+		contents, err := os.ReadFile(filepath.Join(o.dir, filepath.FromSlash(relative)))
+		if err == nil {
+			o.overridden[relative] = true
+			return string(contents), nil
+		}
+		if !errors.Is(err, fs.ErrNotExist) {
+			return "", err
+		}
+		return embedded(name)
+	}
+}
+
+// GeneratedFiles returns `files` and any extra files listed in the manifest.
+func (o *TemplateOverrides) GeneratedFiles(files []GeneratedFile) []GeneratedFile {
+	if o == nil {
+		return files
+	}
+	return append(files, o.extraFiles...)
+}
+
+// Overridden returns the templates, relative to the overrides directory, that
+// were used instead of an embedded template or to generate an extra file.
+func (o *TemplateOverrides) Overridden() []string {
+	if o == nil {
+		return nil
+	}
+	var names []string
+	for name := range o.overridden {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Unused returns the templates in the overrides directory which were not used
+// during generation. These are often typos in the template paths.
+func (o *TemplateOverrides) Unused() ([]string, error) {
+	if o == nil {
+		return nil, nil
+	}
+	var unused []string
+	err := filepath.WalkDir(o.dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || filepath.Ext(path) != ".mustache" {
+			return nil
+		}
+		relative, err := filepath.Rel(o.dir, path)
+		if err != nil {
+			return err
+		}
+		relative = filepath.ToSlash(relative)
+		if !o.overridden[relative] {
+			unused = append(unused, relative)
+		}
+		return nil
+	})
+	return unused, err
+}
+
+// LogOverridden logs the templates returned by Overridden, and warns about
+// any templates returned by Unused.
+func (o *TemplateOverrides) LogOverridden() {
+	for _, name := range o.Overridden() {
+		slog.Info("using template override", "dir", o.dir, "template", name)
+	}
+	unused, err := o.Unused()
+	if err != nil {
+		slog.Warn("cannot list the template overrides", "dir", o.dir, "error", err)
+	}
+	for _, name := range unused {
+		slog.Warn("template override not used, check its path", "dir", o.dir, "template", name)
+	}
+}
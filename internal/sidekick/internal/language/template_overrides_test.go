@@ -0,0 +1,170 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package language
+
+import (
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/googleapis/librarian/internal/sidekick/internal/api"
+)
+
+var overrideTestTemplates = fstest.MapFS{
+	"templates/README.md.mustache":     {Data: []byte("# {{{Title}}}\n{{> common/footer}}\n")},
+	"templates/common/footer.mustache": {Data: []byte("embedded footer")},
+	"templates/src/lib.txt.mustache":   {Data: []byte("embedded lib")},
+}
+
+func overrideTestProvider(name string) (string, error) {
+	contents, err := fs.ReadFile(overrideTestTemplates, filepath.ToSlash(name))
+	if err != nil {
+		return "", err
+	}
+	return string(contents), nil
+}
+
+func writeOverrideFiles(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+	for name, contents := range files {
+		filename := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(filename), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filename, []byte(contents), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestTemplateOverridesNil(t *testing.T) {
+	overrides, err := NewTemplateOverrides("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if overrides != nil {
+		t.Fatalf("expected nil overrides without a directory, got=%v", overrides)
+	}
+	got, err := overrides.Provider(overrideTestProvider)("templates/src/lib.txt.mustache")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != "embedded lib" {
+		t.Errorf("mismatch in template contents, got=%q", got)
+	}
+	files := []GeneratedFile{{TemplatePath: "templates/src/lib.txt.mustache", OutputPath: "src/lib.txt"}}
+	if diff := cmp.Diff(files, overrides.GeneratedFiles(files)); diff != "" {
+		t.Errorf("mismatch in generated files (-want, +got)\n:%s", diff)
+	}
+	if got := overrides.Overridden(); len(got) != 0 {
+		t.Errorf("expected no overridden templates, got=%v", got)
+	}
+}
+
+func TestGenerateFromModelWithOverrides(t *testing.T) {
+	overridesDir := t.TempDir()
+	writeOverrideFiles(t, overridesDir, map[string]string{
+		"common/footer.mustache":      "custom footer",
+		"extra/NOTICE.txt.mustache":   "Notice for {{{Title}}}\n",
+		"crate/typo.txt.mustache":     "never used",
+		"sidekick-templates.toml":     "[[files]]\ntemplate = \"extra/NOTICE.txt.mustache\"\noutput = \"NOTICE\"\n",
+		"not-a-template/README.md.md": "ignored",
+	})
+	model := api.NewTestAPI([]*api.Message{}, []*api.Enum{}, []*api.Service{})
+	model.Title = "Test API"
+	outDir := t.TempDir()
+	generatedFiles := WalkTemplatesDir(overrideTestTemplates, "templates")
+	options := map[string]string{"template-dir": overridesDir}
+	if err := GenerateFromModelWithOverrides(outDir, model, overrideTestProvider, generatedFiles, options); err != nil {
+		t.Fatal(err)
+	}
+	for name, want := range map[string]string{
+		"README.md":   "# Test API\ncustom footer",
+		"src/lib.txt": "embedded lib",
+		"NOTICE":      "Notice for Test API\n",
+	} {
+		got, err := os.ReadFile(path.Join(outDir, name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if diff := cmp.Diff(want, string(got)); diff != "" {
+			t.Errorf("mismatch in %s (-want, +got)\n:%s", name, diff)
+		}
+	}
+}
+
+func TestTemplateOverridesReport(t *testing.T) {
+	overridesDir := t.TempDir()
+	writeOverrideFiles(t, overridesDir, map[string]string{
+		"common/footer.mustache":  "custom footer",
+		"src/lib.txt.mustache":    "custom lib",
+		"crate/typo.txt.mustache": "never used",
+	})
+	overrides, err := NewTemplateOverrides(overridesDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	files := overrides.GeneratedFiles(WalkTemplatesDir(overrideTestTemplates, "templates"))
+	if err := GenerateFromModel(t.TempDir(), api.NewTestAPI([]*api.Message{}, []*api.Enum{}, []*api.Service{}), overrides.Provider(overrideTestProvider), files); err != nil {
+		t.Fatal(err)
+	}
+	wantOverridden := []string{"common/footer.mustache", "src/lib.txt.mustache"}
+	if diff := cmp.Diff(wantOverridden, overrides.Overridden()); diff != "" {
+		t.Errorf("mismatch in overridden templates (-want, +got)\n:%s", diff)
+	}
+	unused, err := overrides.Unused()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff([]string{"crate/typo.txt.mustache"}, unused, cmpopts.EquateEmpty()); diff != "" {
+		t.Errorf("mismatch in unused templates (-want, +got)\n:%s", diff)
+	}
+}
+
+func TestNewTemplateOverridesErrors(t *testing.T) {
+	notADir := path.Join(t.TempDir(), "file.txt")
+	if err := os.WriteFile(notADir, []byte{}, 0644); err != nil {
+		t.Fatal(err)
+	}
+	badManifest := t.TempDir()
+	writeOverrideFiles(t, badManifest, map[string]string{
+		"sidekick-templates.toml": "[[files]]\ntemplate = \"missing.txt.mustache\"\noutput = \"missing.txt\"\n",
+	})
+	incompleteManifest := t.TempDir()
+	writeOverrideFiles(t, incompleteManifest, map[string]string{
+		"sidekick-templates.toml": "[[files]]\ntemplate = \"extra.txt.mustache\"\n",
+		"extra.txt.mustache":      "extra",
+	})
+	invalidManifest := t.TempDir()
+	writeOverrideFiles(t, invalidManifest, map[string]string{
+		"sidekick-templates.toml": "[[files]\n",
+	})
+	for _, dir := range []string{
+		path.Join(t.TempDir(), "missing"),
+		notADir,
+		badManifest,
+		incompleteManifest,
+		invalidManifest,
+	} {
+		if got, err := NewTemplateOverrides(dir); err == nil {
+			t.Errorf("expected an error for %s, got=%v", dir, got)
+		}
+	}
+}
@@ -14,7 +14,170 @@
 
 package license
 
-import "fmt"
+import (
+	"fmt"
+	"os"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/googleapis/librarian/internal/sidekick/internal/options"
+)
+
+// DefaultHolder is the copyright holder used if none is configured.
+const DefaultHolder = "Google LLC"
+
+// DefaultLicense is the SPDX ID of the license used if none is configured.
+const DefaultLicense = "Apache-2.0"
+
+// The codec options used to configure the license header.
+const (
+	// OptionHolder is the codec option with the copyright holder.
+	OptionHolder = "copyright-holder"
+	// OptionLicense is the codec option with the SPDX ID of the license.
+	OptionLicense = "license"
+	// OptionHeaderFile is the codec option with the path of a file containing
+	// a custom license header.
+	OptionHeaderFile = "license-header-file"
+)
+
+// Options describes the codec options used to configure the copyright line
+// and license header, common to all codecs.
+var Options = []options.Option{
+	{
+		Name:        "copyright-year",
+		Type:        options.String,
+		Description: "The year in the copyright line. `sidekick generate` sets it to the year the library is first generated.",
+	},
+	{
+		Name:        OptionHolder,
+		Type:        options.String,
+		Default:     DefaultHolder,
+		Description: "The copyright holder.",
+	},
+	{
+		Name:        OptionLicense,
+		Type:        options.String,
+		Default:     DefaultLicense,
+		Description: "The SPDX ID of a bundled license header.",
+	},
+	{
+		Name:        OptionHeaderFile,
+		Type:        options.String,
+		Description: "The path of a file with a custom license header. Cannot be combined with `license`.",
+	},
+}
+
+// Header is the copyright and license header for generated files.
+type Header struct {
+	// Holder is the copyright holder, e.g. `Google LLC`.
+	Holder string
+	// License is the SPDX ID of the license, e.g. `Apache-2.0`. It is empty
+	// for custom license headers.
+	License string
+	// Bulk contains the lines following the copyright line. The lines do not
+	// include any comment markers, and non-empty lines start with a space.
+	Bulk []string
+}
+
+// bundled contains the headers for the supported SPDX license IDs.
+var bundled = map[string][]string{
+	"Apache-2.0": LicenseHeaderBulk(),
+	"BSD-3-Clause": {
+		"",
+		" Use of this source code is governed by a BSD-style license that can be",
+		" found in the LICENSE file or at https://opensource.org/licenses/BSD-3-Clause.",
+		"",
+		" SPDX-License-Identifier: BSD-3-Clause",
+	},
+	"MIT": {
+		"",
+		" Use of this source code is governed by an MIT-style license that can be",
+		" found in the LICENSE file or at https://opensource.org/licenses/MIT.",
+		"",
+		" SPDX-License-Identifier: MIT",
+	},
+}
+
+// NewHeader returns the header for the given configuration.
+//
+// If `headerFile` is set, the header contains its contents, otherwise it
+// contains the bundled header for the `spdxID` license. Empty values use
+// DefaultHolder and DefaultLicense. It is an error to set both `spdxID` and
+// `headerFile`.
+func NewHeader(holder, spdxID, headerFile string) (*Header, error) {
+	if holder == "" {
+		holder = DefaultHolder
+	}
+	if headerFile != "" {
+		if spdxID != "" {
+			return nil, fmt.Errorf("only one of %q and %q can be set", OptionLicense, OptionHeaderFile)
+		}
+		contents, err := os.ReadFile(headerFile)
+		if err != nil {
+			return nil, fmt.Errorf("cannot read license header file: %w", err)
+		}
+		return &Header{Holder: holder, Bulk: headerFileBulk(string(contents))}, nil
+	}
+	if spdxID == "" {
+		spdxID = DefaultLicense
+	}
+	bulk, ok := bundled[spdxID]
+	if !ok {
+		return nil, fmt.Errorf("unknown license %q, the supported licenses are %s, use %q for other licenses",
+			spdxID, strings.Join(SupportedLicenses(), ", "), OptionHeaderFile)
+	}
+	return &Header{Holder: holder, License: spdxID, Bulk: slices.Clone(bulk)}, nil
+}
+
+// DefaultHeader returns the header used if none is configured.
+func DefaultHeader() *Header {
+	return &Header{Holder: DefaultHolder, License: DefaultLicense, Bulk: LicenseHeaderBulk()}
+}
+
+// NewHeaderFromOptions returns the header configured in the codec options.
+func NewHeaderFromOptions(options map[string]string) (*Header, error) {
+	return NewHeader(options[OptionHolder], options[OptionLicense], options[OptionHeaderFile])
+}
+
+// SupportedLicenses returns the SPDX IDs of the bundled license headers.
+func SupportedLicenses() []string {
+	var ids []string
+	for id := range bundled {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// IsOption returns true if `key` is one of the codec options used to configure
+// the license header.
+func IsOption(key string) bool {
+	return key == OptionHolder || key == OptionLicense || key == OptionHeaderFile
+}
+
+// Lines returns the full header with the given year.
+func (h *Header) Lines(year string) []string {
+	full := []string{fmt.Sprintf(" Copyright %s %s", year, h.Holder)}
+	full = append(full, h.Bulk...)
+	return full
+}
+
+// headerFileBulk converts the contents of a custom license header file to the
+// format used in `Header.Bulk`. The file contains the license text, without
+// the copyright line and without comment markers.
+func headerFileBulk(contents string) []string {
+	bulk := []string{""}
+	for _, line := range strings.Split(strings.TrimRight(contents, "\n"), "\n") {
+		line = strings.TrimRight(line, " \t\r")
+		if line == "" {
+			bulk = append(bulk, "")
+			continue
+		}
+		bulk = append(bulk, " "+line)
+	}
+	return bulk
+}
 
 // LicenseHeader returns the license header with the given year.
 func LicenseHeader(year string) []string {
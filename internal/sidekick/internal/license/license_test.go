@@ -15,8 +15,12 @@
 package license
 
 import (
+	"os"
+	"path"
 	"strings"
 	"testing"
+
+	"github.com/google/go-cmp/cmp"
 )
 
 func TestLicense(t *testing.T) {
@@ -29,3 +33,105 @@ func TestLicense(t *testing.T) {
 		t.Errorf("bad start line for LicenseHeader(), got=%q, want=%q", got[0], want)
 	}
 }
+
+func TestNewHeader(t *testing.T) {
+	for _, test := range []struct {
+		name        string
+		holder      string
+		spdxID      string
+		wantHolder  string
+		wantLicense string
+		wantLast    string
+	}{
+		{
+			name:        "defaults",
+			wantHolder:  "Google LLC",
+			wantLicense: "Apache-2.0",
+			wantLast:    " limitations under the License.",
+		},
+		{
+			name:        "mit",
+			holder:      "Example Inc.",
+			spdxID:      "MIT",
+			wantHolder:  "Example Inc.",
+			wantLicense: "MIT",
+			wantLast:    " SPDX-License-Identifier: MIT",
+		},
+		{
+			name:        "bsd",
+			spdxID:      "BSD-3-Clause",
+			wantHolder:  "Google LLC",
+			wantLicense: "BSD-3-Clause",
+			wantLast:    " SPDX-License-Identifier: BSD-3-Clause",
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := NewHeader(test.holder, test.spdxID, "")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got.Holder != test.wantHolder {
+				t.Errorf("mismatched holder, got=%q, want=%q", got.Holder, test.wantHolder)
+			}
+			if got.License != test.wantLicense {
+				t.Errorf("mismatched license, got=%q, want=%q", got.License, test.wantLicense)
+			}
+			if last := got.Bulk[len(got.Bulk)-1]; last != test.wantLast {
+				t.Errorf("mismatched last line, got=%q, want=%q", last, test.wantLast)
+			}
+		})
+	}
+}
+
+func TestNewHeaderFile(t *testing.T) {
+	headerFile := path.Join(t.TempDir(), "header.txt")
+	contents := "Proprietary and confidential.\n\nDo not distribute.\n"
+	if err := os.WriteFile(headerFile, []byte(contents), 0644); err != nil {
+		t.Fatal(err)
+	}
+	got, err := NewHeaderFromOptions(map[string]string{
+		"copyright-holder":    "Example Inc.",
+		"license-header-file": headerFile,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{
+		" Copyright 2038 Example Inc.",
+		"",
+		" Proprietary and confidential.",
+		"",
+		" Do not distribute.",
+	}
+	if diff := cmp.Diff(want, got.Lines("2038")); diff != "" {
+		t.Errorf("mismatch in header lines (-want, +got)\n:%s", diff)
+	}
+	if got.License != "" {
+		t.Errorf("expected an empty license for custom headers, got=%q", got.License)
+	}
+}
+
+func TestNewHeaderErrors(t *testing.T) {
+	for _, test := range []struct {
+		name       string
+		spdxID     string
+		headerFile string
+	}{
+		{name: "unknown license", spdxID: "GPL-3.0-only"},
+		{name: "missing file", headerFile: path.Join(t.TempDir(), "missing.txt")},
+		{name: "both", spdxID: "MIT", headerFile: "header.txt"},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			if got, err := NewHeader("", test.spdxID, test.headerFile); err == nil {
+				t.Errorf("expected an error, got=%v", got)
+			}
+		})
+	}
+}
+
+func TestDefaultHeader(t *testing.T) {
+	got := DefaultHeader().Lines("2038")
+	if diff := cmp.Diff(LicenseHeader("2038"), got); diff != "" {
+		t.Errorf("mismatch in default header (-want, +got)\n:%s", diff)
+	}
+}
@@ -0,0 +1,255 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mock_server
+
+import (
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/googleapis/librarian/internal/sidekick/internal/api"
+	"github.com/googleapis/librarian/internal/sidekick/internal/language"
+	"github.com/googleapis/librarian/internal/sidekick/internal/license"
+)
+
+const (
+	defaultModulePath = "mockserver"
+	defaultGoVersion  = "1.23"
+	operationID       = ".google.longrunning.Operation"
+)
+
+// modelAnnotations contains the annotations used by the mock server
+// templates.
+type modelAnnotations struct {
+	CopyrightYear   string
+	CopyrightHolder string
+	BoilerPlate     []string
+	ModulePath      string
+	GoVersion       string
+	// The specification package name, e.g. `google.cloud.secretmanager.v1`.
+	PackageName string
+	Routes      []*routeAnnotation
+	// If true, the API has a `GetOperation` method, and the server can
+	// return long-running operations which complete when polled.
+	CanPoll bool
+}
+
+// routeAnnotation describes how the server handles a single HTTP binding of
+// a method.
+type routeAnnotation struct {
+	// The fully qualified method name, without the leading dot, e.g.
+	// `google.cloud.secretmanager.v1.SecretManagerService.CreateSecret`.
+	RPC string
+	// The HTTP method, e.g. `POST`.
+	Verb string
+	// The regular expression matching the URL path. Each capture group
+	// matches a path variable.
+	Pattern string
+	// The JSON names of the request fields set by each path variable. Nested
+	// fields use dots, e.g. `secret.name`.
+	PathFields []string
+	// The JSON name of the request field initialized from the body, `*` for
+	// the full request, or empty if the request has no body.
+	BodyField string
+	// The JSON names of the request fields that must be set.
+	RequiredFields []string
+	LongRunning    bool
+	// The fully qualified names of the response and metadata types of a
+	// long-running operation.
+	ResponseType string
+	MetadataType string
+	// If true, the route returns the operations created by other routes.
+	GetOperation bool
+	// The JSON names of the fields used to paginate the response. These are
+	// empty if the method is not paginated.
+	PageTokenField     string
+	PageSizeField      string
+	NextPageTokenField string
+	ItemsField         string
+}
+
+// Paginated returns true if the server splits the response in pages.
+func (r *routeAnnotation) Paginated() bool {
+	return r.ItemsField != ""
+}
+
+func annotateModel(model *api.API, options map[string]string) error {
+	header, err := license.NewHeaderFromOptions(options)
+	if err != nil {
+		return err
+	}
+	ann := &modelAnnotations{
+		CopyrightYear:   options["copyright-year"],
+		CopyrightHolder: header.Holder,
+		BoilerPlate:     header.Bulk,
+		ModulePath:      defaultModulePath,
+		GoVersion:       defaultGoVersion,
+		PackageName:     model.PackageName,
+	}
+	if value, ok := options["module-path"]; ok {
+		ann.ModulePath = value
+	}
+	if value, ok := options["go-version"]; ok {
+		ann.GoVersion = value
+	}
+	for _, s := range model.Services {
+		for _, m := range s.Methods {
+			routes := annotateMethod(m, model.State)
+			ann.Routes = append(ann.Routes, routes...)
+			if slices.ContainsFunc(routes, func(r *routeAnnotation) bool { return r.GetOperation }) {
+				ann.CanPoll = true
+			}
+		}
+	}
+	model.Codec = ann
+	return nil
+}
+
+// annotateMethod returns the routes for each HTTP binding of `m`.
+//
+// Streaming methods, and methods without HTTP bindings, are not supported
+// over HTTP/JSON, and do not have any routes.
+func annotateMethod(m *api.Method, state *api.APIState) []*routeAnnotation {
+	if m.ClientSideStreaming || m.ServerSideStreaming || m.PathInfo == nil || m.InputType == nil {
+		return nil
+	}
+	var required []string
+	for _, f := range language.SnippetRequiredFields(m, state) {
+		required = append(required, f.JSONName)
+	}
+	var routes []*routeAnnotation
+	for _, b := range m.PathInfo.Bindings {
+		if b.PathTemplate == nil {
+			continue
+		}
+		route := &routeAnnotation{
+			RPC:            strings.TrimPrefix(m.ID, "."),
+			Verb:           b.Verb,
+			Pattern:        pathPattern(b.PathTemplate),
+			BodyField:      jsonPath(m.InputType, strings.Split(m.PathInfo.BodyFieldPath, "."), state),
+			RequiredFields: required,
+			GetOperation:   m.Name == "GetOperation" && m.OutputTypeID == operationID,
+		}
+		if m.PathInfo.BodyFieldPath == "*" {
+			route.BodyField = "*"
+		}
+		for _, s := range b.PathTemplate.Segments {
+			if s.Variable != nil {
+				route.PathFields = append(route.PathFields, jsonPath(m.InputType, s.Variable.FieldPath, state))
+			}
+		}
+		if m.OperationInfo != nil {
+			route.LongRunning = true
+			route.ResponseType = strings.TrimPrefix(m.OperationInfo.ResponseTypeID, ".")
+			route.MetadataType = strings.TrimPrefix(m.OperationInfo.MetadataTypeID, ".")
+		}
+		if m.Pagination != nil && m.OutputType != nil && m.OutputType.Pagination != nil {
+			route.PageTokenField = m.Pagination.JSONName
+			route.NextPageTokenField = m.OutputType.Pagination.NextPageToken.JSONName
+			route.ItemsField = m.OutputType.Pagination.PageableItem.JSONName
+			if f := pageSizeField(m.InputType); f != nil {
+				route.PageSizeField = f.JSONName
+			}
+		}
+		routes = append(routes, route)
+	}
+	return routes
+}
+
+// pathPattern returns a regular expression matching the URL paths for a path
+// template.
+//
+// Each variable becomes a capture group, so the server can initialize the
+// request fields from the path.
+func pathPattern(t *api.PathTemplate) string {
+	var builder strings.Builder
+	builder.WriteString("^")
+	for _, s := range t.Segments {
+		builder.WriteString("/")
+		switch {
+		case s.Literal != nil:
+			builder.WriteString(regexp.QuoteMeta(*s.Literal))
+		case s.Variable != nil:
+			segments := s.Variable.Segments
+			if len(segments) == 0 {
+				segments = []string{api.SingleSegmentWildcard}
+			}
+			var parts []string
+			for _, segment := range segments {
+				switch segment {
+				case api.SingleSegmentWildcard:
+					parts = append(parts, "[^/]+")
+				case api.MultiSegmentWildcard:
+					parts = append(parts, ".+")
+				default:
+					parts = append(parts, regexp.QuoteMeta(segment))
+				}
+			}
+			builder.WriteString("(" + strings.Join(parts, "/") + ")")
+		}
+	}
+	if t.Verb != nil {
+		builder.WriteString(":" + regexp.QuoteMeta(*t.Verb))
+	}
+	builder.WriteString("$")
+	return builder.String()
+}
+
+// jsonPath converts a field path using the protobuf field names, e.g.
+// `secret.secret_id` to the names used in the JSON representation of the
+// request, e.g. `secret.secretId`.
+//
+// Fields not found in the model keep their original name.
+func jsonPath(msg *api.Message, path []string, state *api.APIState) string {
+	if len(path) == 0 || path[0] == "" {
+		return ""
+	}
+	var names []string
+	for _, name := range path {
+		var field *api.Field
+		if msg != nil {
+			if index := slices.IndexFunc(msg.Fields, func(f *api.Field) bool { return f.Name == name }); index != -1 {
+				field = msg.Fields[index]
+			}
+		}
+		if field == nil {
+			names = append(names, name)
+			msg = nil
+			continue
+		}
+		names = append(names, field.JSONName)
+		msg = state.MessageByID[field.TypezID]
+	}
+	return strings.Join(names, ".")
+}
+
+// pageSizeField returns the field used to limit the size of each page, if
+// any.
+func pageSizeField(request *api.Message) *api.Field {
+	for _, f := range request.Fields {
+		if f.Repeated {
+			continue
+		}
+		switch f.Typez {
+		case api.INT32_TYPE, api.UINT32_TYPE, api.INT64_TYPE, api.UINT64_TYPE:
+		default:
+			continue
+		}
+		if f.Name == "page_size" || f.Name == "max_results" {
+			return f
+		}
+	}
+	return nil
+}
@@ -0,0 +1,266 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mock_server
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/googleapis/librarian/internal/sidekick/internal/api"
+)
+
+func testModel(t *testing.T) *api.API {
+	t.Helper()
+	operation := &api.Message{
+		Name:    "Operation",
+		ID:      ".google.longrunning.Operation",
+		Package: "google.longrunning",
+		Fields: []*api.Field{
+			{Name: "name", JSONName: "name", ID: ".google.longrunning.Operation.name", Typez: api.STRING_TYPE},
+		},
+	}
+	getOperationRequest := &api.Message{
+		Name:    "GetOperationRequest",
+		ID:      ".google.longrunning.GetOperationRequest",
+		Package: "google.longrunning",
+		Fields: []*api.Field{
+			{Name: "name", JSONName: "name", ID: ".google.longrunning.GetOperationRequest.name", Typez: api.STRING_TYPE},
+		},
+	}
+	widget := &api.Message{
+		Name:    "Widget",
+		ID:      ".test.v1.Widget",
+		Package: "test.v1",
+		Fields: []*api.Field{
+			{Name: "name", JSONName: "name", ID: ".test.v1.Widget.name", Typez: api.STRING_TYPE},
+			{Name: "display_name", JSONName: "displayName", ID: ".test.v1.Widget.display_name", Typez: api.STRING_TYPE},
+		},
+	}
+	createRequest := &api.Message{
+		Name:    "CreateWidgetRequest",
+		ID:      ".test.v1.CreateWidgetRequest",
+		Package: "test.v1",
+		Fields: []*api.Field{
+			{Name: "parent", JSONName: "parent", ID: ".test.v1.CreateWidgetRequest.parent", Typez: api.STRING_TYPE},
+			{Name: "widget_id", JSONName: "widgetId", ID: ".test.v1.CreateWidgetRequest.widget_id", Typez: api.STRING_TYPE, Behavior: []api.FieldBehavior{api.FIELD_BEHAVIOR_REQUIRED}},
+			{Name: "widget", JSONName: "widget", ID: ".test.v1.CreateWidgetRequest.widget", Typez: api.MESSAGE_TYPE, TypezID: widget.ID, Optional: true, Behavior: []api.FieldBehavior{api.FIELD_BEHAVIOR_REQUIRED}},
+		},
+	}
+	updateRequest := &api.Message{
+		Name:    "UpdateWidgetRequest",
+		ID:      ".test.v1.UpdateWidgetRequest",
+		Package: "test.v1",
+		Fields: []*api.Field{
+			{Name: "widget", JSONName: "widget", ID: ".test.v1.UpdateWidgetRequest.widget", Typez: api.MESSAGE_TYPE, TypezID: widget.ID, Optional: true},
+		},
+	}
+	pageToken := &api.Field{Name: "page_token", JSONName: "pageToken", ID: ".test.v1.ListWidgetsRequest.page_token", Typez: api.STRING_TYPE}
+	listRequest := &api.Message{
+		Name:    "ListWidgetsRequest",
+		ID:      ".test.v1.ListWidgetsRequest",
+		Package: "test.v1",
+		Fields: []*api.Field{
+			{Name: "parent", JSONName: "parent", ID: ".test.v1.ListWidgetsRequest.parent", Typez: api.STRING_TYPE},
+			{Name: "page_size", JSONName: "pageSize", ID: ".test.v1.ListWidgetsRequest.page_size", Typez: api.INT32_TYPE},
+			pageToken,
+		},
+	}
+	nextPageToken := &api.Field{Name: "next_page_token", JSONName: "nextPageToken", ID: ".test.v1.ListWidgetsResponse.next_page_token", Typez: api.STRING_TYPE}
+	widgets := &api.Field{Name: "widgets", JSONName: "widgets", ID: ".test.v1.ListWidgetsResponse.widgets", Typez: api.MESSAGE_TYPE, TypezID: widget.ID, Repeated: true}
+	listResponse := &api.Message{
+		Name:       "ListWidgetsResponse",
+		ID:         ".test.v1.ListWidgetsResponse",
+		Package:    "test.v1",
+		Fields:     []*api.Field{widgets, nextPageToken},
+		Pagination: &api.PaginationInfo{NextPageToken: nextPageToken, PageableItem: widgets},
+	}
+	widgetsPath := func() *api.PathTemplate {
+		return api.NewPathTemplate().
+			WithLiteral("v1").
+			WithVariable(api.NewPathVariable("parent").WithLiteral("projects").WithMatch().WithLiteral("locations").WithMatch()).
+			WithLiteral("widgets")
+	}
+	create := &api.Method{
+		Name:         "CreateWidget",
+		ID:           ".test.v1.WidgetService.CreateWidget",
+		InputTypeID:  createRequest.ID,
+		OutputTypeID: operation.ID,
+		PathInfo: &api.PathInfo{
+			Bindings:      []*api.PathBinding{{Verb: "POST", PathTemplate: widgetsPath()}},
+			BodyFieldPath: "widget",
+		},
+		OperationInfo: &api.OperationInfo{
+			MetadataTypeID: ".google.protobuf.Empty",
+			ResponseTypeID: widget.ID,
+		},
+	}
+	update := &api.Method{
+		Name:         "UpdateWidget",
+		ID:           ".test.v1.WidgetService.UpdateWidget",
+		InputTypeID:  updateRequest.ID,
+		OutputTypeID: widget.ID,
+		PathInfo: &api.PathInfo{
+			Bindings: []*api.PathBinding{{
+				Verb: "PATCH",
+				PathTemplate: api.NewPathTemplate().
+					WithLiteral("v1").
+					WithVariable(api.NewPathVariable("widget", "name").WithLiteral("projects").WithMatch().WithLiteral("widgets").WithMatch()).
+					WithVerb("rename"),
+			}},
+			BodyFieldPath: "*",
+		},
+	}
+	list := &api.Method{
+		Name:         "ListWidgets",
+		ID:           ".test.v1.WidgetService.ListWidgets",
+		InputTypeID:  listRequest.ID,
+		OutputTypeID: listResponse.ID,
+		PathInfo: &api.PathInfo{
+			Bindings: []*api.PathBinding{{Verb: "GET", PathTemplate: widgetsPath()}},
+		},
+		Pagination: pageToken,
+	}
+	watch := &api.Method{
+		Name:                "WatchWidgets",
+		ID:                  ".test.v1.WidgetService.WatchWidgets",
+		InputTypeID:         listRequest.ID,
+		OutputTypeID:        widget.ID,
+		ServerSideStreaming: true,
+		PathInfo: &api.PathInfo{
+			Bindings: []*api.PathBinding{{Verb: "GET", PathTemplate: widgetsPath().WithVerb("watch")}},
+		},
+	}
+	getOperation := &api.Method{
+		Name:         "GetOperation",
+		ID:           ".google.longrunning.Operations.GetOperation",
+		InputTypeID:  getOperationRequest.ID,
+		OutputTypeID: operation.ID,
+		PathInfo: &api.PathInfo{
+			Bindings: []*api.PathBinding{{
+				Verb: "GET",
+				PathTemplate: api.NewPathTemplate().
+					WithLiteral("v1").
+					WithVariable(api.NewPathVariable("name").WithLiteral("projects").WithMatch().WithLiteral("locations").WithMatch().WithLiteral("operations").WithMatch()),
+			}},
+		},
+	}
+	service := &api.Service{
+		Name:        "WidgetService",
+		ID:          ".test.v1.WidgetService",
+		Package:     "test.v1",
+		DefaultHost: "widgets.googleapis.com",
+		Methods:     []*api.Method{create, update, list, watch, getOperation},
+	}
+	model := api.NewTestAPI(
+		[]*api.Message{operation, getOperationRequest, widget, createRequest, updateRequest, listRequest, listResponse},
+		[]*api.Enum{},
+		[]*api.Service{service})
+	model.PackageName = "test.v1"
+	if err := api.CrossReference(model); err != nil {
+		t.Fatal(err)
+	}
+	return model
+}
+
+func TestAnnotateModel(t *testing.T) {
+	model := testModel(t)
+	if err := annotateModel(model, map[string]string{
+		"copyright-year": "2038",
+		"module-path":    "example.com/mock",
+	}); err != nil {
+		t.Fatal(err)
+	}
+	got := model.Codec.(*modelAnnotations)
+	if got.ModulePath != "example.com/mock" || got.GoVersion != defaultGoVersion || !got.CanPoll {
+		t.Errorf("mismatch in model annotations, got=%v", got)
+	}
+	want := []*routeAnnotation{
+		{
+			RPC:            "test.v1.WidgetService.CreateWidget",
+			Verb:           "POST",
+			Pattern:        `^/v1/(projects/[^/]+/locations/[^/]+)/widgets$`,
+			PathFields:     []string{"parent"},
+			BodyField:      "widget",
+			RequiredFields: []string{"parent", "widgetId", "widget"},
+			LongRunning:    true,
+			ResponseType:   "test.v1.Widget",
+			MetadataType:   "google.protobuf.Empty",
+		},
+		{
+			RPC:            "test.v1.WidgetService.UpdateWidget",
+			Verb:           "PATCH",
+			Pattern:        `^/v1/(projects/[^/]+/widgets/[^/]+):rename$`,
+			PathFields:     []string{"widget.name"},
+			BodyField:      "*",
+			RequiredFields: []string{"widget"},
+		},
+		{
+			RPC:                "test.v1.WidgetService.ListWidgets",
+			Verb:               "GET",
+			Pattern:            `^/v1/(projects/[^/]+/locations/[^/]+)/widgets$`,
+			PathFields:         []string{"parent"},
+			RequiredFields:     []string{"parent"},
+			PageTokenField:     "pageToken",
+			PageSizeField:      "pageSize",
+			NextPageTokenField: "nextPageToken",
+			ItemsField:         "widgets",
+		},
+		{
+			RPC:            "google.longrunning.Operations.GetOperation",
+			Verb:           "GET",
+			Pattern:        `^/v1/(projects/[^/]+/locations/[^/]+/operations/[^/]+)$`,
+			PathFields:     []string{"name"},
+			RequiredFields: []string{"name"},
+			GetOperation:   true,
+		},
+	}
+	if diff := cmp.Diff(want, got.Routes); diff != "" {
+		t.Errorf("mismatch in routes (-want, +got):\n%s", diff)
+	}
+}
+
+func TestPathPattern(t *testing.T) {
+	for _, test := range []struct {
+		template *api.PathTemplate
+		want     string
+	}{
+		{
+			template: api.NewPathTemplate().WithLiteral("v1").WithVariable(api.NewPathVariable("name")),
+			want:     `^/v1/([^/]+)$`,
+		},
+		{
+			template: api.NewPathTemplate().
+				WithLiteral("v1").
+				WithVariable(api.NewPathVariable("object").WithLiteral("buckets").WithMatch().WithLiteral("objects").WithMatchRecursive()).
+				WithVerb("compose"),
+			want: `^/v1/(buckets/[^/]+/objects/.+):compose$`,
+		},
+		{
+			template: api.NewPathTemplate().WithLiteral("v1.beta").WithLiteral("widgets"),
+			want:     `^/v1\.beta/widgets$`,
+		},
+	} {
+		if got := pathPattern(test.template); got != test.want {
+			t.Errorf("pathPattern() = %q, want = %q", got, test.want)
+		}
+	}
+}
+
+func TestAnnotateModelBadLicense(t *testing.T) {
+	model := testModel(t)
+	if err := annotateModel(model, map[string]string{"license": "--invalid--"}); err == nil {
+		t.Errorf("expected an error with an invalid license")
+	}
+}
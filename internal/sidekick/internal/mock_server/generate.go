@@ -0,0 +1,55 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package mock_server generates a mock HTTP/JSON server for an API.
+//
+// The generated server is a Go program using only the standard library. It
+// routes the requests using the HTTP bindings of each method, rejects
+// requests without their required fields, and returns canned or scripted
+// responses. Client libraries can use it to run their tests hermetically.
+package mock_server
+
+import (
+	"embed"
+	"path/filepath"
+
+	"github.com/googleapis/librarian/internal/sidekick/internal/api"
+	"github.com/googleapis/librarian/internal/sidekick/internal/config"
+	"github.com/googleapis/librarian/internal/sidekick/internal/language"
+)
+
+//go:embed templates
+var templates embed.FS
+
+// Generate generates the mock server for the model.
+func Generate(model *api.API, outdir string, cfg *config.Config) error {
+	if err := annotateModel(model, cfg.Codec); err != nil {
+		return err
+	}
+	provider := func(name string) (string, error) {
+		contents, err := templates.ReadFile(filepath.ToSlash(name))
+		if err != nil {
+			return "", err
+		}
+		return string(contents), nil
+	}
+	generatedFiles := language.WalkTemplatesDir(templates, "templates")
+	if err := language.GenerateFromModelWithOverrides(outdir, model, provider, generatedFiles, cfg.Codec); err != nil {
+		return err
+	}
+	if cfg.Codec["skip-format"] == "true" {
+		return nil
+	}
+	return language.FormatGoFiles(outdir, generatedFiles)
+}
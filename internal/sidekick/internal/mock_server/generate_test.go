@@ -0,0 +1,241 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mock_server
+
+import (
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/googleapis/librarian/internal/sidekick/internal/config"
+	"github.com/googleapis/librarian/internal/sidekick/internal/parser"
+)
+
+var (
+	testdataDir, _ = filepath.Abs("../../testdata")
+)
+
+func TestFromOpenAPI(t *testing.T) {
+	outDir := t.TempDir()
+	cfg := &config.Config{
+		General: config.GeneralConfig{
+			SpecificationFormat: "openapi",
+			ServiceConfig:       path.Join(testdataDir, "googleapis/google/cloud/secretmanager/v1/secretmanager_v1.yaml"),
+			SpecificationSource: path.Join(testdataDir, "openapi/secretmanager_openapi_v1.json"),
+		},
+		Codec: map[string]string{
+			"copyright-year": "2025",
+		},
+	}
+	model, err := parser.CreateModel(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := Generate(model, outDir, cfg); err != nil {
+		t.Fatal(err)
+	}
+	for _, expected := range []string{"go.mod", "README.md", "main.go", "routes.go", "server.go"} {
+		filename := path.Join(outDir, expected)
+		stat, err := os.Stat(filename)
+		if os.IsNotExist(err) {
+			t.Errorf("missing %s: %s", filename, err)
+			continue
+		}
+		if stat.Mode().Perm()|0666 != 0666 {
+			t.Errorf("generated files should not be executable %s: %o", filename, stat.Mode())
+		}
+	}
+	contents, err := os.ReadFile(path.Join(outDir, "routes.go"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `"google.cloud.secretmanager.v1.SecretManagerService.AccessSecretVersion"`; !strings.Contains(string(contents), want) {
+		t.Errorf("expected %s in the generated routes, got=\n%s", want, contents)
+	}
+	goVet(t, outDir)
+}
+
+// TestGeneratedServer runs requests against the generated server.
+func TestGeneratedServer(t *testing.T) {
+	outDir := t.TempDir()
+	cfg := &config.Config{
+		Codec: map[string]string{
+			"copyright-year": "2025",
+		},
+	}
+	if err := Generate(testModel(t), outDir, cfg); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path.Join(outDir, "server_test.go"), []byte(generatedServerTest), 0666); err != nil {
+		t.Fatal(err)
+	}
+	goTool, err := exec.LookPath("go")
+	if err != nil {
+		t.Skip("skipping test of generated code because go is not installed")
+	}
+	cmd := exec.Command(goTool, "test", "./...")
+	cmd.Dir = outDir
+	cmd.Env = append(os.Environ(), "GOFLAGS=-mod=mod", "GOWORK=off")
+	if output, err := cmd.CombinedOutput(); err != nil {
+		t.Errorf("go test failed on the generated code: %v\n%s", err, output)
+	}
+}
+
+func TestGenerateBadLicense(t *testing.T) {
+	cfg := &config.Config{
+		Codec: map[string]string{"license": "--invalid--"},
+	}
+	if err := Generate(testModel(t), t.TempDir(), cfg); err == nil {
+		t.Errorf("expected an error with an invalid license")
+	}
+}
+
+func goVet(t *testing.T, dir string) {
+	t.Helper()
+	// The generated code only depends on the standard library, so it can be
+	// verified without network access.
+	goTool, err := exec.LookPath("go")
+	if err != nil {
+		t.Skip("skipping build of generated code because go is not installed")
+	}
+	cmd := exec.Command(goTool, "vet", "./...")
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), "GOFLAGS=-mod=mod", "GOWORK=off")
+	if output, err := cmd.CombinedOutput(); err != nil {
+		t.Errorf("go vet failed on the generated code: %v\n%s", err, output)
+	}
+}
+
+// generatedServerTest is added to the generated server to test its behavior.
+const generatedServerTest = `package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func call(t *testing.T, url, method, body string) (int, map[string]any) {
+	t.Helper()
+	request, err := http.NewRequest(method, url, strings.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	response, err := http.DefaultClient.Do(request)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer response.Body.Close()
+	got := map[string]any{}
+	if err := json.NewDecoder(response.Body).Decode(&got); err != nil {
+		t.Fatal(err)
+	}
+	return response.StatusCode, got
+}
+
+func TestRouting(t *testing.T) {
+	server := NewServer()
+	server.SetResponses("test.v1.WidgetService.UpdateWidget", json.RawMessage(` + "`" + `{"displayName": "updated"}` + "`" + `))
+	ts := httptest.NewServer(server)
+	defer ts.Close()
+
+	code, got := call(t, ts.URL+"/v1/projects/p/widgets/w:rename", "PATCH", ` + "`" + `{"widget": {"displayName": "new"}}` + "`" + `)
+	if code != http.StatusOK || got["displayName"] != "updated" {
+		t.Errorf("unexpected response %d %v", code, got)
+	}
+	code, _ = call(t, ts.URL+"/v1/projects/p/widgets/w:unknown", "PATCH", "{}")
+	if code != http.StatusNotFound {
+		t.Errorf("expected NOT_FOUND for unknown paths, got %d", code)
+	}
+}
+
+func TestRequiredFields(t *testing.T) {
+	ts := httptest.NewServer(NewServer())
+	defer ts.Close()
+
+	code, got := call(t, ts.URL+"/v1/projects/p/locations/l/widgets", "POST", "{}")
+	if code != http.StatusBadRequest || !strings.Contains(got["error"].(map[string]any)["message"].(string), "widgetId") {
+		t.Errorf("expected INVALID_ARGUMENT for missing widgetId, got %d %v", code, got)
+	}
+}
+
+func TestScriptedResponses(t *testing.T) {
+	server := NewServer()
+	server.SetResponses("test.v1.WidgetService.UpdateWidget",
+		json.RawMessage(` + "`" + `{"error": {"code": 503, "message": "try again", "status": "UNAVAILABLE"}}` + "`" + `),
+		json.RawMessage(` + "`" + `{"displayName": "ok"}` + "`" + `))
+	ts := httptest.NewServer(server)
+	defer ts.Close()
+
+	for _, want := range []int{http.StatusServiceUnavailable, http.StatusOK, http.StatusOK} {
+		if code, got := call(t, ts.URL+"/v1/projects/p/widgets/w:rename", "PATCH", "{}"); code != want {
+			t.Errorf("expected %d, got %d %v", want, code, got)
+		}
+	}
+}
+
+func TestLongRunningOperation(t *testing.T) {
+	server := NewServer()
+	server.SetResponses("test.v1.WidgetService.CreateWidget", json.RawMessage(` + "`" + `{"name": "projects/p/locations/l/widgets/w"}` + "`" + `))
+	ts := httptest.NewServer(server)
+	defer ts.Close()
+
+	code, got := call(t, ts.URL+"/v1/projects/p/locations/l/widgets?widgetId=w", "POST", "{}")
+	if code != http.StatusOK || got["done"] != false {
+		t.Fatalf("unexpected operation %d %v", code, got)
+	}
+	name := got["name"].(string)
+	if name != "projects/p/locations/l/operations/1" {
+		t.Errorf("unexpected operation name %q", name)
+	}
+	code, got = call(t, ts.URL+"/v1/"+name, "GET", "")
+	if code != http.StatusOK || got["done"] != true {
+		t.Fatalf("unexpected operation %d %v", code, got)
+	}
+	response := got["response"].(map[string]any)
+	if response["@type"] != "type.googleapis.com/test.v1.Widget" || response["name"] != "projects/p/locations/l/widgets/w" {
+		t.Errorf("unexpected operation response %v", response)
+	}
+}
+
+func TestPagination(t *testing.T) {
+	server := NewServer()
+	server.SetResponses("test.v1.WidgetService.ListWidgets", json.RawMessage(` + "`" + `{"widgets": [{"name": "a"}, {"name": "b"}, {"name": "c"}]}` + "`" + `))
+	ts := httptest.NewServer(server)
+	defer ts.Close()
+
+	var names []string
+	token := ""
+	for {
+		_, got := call(t, ts.URL+"/v1/projects/p/locations/l/widgets?pageSize=2&pageToken="+token, "GET", "")
+		for _, w := range got["widgets"].([]any) {
+			names = append(names, w.(map[string]any)["name"].(string))
+		}
+		next, _ := got["nextPageToken"].(string)
+		if next == "" {
+			break
+		}
+		token = next
+	}
+	if strings.Join(names, ",") != "a,b,c" {
+		t.Errorf("unexpected items %v", names)
+	}
+}
+`
@@ -0,0 +1,158 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package options describes and validates the codec options.
+//
+// Each codec declares the options it supports, with their type, default
+// value, and description. Sidekick validates the configuration against this
+// declaration before generating any code, and `sidekick config explain`
+// prints it.
+package options
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Type is the type of the value of an option.
+type Type string
+
+const (
+	// String options accept any value.
+	String Type = "string"
+	// Bool options accept the values supported by [strconv.ParseBool].
+	Bool Type = "bool"
+	// List options accept a comma-separated list of values.
+	List Type = "list"
+)
+
+// Option describes a codec option.
+type Option struct {
+	// The name of the option, e.g. `package-name-override`. Names ending in
+	// a placeholder, such as `proto:<package>`, describe a family of options
+	// with a common prefix.
+	Name        string
+	Type        Type
+	Default     string
+	Description string
+	// If not empty, the option is deprecated, and this explains what to use
+	// instead.
+	Deprecated string
+}
+
+// Matches returns true if `key` is the name of this option, or, for options
+// with a placeholder, if `key` starts with the same prefix.
+func (o Option) Matches(key string) bool {
+	if prefix, _, ok := strings.Cut(o.Name, "<"); ok {
+		return strings.HasPrefix(key, prefix) && len(key) > len(prefix)
+	}
+	return key == o.Name
+}
+
+// Find returns the option matching `key`.
+func Find(schema []Option, key string) (Option, bool) {
+	index := slices.IndexFunc(schema, func(o Option) bool { return o.Matches(key) })
+	if index == -1 {
+		return Option{}, false
+	}
+	return schema[index], true
+}
+
+// Validate checks the codec options for `language` against its schema.
+//
+// Unknown options and invalid values are errors, unless the option is
+// inherited, with the same value, from `inherited`. The top-level
+// configuration is shared by all the libraries in a repository, which may
+// use different codecs, so each codec ignores the options it does not know
+// about. Deprecated options are logged as warnings.
+func Validate(language string, schema []Option, options, inherited map[string]string) error {
+	var errs []error
+	keys := make([]string, 0, len(options))
+	for key := range options {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+	for _, key := range keys {
+		value := options[key]
+		option, ok := Find(schema, key)
+		if !ok {
+			if v, found := inherited[key]; found && v == value {
+				continue
+			}
+			msg := fmt.Sprintf("unknown codec option %q for language %q", key, language)
+			if suggestion := Suggest(schema, key); suggestion != "" {
+				msg += fmt.Sprintf(", did you mean %q?", suggestion)
+			}
+			errs = append(errs, errors.New(msg))
+			continue
+		}
+		if option.Type == Bool {
+			if _, err := strconv.ParseBool(value); err != nil {
+				errs = append(errs, fmt.Errorf("cannot convert `%s` value %q to boolean: %w", key, value, err))
+				continue
+			}
+		}
+		if option.Deprecated != "" {
+			slog.Warn("deprecated codec option", "option", key, "language", language, "replacement", option.Deprecated)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Suggest returns the name of the option closest to `key`, if it is close
+// enough to be a typo.
+func Suggest(schema []Option, key string) string {
+	best := ""
+	bestDistance := len(key)/3 + 1
+	for _, o := range schema {
+		name := o.Name
+		if prefix, _, ok := strings.Cut(name, "<"); ok {
+			// Compare the prefix of both names, e.g. `protos:a.b` with
+			// `proto:<package>`.
+			name = prefix
+			if _, suffix, ok := strings.Cut(key, ":"); ok {
+				name += suffix
+			}
+		}
+		if d := distance(key, name); d < bestDistance {
+			best, bestDistance = o.Name, d
+		}
+	}
+	return best
+}
+
+// distance returns the Levenshtein distance between two strings.
+func distance(a, b string) int {
+	previous := make([]int, len(b)+1)
+	current := make([]int, len(b)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		current[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous, current = current, previous
+	}
+	return previous[len(b)]
+}
@@ -0,0 +1,150 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package options
+
+import (
+	"strings"
+	"testing"
+)
+
+var testSchema = []Option{
+	{Name: "package-name-override", Type: String, Description: "The package name."},
+	{Name: "skip-format", Type: Bool, Default: "false", Description: "Skip formatting."},
+	{Name: "proto:<package>", Type: String, Description: "Maps protobuf packages."},
+	{Name: "old-option", Type: String, Description: "An old option.", Deprecated: "use `package-name-override`"},
+}
+
+func TestMatches(t *testing.T) {
+	for _, test := range []struct {
+		name string
+		key  string
+		want bool
+	}{
+		{"skip-format", "skip-format", true},
+		{"skip-format", "skip-formats", false},
+		{"proto:<package>", "proto:google.protobuf", true},
+		{"proto:<package>", "proto:", false},
+		{"proto:<package>", "protos:google.protobuf", false},
+	} {
+		option := Option{Name: test.name}
+		if got := option.Matches(test.key); got != test.want {
+			t.Errorf("Option{Name: %q}.Matches(%q) = %v, want = %v", test.name, test.key, got, test.want)
+		}
+	}
+}
+
+func TestFind(t *testing.T) {
+	got, ok := Find(testSchema, "proto:google.type")
+	if !ok || got.Name != "proto:<package>" {
+		t.Errorf("Find() = %v, %v, want the `proto:<package>` option", got, ok)
+	}
+	if got, ok := Find(testSchema, "unknown"); ok {
+		t.Errorf("Find() = %v, %v, want no option", got, ok)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	options := map[string]string{
+		"package-name-override": "google-cloud-test",
+		"skip-format":           "true",
+		"proto:google.protobuf": "example.com/protobuf",
+		"old-option":            "deprecated, but valid",
+		"shared-option":         "used by other languages",
+	}
+	inherited := map[string]string{
+		"shared-option": "used by other languages",
+	}
+	if err := Validate("test", testSchema, options, inherited); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestValidateErrors(t *testing.T) {
+	for _, test := range []struct {
+		options   map[string]string
+		inherited map[string]string
+		want      string
+	}{
+		{
+			options: map[string]string{"skip-formatt": "true"},
+			want:    `unknown codec option "skip-formatt" for language "test", did you mean "skip-format"?`,
+		},
+		{
+			options: map[string]string{"protos:google.type": "example.com/type"},
+			want:    `did you mean "proto:<package>"?`,
+		},
+		{
+			options: map[string]string{"completely-unrelated": "value"},
+			want:    `unknown codec option "completely-unrelated" for language "test"`,
+		},
+		{
+			options: map[string]string{"skip-format": "--invalid--"},
+			want:    "cannot convert `skip-format` value",
+		},
+		{
+			// Inherited options are validated if the codec knows about them.
+			options:   map[string]string{"skip-format": "--invalid--"},
+			inherited: map[string]string{"skip-format": "--invalid--"},
+			want:      "cannot convert `skip-format` value",
+		},
+		{
+			// A local override of an inherited option is validated.
+			options:   map[string]string{"shared-option": "local"},
+			inherited: map[string]string{"shared-option": "root"},
+			want:      `unknown codec option "shared-option"`,
+		},
+	} {
+		err := Validate("test", testSchema, test.options, test.inherited)
+		if err == nil {
+			t.Errorf("expected an error for %v", test.options)
+			continue
+		}
+		if !strings.Contains(err.Error(), test.want) {
+			t.Errorf("mismatch in error for %v, want=%q, got=%q", test.options, test.want, err)
+		}
+	}
+}
+
+func TestSuggest(t *testing.T) {
+	for _, test := range []struct {
+		key  string
+		want string
+	}{
+		{"package-name-overide", "package-name-override"},
+		{"skipformat", "skip-format"},
+		{"prto:google.type", "proto:<package>"},
+		{"license", ""},
+	} {
+		if got := Suggest(testSchema, test.key); got != test.want {
+			t.Errorf("Suggest(%q) = %q, want = %q", test.key, got, test.want)
+		}
+	}
+}
+
+func TestDistance(t *testing.T) {
+	for _, test := range []struct {
+		a, b string
+		want int
+	}{
+		{"", "", 0},
+		{"abc", "", 3},
+		{"kitten", "sitting", 3},
+		{"skip-format", "skip-format", 0},
+	} {
+		if got := distance(test.a, test.b); got != test.want {
+			t.Errorf("distance(%q, %q) = %d, want = %d", test.a, test.b, got, test.want)
+		}
+	}
+}
@@ -0,0 +1,153 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package parser
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/googleapis/librarian/internal/sidekick/internal/api"
+)
+
+// grpcServiceConfig is the subset of the gRPC service config used by sidekick.
+//
+// The format is documented in:
+//
+//	https://github.com/grpc/grpc/blob/master/doc/service_config.md
+type grpcServiceConfig struct {
+	MethodConfig []grpcMethodConfig `json:"methodConfig"`
+}
+
+type grpcMethodConfig struct {
+	Name        []grpcMethodName `json:"name"`
+	Timeout     string           `json:"timeout"`
+	RetryPolicy *grpcRetryPolicy `json:"retryPolicy"`
+}
+
+// grpcMethodName selects the methods for a `grpcMethodConfig`. If `Method` is
+// empty the configuration applies to all the methods in `Service`. If both
+// are empty the configuration applies to all the methods.
+type grpcMethodName struct {
+	Service string `json:"service"`
+	Method  string `json:"method"`
+}
+
+type grpcRetryPolicy struct {
+	MaxAttempts          int      `json:"maxAttempts"`
+	InitialBackoff       string   `json:"initialBackoff"`
+	MaxBackoff           string   `json:"maxBackoff"`
+	BackoffMultiplier    float64  `json:"backoffMultiplier"`
+	RetryableStatusCodes []string `json:"retryableStatusCodes"`
+}
+
+func readGrpcServiceConfig(filename string) (*grpcServiceConfig, error) {
+	contents, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, fmt.Errorf("error reading gRPC service config [%s]: %w", filename, err)
+	}
+	cfg := &grpcServiceConfig{}
+	if err := json.Unmarshal(contents, cfg); err != nil {
+		return nil, fmt.Errorf("error parsing gRPC service config [%s]: %w", filename, err)
+	}
+	return cfg, nil
+}
+
+// updateMethodRetryPolicies sets the default timeout and retry policy for each
+// method in the model.
+//
+// A configuration naming the method takes precedence over a configuration for
+// all the methods in the service, which takes precedence over the wildcard
+// configuration. Mixin methods are matched using their source service, as
+// that is the service name used in the gRPC requests.
+func updateMethodRetryPolicies(cfg *grpcServiceConfig, model *api.API) error {
+	if cfg == nil {
+		return nil
+	}
+	policies := map[grpcMethodName]*api.RetryPolicy{}
+	for _, mc := range cfg.MethodConfig {
+		policy, err := makeRetryPolicy(mc)
+		if err != nil {
+			return err
+		}
+		for _, name := range mc.Name {
+			if name.Service == "" && name.Method != "" {
+				return fmt.Errorf("invalid gRPC service config, method %q without a service", name.Method)
+			}
+			if _, ok := policies[name]; ok {
+				return fmt.Errorf("invalid gRPC service config, duplicate configuration for %v", name)
+			}
+			policies[name] = policy
+		}
+	}
+	for _, s := range model.Services {
+		for _, m := range s.Methods {
+			serviceID := m.SourceServiceID
+			if serviceID == "" {
+				serviceID = s.ID
+			}
+			service := strings.TrimPrefix(serviceID, ".")
+			for _, name := range []grpcMethodName{{service, m.Name}, {service, ""}, {}} {
+				if policy, ok := policies[name]; ok {
+					m.RetryPolicy = policy
+					break
+				}
+			}
+		}
+	}
+	return nil
+}
+
+func makeRetryPolicy(mc grpcMethodConfig) (*api.RetryPolicy, error) {
+	var err error
+	policy := &api.RetryPolicy{}
+	if policy.Timeout, err = parseGrpcDuration(mc.Timeout); err != nil {
+		return nil, err
+	}
+	rp := mc.RetryPolicy
+	if rp == nil {
+		return policy, nil
+	}
+	policy.MaxAttempts = rp.MaxAttempts
+	policy.BackoffMultiplier = rp.BackoffMultiplier
+	if policy.InitialBackoff, err = parseGrpcDuration(rp.InitialBackoff); err != nil {
+		return nil, err
+	}
+	if policy.MaxBackoff, err = parseGrpcDuration(rp.MaxBackoff); err != nil {
+		return nil, err
+	}
+	for _, code := range rp.RetryableStatusCodes {
+		policy.RetryableCodes = append(policy.RetryableCodes, strings.ToUpper(code))
+	}
+	return policy, nil
+}
+
+// parseGrpcDuration parses durations in the JSON format for
+// `google.protobuf.Duration`, e.g. `60s` or `0.1s`.
+func parseGrpcDuration(value string) (time.Duration, error) {
+	if value == "" {
+		return 0, nil
+	}
+	if !strings.HasSuffix(value, "s") {
+		return 0, fmt.Errorf("invalid duration %q in gRPC service config, must end in `s`", value)
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid duration %q in gRPC service config: %w", value, err)
+	}
+	return d, nil
+}
@@ -0,0 +1,204 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package parser
+
+import (
+	"os"
+	"path"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/googleapis/librarian/internal/sidekick/internal/api"
+	"github.com/googleapis/librarian/internal/sidekick/internal/config"
+)
+
+const secretManagerGrpcConfigRelative = "google/cloud/secretmanager/v1/secretmanager_grpc_service_config.json"
+
+func TestReadGrpcServiceConfig(t *testing.T) {
+	got, err := readGrpcServiceConfig(path.Join(testdataDir, "googleapis", secretManagerGrpcConfigRelative))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := &grpcServiceConfig{
+		MethodConfig: []grpcMethodConfig{
+			{
+				Name:    []grpcMethodName{{Service: "google.cloud.secretmanager.v1.SecretManagerService", Method: "AccessSecretVersion"}},
+				Timeout: "60s",
+				RetryPolicy: &grpcRetryPolicy{
+					MaxAttempts:          5,
+					InitialBackoff:       "1s",
+					MaxBackoff:           "60s",
+					BackoffMultiplier:    1.3,
+					RetryableStatusCodes: []string{"UNAVAILABLE", "RESOURCE_EXHAUSTED"},
+				},
+			},
+			{
+				Name:    []grpcMethodName{{Service: "google.cloud.secretmanager.v1.SecretManagerService"}},
+				Timeout: "60s",
+			},
+		},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("mismatch (-want, +got):\n%s", diff)
+	}
+}
+
+func TestReadGrpcServiceConfigErrors(t *testing.T) {
+	if _, err := readGrpcServiceConfig(path.Join(t.TempDir(), "missing.json")); err == nil {
+		t.Errorf("expected an error for a missing file")
+	}
+	filename := path.Join(t.TempDir(), "bad.json")
+	if err := os.WriteFile(filename, []byte("{ not json"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := readGrpcServiceConfig(filename); err == nil {
+		t.Errorf("expected an error for invalid JSON")
+	}
+}
+
+func TestUpdateMethodRetryPolicies(t *testing.T) {
+	method := &api.Method{Name: "Method", ID: ".test.Service.Method"}
+	other := &api.Method{Name: "Other", ID: ".test.Service.Other"}
+	mixin := &api.Method{Name: "GetOperation", ID: ".test.Service.GetOperation", SourceServiceID: ".google.longrunning.Operations"}
+	unrelated := &api.Method{Name: "Method", ID: ".test.Unrelated.Method"}
+	model := api.NewTestAPI(nil, nil, []*api.Service{
+		{Name: "Service", ID: ".test.Service", Package: "test", Methods: []*api.Method{method, other, mixin}},
+		{Name: "Unrelated", ID: ".test.Unrelated", Package: "test", Methods: []*api.Method{unrelated}},
+	})
+	cfg := &grpcServiceConfig{
+		MethodConfig: []grpcMethodConfig{
+			{
+				Name:    []grpcMethodName{{}},
+				Timeout: "5s",
+			},
+			{
+				Name:    []grpcMethodName{{Service: "test.Service"}, {Service: "google.longrunning.Operations"}},
+				Timeout: "30s",
+			},
+			{
+				Name:    []grpcMethodName{{Service: "test.Service", Method: "Method"}},
+				Timeout: "60s",
+				RetryPolicy: &grpcRetryPolicy{
+					MaxAttempts:          5,
+					InitialBackoff:       "0.1s",
+					MaxBackoff:           "10s",
+					BackoffMultiplier:    2,
+					RetryableStatusCodes: []string{"unavailable"},
+				},
+			},
+		},
+	}
+	if err := updateMethodRetryPolicies(cfg, model); err != nil {
+		t.Fatal(err)
+	}
+	want := &api.RetryPolicy{
+		Timeout:           60 * time.Second,
+		MaxAttempts:       5,
+		InitialBackoff:    100 * time.Millisecond,
+		MaxBackoff:        10 * time.Second,
+		BackoffMultiplier: 2,
+		RetryableCodes:    []string{"UNAVAILABLE"},
+	}
+	if diff := cmp.Diff(want, method.RetryPolicy); diff != "" {
+		t.Errorf("mismatch (-want, +got):\n%s", diff)
+	}
+	if diff := cmp.Diff(&api.RetryPolicy{Timeout: 30 * time.Second}, other.RetryPolicy); diff != "" {
+		t.Errorf("mismatch (-want, +got):\n%s", diff)
+	}
+	if diff := cmp.Diff(&api.RetryPolicy{Timeout: 30 * time.Second}, mixin.RetryPolicy); diff != "" {
+		t.Errorf("mismatch (-want, +got):\n%s", diff)
+	}
+	if diff := cmp.Diff(&api.RetryPolicy{Timeout: 5 * time.Second}, unrelated.RetryPolicy); diff != "" {
+		t.Errorf("mismatch (-want, +got):\n%s", diff)
+	}
+}
+
+func TestUpdateMethodRetryPoliciesErrors(t *testing.T) {
+	for _, test := range []struct {
+		cfg  *grpcServiceConfig
+		want string
+	}{
+		{
+			cfg: &grpcServiceConfig{MethodConfig: []grpcMethodConfig{
+				{Name: []grpcMethodName{{Method: "Method"}}},
+			}},
+			want: "without a service",
+		},
+		{
+			cfg: &grpcServiceConfig{MethodConfig: []grpcMethodConfig{
+				{Name: []grpcMethodName{{Service: "test.Service"}}},
+				{Name: []grpcMethodName{{Service: "test.Service"}}},
+			}},
+			want: "duplicate configuration",
+		},
+		{
+			cfg: &grpcServiceConfig{MethodConfig: []grpcMethodConfig{
+				{Name: []grpcMethodName{{}}, Timeout: "60"},
+			}},
+			want: "must end in `s`",
+		},
+		{
+			cfg: &grpcServiceConfig{MethodConfig: []grpcMethodConfig{
+				{Name: []grpcMethodName{{}}, RetryPolicy: &grpcRetryPolicy{InitialBackoff: "abcs"}},
+			}},
+			want: "invalid duration",
+		},
+	} {
+		model := api.NewTestAPI(nil, nil, nil)
+		err := updateMethodRetryPolicies(test.cfg, model)
+		if err == nil || !strings.Contains(err.Error(), test.want) {
+			t.Errorf("expected an error containing %q, got=%v", test.want, err)
+		}
+	}
+}
+
+func TestCreateModelWithGrpcServiceConfig(t *testing.T) {
+	cfg := &config.Config{
+		General: config.GeneralConfig{
+			SpecificationFormat: "openapi",
+			ServiceConfig:       secretManagerYamlFullPath,
+			GrpcServiceConfig:   secretManagerGrpcConfigRelative,
+			SpecificationSource: path.Join(testdataDir, "openapi/secretmanager_openapi_v1.json"),
+		},
+		Source: map[string]string{
+			"googleapis-root": path.Join(testdataDir, "googleapis"),
+		},
+	}
+	model, err := CreateModel(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	access, ok := model.State.MethodByID[".google.cloud.secretmanager.v1.SecretManagerService.AccessSecretVersion"]
+	if !ok {
+		t.Fatalf("missing method AccessSecretVersion")
+	}
+	if access.RetryPolicy == nil || access.RetryPolicy.MaxAttempts != 5 {
+		t.Errorf("mismatch in AccessSecretVersion retry policy, got=%v", access.RetryPolicy)
+	}
+	list, ok := model.State.MethodByID[".google.cloud.secretmanager.v1.SecretManagerService.ListSecrets"]
+	if !ok {
+		t.Fatalf("missing method ListSecrets")
+	}
+	if diff := cmp.Diff(&api.RetryPolicy{Timeout: 60 * time.Second}, list.RetryPolicy); diff != "" {
+		t.Errorf("mismatch (-want, +got):\n%s", diff)
+	}
+
+	cfg.General.GrpcServiceConfig = "--invalid--"
+	if _, err := CreateModel(cfg); err == nil {
+		t.Errorf("expected an error with a missing gRPC service config")
+	}
+}
@@ -125,6 +125,7 @@ func makeAPIForOpenAPI(serviceConfig *serviceconfig.Service, model *libopenapi.D
 	if err != nil {
 		return nil, err
 	}
+	applyServiceConfigRules(serviceConfig, result)
 	updateMethodPagination(result)
 	updateAutoPopulatedFields(serviceConfig, result)
 	return result, nil
@@ -42,6 +42,15 @@ func CreateModel(config *config.Config) (*api.API, error) {
 	if err != nil {
 		return nil, err
 	}
+	if config.General.GrpcServiceConfig != "" {
+		cfg, err := readGrpcServiceConfig(findServiceConfigPath(config.General.GrpcServiceConfig, config.Source))
+		if err != nil {
+			return nil, err
+		}
+		if err := updateMethodRetryPolicies(cfg, model); err != nil {
+			return nil, err
+		}
+	}
 	api.LabelRecursiveFields(model)
 	if err := api.CrossReference(model); err != nil {
 		return nil, err
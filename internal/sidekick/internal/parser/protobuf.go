@@ -331,6 +331,7 @@ func makeAPIForProtobuf(serviceConfig *serviceconfig.Service, req *pluginpb.Code
 		result.Name = strings.TrimSuffix(serviceConfig.Name, ".googleapis.com")
 	}
 	updatePackageName(result)
+	applyServiceConfigRules(serviceConfig, result)
 	updateMethodPagination(result)
 	updateAutoPopulatedFields(serviceConfig, result)
 	return result
@@ -17,9 +17,12 @@ package parser
 import (
 	"encoding/json"
 	"fmt"
+	"log/slog"
 	"os"
 	"path"
+	"strings"
 
+	"github.com/googleapis/librarian/internal/sidekick/internal/api"
 	"github.com/googleapis/librarian/internal/sidekick/internal/config"
 	"google.golang.org/genproto/googleapis/api/serviceconfig"
 	"google.golang.org/protobuf/encoding/protojson"
@@ -76,3 +79,97 @@ func findServiceConfigPath(serviceConfigFile string, options map[string]string)
 	// elsewhere.
 	return serviceConfigFile
 }
+
+// applyServiceConfigRules applies the `documentation.rules` and `http.rules`
+// from the service config to the elements in the model.
+//
+// Rules for the mixin services are applied as the mixin methods are created,
+// see `applyServiceConfigMethodOverrides()`. Rules with a wildcard selector
+// are ignored, they are typically used for deprecation notices and not for
+// documentation or HTTP bindings.
+func applyServiceConfigRules(serviceConfig *serviceconfig.Service, model *api.API) {
+	for _, rule := range serviceConfig.GetDocumentation().GetRules() {
+		selector, ok := ruleSelector(rule.GetSelector())
+		if !ok || rule.GetDescription() == "" {
+			continue
+		}
+		if !setDocumentation(model.State, selector, rule.GetDescription()) {
+			slog.Warn("documentation rule selector matches no element", "selector", rule.GetSelector())
+		}
+	}
+	for _, rule := range serviceConfig.GetHttp().GetRules() {
+		selector, ok := ruleSelector(rule.GetSelector())
+		if !ok {
+			continue
+		}
+		method, ok := model.State.MethodByID[selector]
+		if !ok {
+			slog.Warn("http rule selector matches no method", "selector", rule.GetSelector())
+			continue
+		}
+		pathInfo, err := processRule(rule, model.State, method.InputTypeID)
+		if err != nil {
+			slog.Error("unsupported http rule", "method", method.ID, "rule", rule, "error", err)
+			continue
+		}
+		method.PathInfo = pathInfo
+	}
+}
+
+// ruleSelector returns the element ID for a rule selector, and false if the
+// rule should be skipped.
+func ruleSelector(selector string) (string, bool) {
+	selector = strings.TrimPrefix(selector, ".")
+	if strings.HasSuffix(selector, "*") {
+		return "", false
+	}
+	for _, mixin := range []string{locationService, iamService, longrunningService} {
+		if strings.HasPrefix(selector, mixin+".") {
+			return "", false
+		}
+	}
+	return "." + selector, true
+}
+
+// setDocumentation replaces the documentation of the element with ID
+// `selector`. Returns false if there is no such element.
+func setDocumentation(state *api.APIState, selector, documentation string) bool {
+	if s, ok := state.ServiceByID[selector]; ok {
+		s.Documentation = documentation
+		return true
+	}
+	if m, ok := state.MethodByID[selector]; ok {
+		m.Documentation = documentation
+		return true
+	}
+	if m, ok := state.MessageByID[selector]; ok {
+		m.Documentation = documentation
+		return true
+	}
+	if e, ok := state.EnumByID[selector]; ok {
+		e.Documentation = documentation
+		return true
+	}
+	index := strings.LastIndex(selector, ".")
+	if index == -1 {
+		return false
+	}
+	parentID, name := selector[:index], selector[index+1:]
+	if m, ok := state.MessageByID[parentID]; ok {
+		for _, f := range m.Fields {
+			if f.Name == name {
+				f.Documentation = documentation
+				return true
+			}
+		}
+	}
+	if e, ok := state.EnumByID[parentID]; ok {
+		for _, v := range e.Values {
+			if v.Name == name {
+				v.Documentation = documentation
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -19,6 +19,7 @@ import (
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/googleapis/librarian/internal/sidekick/internal/api"
 	"github.com/googleapis/librarian/internal/sidekick/internal/sample"
 	"google.golang.org/genproto/googleapis/api/annotations"
 	"google.golang.org/genproto/googleapis/api/serviceconfig"
@@ -48,3 +49,99 @@ func TestReadServiceConfig(t *testing.T) {
 		t.Errorf("mismatch (-want +got):\n%s", diff)
 	}
 }
+
+func TestApplyServiceConfigRules(t *testing.T) {
+	request := &api.Message{
+		Name:    "GetWidgetRequest",
+		ID:      ".test.v1.GetWidgetRequest",
+		Package: "test.v1",
+		Fields: []*api.Field{
+			{Name: "name", ID: ".test.v1.GetWidgetRequest.name", Typez: api.STRING_TYPE},
+		},
+	}
+	state := &api.Enum{
+		Name:    "State",
+		ID:      ".test.v1.State",
+		Package: "test.v1",
+		Values:  []*api.EnumValue{{Name: "ACTIVE", Number: 1}},
+	}
+	method := &api.Method{
+		Name:        "GetWidget",
+		ID:          ".test.v1.WidgetService.GetWidget",
+		InputTypeID: request.ID,
+		PathInfo:    &api.PathInfo{},
+	}
+	service := &api.Service{
+		Name:    "WidgetService",
+		ID:      ".test.v1.WidgetService",
+		Package: "test.v1",
+		Methods: []*api.Method{method},
+	}
+	model := api.NewTestAPI([]*api.Message{request}, []*api.Enum{state}, []*api.Service{service})
+
+	sc := &serviceconfig.Service{
+		Documentation: &serviceconfig.Documentation{
+			Rules: []*serviceconfig.DocumentationRule{
+				{Selector: "test.v1.WidgetService", Description: "Service docs."},
+				{Selector: "test.v1.WidgetService.GetWidget", Description: "Method docs."},
+				{Selector: "test.v1.GetWidgetRequest", Description: "Message docs."},
+				{Selector: "test.v1.GetWidgetRequest.name", Description: "Field docs."},
+				{Selector: "test.v1.State", Description: "Enum docs."},
+				{Selector: "test.v1.State.ACTIVE", Description: "Value docs."},
+				{Selector: "test.v1.*", Description: "Ignored wildcard."},
+				{Selector: "test.v1.Missing", Description: "Matches nothing."},
+				{Selector: "google.cloud.location.Locations.GetLocation", Description: "Ignored mixin."},
+			},
+		},
+		Http: &annotations.Http{
+			Rules: []*annotations.HttpRule{
+				{
+					Selector: "test.v1.WidgetService.GetWidget",
+					Pattern:  &annotations.HttpRule_Get{Get: "/v1/{name=widgets/*}"},
+				},
+				{
+					Selector: "test.v1.WidgetService.Missing",
+					Pattern:  &annotations.HttpRule_Get{Get: "/v1/missing"},
+				},
+			},
+		},
+	}
+	applyServiceConfigRules(sc, model)
+
+	for _, test := range []struct {
+		name string
+		got  string
+		want string
+	}{
+		{"service", service.Documentation, "Service docs."},
+		{"method", method.Documentation, "Method docs."},
+		{"message", request.Documentation, "Message docs."},
+		{"field", request.Fields[0].Documentation, "Field docs."},
+		{"enum", state.Documentation, "Enum docs."},
+		{"enum value", state.Values[0].Documentation, "Value docs."},
+	} {
+		if test.got != test.want {
+			t.Errorf("mismatch in %s documentation, want=%q, got=%q", test.name, test.want, test.got)
+		}
+	}
+	want := &api.PathInfo{
+		Bindings: []*api.PathBinding{
+			{
+				Verb: "GET",
+				PathTemplate: api.NewPathTemplate().
+					WithLiteral("v1").
+					WithVariable(api.NewPathVariable("name").WithLiteral("widgets").WithMatch()),
+				QueryParameters: map[string]bool{},
+			},
+		},
+		BodyFieldPath: "",
+	}
+	if diff := cmp.Diff(want, method.PathInfo); diff != "" {
+		t.Errorf("mismatch (-want, +got):\n%s", diff)
+	}
+}
+
+func TestApplyServiceConfigRulesNil(t *testing.T) {
+	model := api.NewTestAPI(nil, nil, nil)
+	applyServiceConfigRules(nil, model)
+}
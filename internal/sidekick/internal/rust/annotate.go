@@ -18,6 +18,7 @@ import (
 	"fmt"
 	"log/slog"
 	"slices"
+	"strconv"
 	"strings"
 
 	"github.com/googleapis/librarian/internal/sidekick/internal/api"
@@ -35,6 +36,7 @@ type modelAnnotations struct {
 	ExternPackages   []string
 	HasLROs          bool
 	CopyrightYear    string
+	CopyrightHolder  string
 	BoilerPlate      []string
 	DefaultHost      string
 	DefaultHostShort string
@@ -124,6 +126,12 @@ func (s *serviceAnnotations) HasLROs() bool {
 	return len(s.LROTypes) > 0
 }
 
+// HasRetryPolicies returns true if any method in this service has default
+// request options from the gRPC service config.
+func (s *serviceAnnotations) HasRetryPolicies() bool {
+	return slices.ContainsFunc(s.Methods, func(m *api.Method) bool { return m.RetryPolicy != nil })
+}
+
 // FeatureName returns the feature name for the service.
 func (a *serviceAnnotations) FeatureName() string {
 	return strcase.ToKebab(a.ModuleName)
@@ -202,6 +210,63 @@ type methodAnnotation struct {
 	HasVeneer           bool
 	Attributes          []string
 	RoutingRequired     bool
+	// RetryPolicy contains the default request options of the method, from
+	// the gRPC service config, if any.
+	RetryPolicy *retryPolicyAnnotation
+}
+
+// retryPolicyAnnotation contains the default attempt timeout, retry and
+// backoff policies of a method.
+//
+// The transport applies these defaults only if neither the request options nor
+// the client configuration set them. The Rust client libraries follow
+// [AIP-194], which only retries `UNAVAILABLE` errors. Methods whose policy does
+// not list `UNAVAILABLE` as retryable keep the retry policy of the client.
+//
+// [AIP-194]: https://google.aip.dev/194
+type retryPolicyAnnotation struct {
+	// TimeoutMillis is the default attempt timeout, zero if not set.
+	TimeoutMillis int64
+	// Retryable is true if the method retries `UNAVAILABLE` errors.
+	Retryable bool
+	// MaxAttempts is the maximum number of attempts, including the original
+	// request.
+	MaxAttempts int
+	// InitialBackoffMillis is the delay before the first retry.
+	InitialBackoffMillis int64
+	// MaxBackoffMillis is the maximum delay between retries.
+	MaxBackoffMillis int64
+	// BackoffScaling is the growth factor for the delay between retries, as
+	// a Rust `f64` literal.
+	BackoffScaling string
+}
+
+// HasTimeout returns true if the method has a default attempt timeout.
+func (p *retryPolicyAnnotation) HasTimeout() bool {
+	return p.TimeoutMillis > 0
+}
+
+// HasBackoff returns true if the method has a default backoff policy.
+func (p *retryPolicyAnnotation) HasBackoff() bool {
+	return p.Retryable && p.InitialBackoffMillis > 0 && p.MaxBackoffMillis > 0
+}
+
+func annotateRetryPolicy(policy *api.RetryPolicy) *retryPolicyAnnotation {
+	if policy == nil {
+		return nil
+	}
+	scaling := strconv.FormatFloat(policy.BackoffMultiplier, 'f', -1, 64)
+	if !strings.ContainsAny(scaling, ".e") {
+		scaling += ".0"
+	}
+	return &retryPolicyAnnotation{
+		TimeoutMillis:        policy.Timeout.Milliseconds(),
+		Retryable:            slices.Contains(policy.RetryableCodes, "UNAVAILABLE") && policy.MaxAttempts > 1,
+		MaxAttempts:          policy.MaxAttempts,
+		InitialBackoffMillis: policy.InitialBackoff.Milliseconds(),
+		MaxBackoffMillis:     policy.MaxBackoff.Milliseconds(),
+		BackoffScaling:       scaling,
+	}
 }
 
 type pathInfoAnnotation struct {
@@ -508,6 +573,10 @@ func annotateModel(model *api.API, codec *codec) *modelAnnotations {
 		}
 		return defaultHost[:idx]
 	}()
+	header := codec.licenseHeader
+	if header == nil {
+		header = license.DefaultHeader()
+	}
 	ann := &modelAnnotations{
 		PackageName:      packageName,
 		PackageNamespace: packageNamespace,
@@ -517,7 +586,8 @@ func annotateModel(model *api.API, codec *codec) *modelAnnotations {
 		ExternPackages:   externPackages(codec.extraPackages),
 		HasLROs:          hasLROs,
 		CopyrightYear:    codec.generationYear,
-		BoilerPlate: append(license.LicenseHeaderBulk(),
+		CopyrightHolder:  header.Holder,
+		BoilerPlate: append(header.Bulk,
 			"",
 			" Code generated by sidekick. DO NOT EDIT."),
 		DefaultHost:             defaultHost,
@@ -727,6 +797,7 @@ func (c *codec) annotateMethod(m *api.Method, s *api.Service, state *api.APIStat
 		ReturnType:          returnType,
 		HasVeneer:           c.hasVeneer,
 		RoutingRequired:     c.routingRequired,
+		RetryPolicy:         annotateRetryPolicy(m.RetryPolicy),
 	}
 	if annotation.Name == "clone" {
 		// Some methods look too similar to standard Rust traits. Clippy makes
@@ -17,6 +17,7 @@ package rust
 import (
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/go-cmp/cmp/cmpopts"
@@ -45,6 +46,7 @@ func TestPackageNames(t *testing.T) {
 		RequiredPackages:   []string{},
 		ExternPackages:     []string{},
 		CopyrightYear:      "2035",
+		CopyrightHolder:    "Google LLC",
 		Services:           []*api.Service{},
 		NameToLower:        "workflows-v1",
 		PerServiceFeatures: false,
@@ -1800,3 +1802,131 @@ func TestRoutingRequired(t *testing.T) {
 		t.Errorf("codec setting `routing-required` not respected")
 	}
 }
+
+func TestRetryPolicyAnnotation(t *testing.T) {
+	for _, test := range []struct {
+		name   string
+		policy *api.RetryPolicy
+		want   *retryPolicyAnnotation
+	}{
+		{
+			name: "no policy",
+		},
+		{
+			name: "retryable",
+			policy: &api.RetryPolicy{
+				Timeout:           60 * time.Second,
+				MaxAttempts:       5,
+				InitialBackoff:    100 * time.Millisecond,
+				MaxBackoff:        60 * time.Second,
+				BackoffMultiplier: 1.3,
+				RetryableCodes:    []string{"UNAVAILABLE", "RESOURCE_EXHAUSTED"},
+			},
+			want: &retryPolicyAnnotation{
+				TimeoutMillis:        60000,
+				Retryable:            true,
+				MaxAttempts:          5,
+				InitialBackoffMillis: 100,
+				MaxBackoffMillis:     60000,
+				BackoffScaling:       "1.3",
+			},
+		},
+		{
+			name: "integer multiplier",
+			policy: &api.RetryPolicy{
+				MaxAttempts:       3,
+				InitialBackoff:    time.Second,
+				MaxBackoff:        10 * time.Second,
+				BackoffMultiplier: 2,
+				RetryableCodes:    []string{"UNAVAILABLE"},
+			},
+			want: &retryPolicyAnnotation{
+				Retryable:            true,
+				MaxAttempts:          3,
+				InitialBackoffMillis: 1000,
+				MaxBackoffMillis:     10000,
+				BackoffScaling:       "2.0",
+			},
+		},
+		{
+			name: "not retryable under AIP-194",
+			policy: &api.RetryPolicy{
+				MaxAttempts:    3,
+				RetryableCodes: []string{"DEADLINE_EXCEEDED"},
+			},
+			want: &retryPolicyAnnotation{
+				MaxAttempts:    3,
+				BackoffScaling: "0.0",
+			},
+		},
+		{
+			name:   "timeout only",
+			policy: &api.RetryPolicy{Timeout: 30 * time.Second},
+			want: &retryPolicyAnnotation{
+				TimeoutMillis:  30000,
+				BackoffScaling: "0.0",
+			},
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			message := &api.Message{
+				Name: "Message",
+				ID:   ".test.Message",
+			}
+			method := &api.Method{
+				Name:         "DoFoo",
+				ID:           ".test.Service.DoFoo",
+				InputTypeID:  ".test.Message",
+				OutputTypeID: ".test.Message",
+				PathInfo:     &api.PathInfo{},
+				RetryPolicy:  test.policy,
+			}
+			service := &api.Service{
+				Name:    "FooService",
+				ID:      ".test.FooService",
+				Package: "test",
+				Methods: []*api.Method{method},
+			}
+			model := api.NewTestAPI([]*api.Message{message},
+				[]*api.Enum{},
+				[]*api.Service{service})
+			codec, err := newCodec(true, map[string]string{
+				"include-grpc-only-methods": "true",
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			annotateModel(model, codec)
+
+			got := method.Codec.(*methodAnnotation).RetryPolicy
+			if diff := cmp.Diff(test.want, got); diff != "" {
+				t.Errorf("mismatch in RetryPolicy annotation (-want, +got)\n:%s", diff)
+			}
+		})
+	}
+}
+
+func TestRetryPolicyAnnotationHelpers(t *testing.T) {
+	p := &retryPolicyAnnotation{
+		TimeoutMillis:        1000,
+		Retryable:            true,
+		InitialBackoffMillis: 100,
+		MaxBackoffMillis:     1000,
+	}
+	if !p.HasTimeout() || !p.HasBackoff() {
+		t.Errorf("expected a timeout and a backoff policy for %+v", p)
+	}
+	p = &retryPolicyAnnotation{InitialBackoffMillis: 100, MaxBackoffMillis: 1000}
+	if p.HasTimeout() || p.HasBackoff() {
+		t.Errorf("expected no timeout and no backoff policy for %+v", p)
+	}
+
+	s := &serviceAnnotations{Methods: []*api.Method{{Name: "DoFoo"}}}
+	if s.HasRetryPolicies() {
+		t.Errorf("expected no retry policies for %+v", s)
+	}
+	s.Methods = append(s.Methods, &api.Method{Name: "DoBar", RetryPolicy: &api.RetryPolicy{MaxAttempts: 3}})
+	if !s.HasRetryPolicies() {
+		t.Errorf("expected retry policies for %+v", s)
+	}
+}
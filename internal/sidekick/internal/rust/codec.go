@@ -27,6 +27,8 @@ import (
 
 	"github.com/googleapis/librarian/internal/sidekick/internal/api"
 	"github.com/googleapis/librarian/internal/sidekick/internal/language"
+	"github.com/googleapis/librarian/internal/sidekick/internal/license"
+	"github.com/googleapis/librarian/internal/sidekick/internal/surface"
 	"github.com/iancoleman/strcase"
 	"github.com/yuin/goldmark"
 	"github.com/yuin/goldmark/ast"
@@ -91,6 +93,18 @@ func newCodec(protobufSource bool, options map[string]string) (*codec, error) {
 			codec.modulePath = definition
 		case key == "copyright-year":
 			codec.generationYear = definition
+		case license.IsOption(key):
+			// Parsed by `license.NewHeaderFromOptions()` below.
+		case key == language.TemplateDirOption:
+			// Used by `language.GenerateFromModelWithOverrides()`.
+		case key == surface.Option:
+			// Used by `surface.Write()` after the code is generated.
+		case key == language.GenerateSnippetsOption:
+			value, err := strconv.ParseBool(definition)
+			if err != nil {
+				return nil, fmt.Errorf("cannot convert `%s` value %q to boolean: %w", language.GenerateSnippetsOption, definition, err)
+			}
+			codec.generateSnippets = value
 		case key == "not-for-publication":
 			value, err := strconv.ParseBool(definition)
 			if err != nil {
@@ -148,6 +162,11 @@ func newCodec(protobufSource bool, options map[string]string) (*codec, error) {
 			return nil, fmt.Errorf("unknown Rust codec option %q", key)
 		}
 	}
+	header, err := license.NewHeaderFromOptions(options)
+	if err != nil {
+		return nil, err
+	}
+	codec.licenseHeader = header
 	return codec, nil
 }
 
@@ -208,6 +227,8 @@ type codec struct {
 	nameOverrides map[string]string
 	// The year when the files were first generated.
 	generationYear string
+	// The copyright holder and license header for the generated files.
+	licenseHeader *license.Header
 	// The full path of the generated module within the crate. This defaults to
 	// `model`. When generating only a module within a larger crate (see
 	// `GenerateModule`), this overrides the path for elements within the crate.
@@ -257,6 +278,8 @@ type codec struct {
 	// If true, fail requests locally that do not yield a gRPC routing
 	// header.
 	routingRequired bool
+	// If true, generate a sample for each method.
+	generateSnippets bool
 }
 
 type systemParameter struct {
@@ -24,6 +24,7 @@ import (
 	"github.com/google/go-cmp/cmp/cmpopts"
 	"github.com/googleapis/librarian/internal/sidekick/internal/api"
 	"github.com/googleapis/librarian/internal/sidekick/internal/language"
+	"github.com/googleapis/librarian/internal/sidekick/internal/license"
 	"github.com/googleapis/librarian/internal/sidekick/internal/sample"
 )
 
@@ -67,6 +68,7 @@ func TestParseOptionsProtobuf(t *testing.T) {
 		releaseLevel:        "preview",
 		packageNameOverride: "test-only",
 		generationYear:      "2035",
+		licenseHeader:       license.DefaultHeader(),
 		modulePath:          "alternative::generated",
 		extraPackages: []*packagez{
 			gp,
@@ -122,6 +124,7 @@ func TestParseOptionsOpenAPI(t *testing.T) {
 		releaseLevel:        "preview",
 		packageNameOverride: "test-only",
 		generationYear:      "2035",
+		licenseHeader:       license.DefaultHeader(),
 		modulePath:          "crate::model",
 		extraPackages:       []*packagez{},
 		packageMapping:      map[string]*packagez{},
@@ -160,6 +163,7 @@ func TestParseOptionsTemplateOverride(t *testing.T) {
 		releaseLevel:        "preview",
 		packageNameOverride: "test-only",
 		generationYear:      "2038",
+		licenseHeader:       license.DefaultHeader(),
 		modulePath:          "crate::model",
 		extraPackages:       []*packagez{},
 		packageMapping:      map[string]*packagez{},
@@ -200,6 +204,32 @@ func TestPackageName(t *testing.T) {
 	})
 }
 
+func TestParseOptionsLicense(t *testing.T) {
+	c, err := newCodec(true, map[string]string{
+		"copyright-holder": "Example Inc.",
+		"license":          "BSD-3-Clause",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.licenseHeader.Holder != "Example Inc." {
+		t.Errorf("mismatch in copyright holder, want=%s, got=%s", "Example Inc.", c.licenseHeader.Holder)
+	}
+	if c.licenseHeader.License != "BSD-3-Clause" {
+		t.Errorf("mismatch in license, want=%s, got=%s", "BSD-3-Clause", c.licenseHeader.License)
+	}
+
+	if _, err := newCodec(true, map[string]string{"license": "unknown"}); err == nil {
+		t.Errorf("expected an error with an unknown license")
+	}
+}
+
+func TestParseOptionsTemplateDir(t *testing.T) {
+	if _, err := newCodec(true, map[string]string{"template-dir": "custom/templates"}); err != nil {
+		t.Errorf("the template-dir option should be accepted: %v", err)
+	}
+}
+
 func rustPackageNameImpl(t *testing.T, want string, opts map[string]string, api *api.API) {
 	t.Helper()
 	c, err := newCodec(true, opts)
@@ -35,7 +35,11 @@ func Generate(model *api.API, outdir string, cfg *config.Config) error {
 	annotations := annotateModel(model, codec)
 	provider := templatesProvider()
 	generatedFiles := codec.generatedFiles(annotations.HasServices())
-	return language.GenerateFromModel(outdir, model, provider, generatedFiles)
+	var snippets *language.SnippetFiles
+	if codec.generateSnippets {
+		snippets = &language.SnippetFiles{Language: "rust", Dir: snippetsDir, Snippets: codec.snippets(model)}
+	}
+	return language.GenerateFromModelAndSnippets(outdir, model, provider, generatedFiles, snippets, cfg.Codec)
 }
 
 // GenerateStorage generates Rust code for the storage service.
@@ -59,7 +63,7 @@ func GenerateStorage(outdir string, storageModel *api.API, storageConfig *config
 	}
 	provider := templatesProvider()
 	generatedFiles := language.WalkTemplatesDir(templates, "templates/storage")
-	return language.GenerateFromModel(outdir, model, provider, generatedFiles)
+	return language.GenerateFromModelWithOverrides(outdir, model, provider, generatedFiles, storageConfig.Codec)
 }
 
 type storageAnnotations struct {
@@ -103,6 +103,50 @@ func TestRustFromOpenAPI(t *testing.T) {
 	importsModelModules(t, path.Join(outDir, "src", "model.rs"))
 }
 
+func TestRustRetryPolicies(t *testing.T) {
+	outDir := t.TempDir()
+
+	cfg := &config.Config{
+		General: config.GeneralConfig{
+			SpecificationFormat: "openapi",
+			ServiceConfig:       path.Join(testdataDir, "googleapis/google/cloud/secretmanager/v1/secretmanager_v1.yaml"),
+			SpecificationSource: path.Join(testdataDir, "openapi/secretmanager_openapi_v1.json"),
+			GrpcServiceConfig:   path.Join(testdataDir, "googleapis/google/cloud/secretmanager/v1/secretmanager_grpc_service_config.json"),
+		},
+	}
+	model, err := parser.CreateModel(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := Generate(model, outDir, cfg); err != nil {
+		t.Fatal(err)
+	}
+	contents, err := os.ReadFile(path.Join(outDir, "src", "transport.rs"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := string(contents)
+	for _, want := range []string{
+		"client_retry_policy: bool,",
+		"let client_retry_policy = config.retry_policy.is_some();",
+		"if options.attempt_timeout().is_none() {",
+		"options.set_attempt_timeout(std::time::Duration::from_millis(60000));",
+		"if options.retry_policy().is_none() && !self.client_retry_policy {",
+		"options.set_retry_policy(gax::retry_policy::RetryPolicyExt::with_attempt_limit(gax::retry_policy::Aip194Strict, 5));",
+		"if options.backoff_policy().is_none() && !self.client_backoff_policy {",
+		".with_initial_delay(std::time::Duration::from_millis(1000))",
+		".with_maximum_delay(std::time::Duration::from_millis(60000))",
+		".with_scaling(1.3)",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("missing %q in generated transport.rs", want)
+		}
+	}
+	if strings.Contains(got, "NeverRetry") {
+		t.Errorf("unexpected per-request NeverRetry policy in generated transport.rs")
+	}
+}
+
 func TestRustFromProtobuf(t *testing.T) {
 	requireProtoc(t)
 	outDir := t.TempDir()
@@ -0,0 +1,106 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rust
+
+import (
+	"slices"
+
+	"github.com/googleapis/librarian/internal/sidekick/internal/language"
+	"github.com/googleapis/librarian/internal/sidekick/internal/license"
+	"github.com/googleapis/librarian/internal/sidekick/internal/options"
+	"github.com/googleapis/librarian/internal/sidekick/internal/surface"
+)
+
+// CodecOptions describes the codec options supported by the Rust codec.
+var CodecOptions = slices.Concat([]options.Option{
+	{
+		Name:        "package-name-override",
+		Type:        options.String,
+		Description: "The name of the generated crate, instead of the name derived from the specification package.",
+	},
+	{
+		Name:        "name-overrides",
+		Type:        options.List,
+		Description: "Renames services and oneofs, as a comma-separated list of `<id>=<name>` pairs.",
+	},
+	{
+		Name:        "module-path",
+		Type:        options.String,
+		Default:     "crate::model",
+		Description: "The path of the generated module within the crate.",
+	},
+	{
+		Name:        "not-for-publication",
+		Type:        options.Bool,
+		Default:     "false",
+		Description: "If true, the crate is not published, e.g. because it is only used in tests.",
+	},
+	{
+		Name:        "version",
+		Type:        options.String,
+		Default:     "0.0.0",
+		Description: "The version of the generated crate.",
+	},
+	{
+		Name:        "release-level",
+		Type:        options.String,
+		Default:     "preview",
+		Description: "The release level in the `.repo-metadata.json` file, typically `stable` or `preview`.",
+	},
+	{
+		Name:        "package:<name>",
+		Type:        options.List,
+		Description: "A Rust package used by the generated code, as a comma-separated list of `package=`, `source=`, `feature=`, `ignore=`, `force-used=`, and `used-if=` values.",
+	},
+	{
+		Name:        "disabled-rustdoc-warnings",
+		Type:        options.List,
+		Description: "The `rustdoc` warnings disabled in the generated crate.",
+	},
+	{
+		Name:        "template-override",
+		Type:        options.String,
+		Description: "Overrides the subdirectory of the embedded templates, e.g. `templates/mod`.",
+	},
+	{
+		Name:        "include-grpc-only-methods",
+		Type:        options.Bool,
+		Default:     "false",
+		Description: "If true, include methods without HTTP annotations.",
+	},
+	{
+		Name:        "per-service-features",
+		Type:        options.Bool,
+		Default:     "false",
+		Description: "If true, generate a Cargo feature for each service.",
+	},
+	{
+		Name:        "has-veneer",
+		Type:        options.Bool,
+		Default:     "false",
+		Description: "If true, the crate has a handwritten client surface.",
+	},
+	{
+		Name:        "internal-types",
+		Type:        options.List,
+		Description: "The messages which are only visible within the crate.",
+	},
+	{
+		Name:        "routing-required",
+		Type:        options.Bool,
+		Default:     "false",
+		Description: "If true, fail requests locally if they do not yield a gRPC routing header.",
+	},
+}, license.Options, language.TemplateOptions, language.SnippetOptions, surface.Options)
@@ -0,0 +1,46 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rust
+
+import (
+	"testing"
+
+	"github.com/googleapis/librarian/internal/sidekick/internal/license"
+	"github.com/googleapis/librarian/internal/sidekick/internal/options"
+)
+
+// TestCodecOptionsAccepted verifies the declared options and the options
+// accepted by `newCodec()` are in sync.
+func TestCodecOptionsAccepted(t *testing.T) {
+	for _, option := range CodecOptions {
+		key, value := option.Name, "test-value"
+		switch {
+		case option.Type == options.Bool:
+			value = "true"
+		case option.Name == "package:<name>":
+			key, value = "package:test", "package=test"
+		case option.Name == "name-overrides":
+			value = ".test.Service=Renamed"
+		case option.Name == license.OptionLicense:
+			value = "MIT"
+		case option.Name == license.OptionHeaderFile:
+			// Requires an existing file, tested in the `license` package.
+			continue
+		}
+		if _, err := newCodec(true, map[string]string{key: value}); err != nil {
+			t.Errorf("declared option %q is rejected by newCodec(): %v", option.Name, err)
+		}
+	}
+}
@@ -0,0 +1,134 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rust
+
+import (
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/googleapis/librarian/internal/sidekick/internal/api"
+	"github.com/googleapis/librarian/internal/sidekick/internal/language"
+)
+
+// The snippets are generated as Cargo examples, so they can be compiled and
+// run with `cargo run --example`.
+const snippetsDir = "examples"
+
+type snippetAnnotation struct {
+	RegionTag string
+	Model     *api.API
+	Service   *api.Service
+	Method    *api.Method
+	// The names of the client type and the client function for the method.
+	ClientName string
+	MethodName string
+	// The fields set in the request, in the order they are set.
+	RequiredFields []*snippetField
+	LongRunning    bool
+	Paginated      bool
+	ReturnsEmpty   bool
+}
+
+type snippetField struct {
+	SetterName string
+	Value      string
+}
+
+// snippets returns the samples for each generated method.
+//
+// Services with a handwritten client surface are skipped, their clients are
+// not created with the generated `builder()` function.
+func (c *codec) snippets(model *api.API) []language.Snippet {
+	ann := model.Codec.(*modelAnnotations)
+	var snippets []language.Snippet
+	for _, s := range ann.Services {
+		svcAnn := s.Codec.(*serviceAnnotations)
+		if svcAnn.HasVeneer {
+			continue
+		}
+		for _, m := range svcAnn.Methods {
+			regionTag := language.SnippetRegionTag(model, s, m)
+			var fields []*snippetField
+			for _, f := range language.SnippetRequiredFields(m, model.State) {
+				fields = append(fields, &snippetField{
+					SetterName: "set_" + toSnakeNoMangling(f.Name),
+					Value:      c.snippetValue(m, f, model.State, model.PackageName, ann.PackageNamespace),
+				})
+			}
+			snippets = append(snippets, language.Snippet{
+				RegionTag:    regionTag,
+				Service:      s,
+				Method:       m,
+				TemplatePath: "templates/snippets/snippet.mustache",
+				OutputPath:   filepath.Join(snippetsDir, svcAnn.ModuleName+"_"+toSnakeNoMangling(m.Name)+".rs"),
+				Context: &snippetAnnotation{
+					RegionTag:      regionTag,
+					Model:          model,
+					Service:        s,
+					Method:         m,
+					ClientName:     svcAnn.Name,
+					MethodName:     m.Codec.(*methodAnnotation).Name,
+					RequiredFields: fields,
+					LongRunning:    m.OperationInfo != nil,
+					Paginated:      m.Pagination != nil,
+					ReturnsEmpty:   m.ReturnsEmpty,
+				},
+			})
+		}
+	}
+	return snippets
+}
+
+// snippetValue returns a Rust expression suitable as the argument to the
+// setter for `f`.
+func (c *codec) snippetValue(m *api.Method, f *api.Field, state *api.APIState, sourceSpecificationPackageName, packageNamespace string) string {
+	if f.Typez == api.MESSAGE_TYPE {
+		if msg, ok := state.MessageByID[f.TypezID]; ok && msg.IsMap {
+			key := c.snippetValue(m, msg.Fields[0], state, sourceSpecificationPackageName, packageNamespace)
+			value := c.snippetValue(m, msg.Fields[1], state, sourceSpecificationPackageName, packageNamespace)
+			return fmt.Sprintf("[(%s, %s)]", key, value)
+		}
+	}
+	value := c.snippetSingularValue(m, f, state, sourceSpecificationPackageName, packageNamespace)
+	if f.Repeated {
+		return fmt.Sprintf("[%s]", value)
+	}
+	return value
+}
+
+func (c *codec) snippetSingularValue(m *api.Method, f *api.Field, state *api.APIState, sourceSpecificationPackageName, packageNamespace string) string {
+	switch f.Typez {
+	case api.MESSAGE_TYPE, api.ENUM_TYPE:
+		// The snippets are compiled outside the crate, references to `crate::`
+		// must use the crate name.
+		typeName := baseFieldType(f, state, c.modulePath, sourceSpecificationPackageName, c.packageMapping)
+		if rest, ok := strings.CutPrefix(typeName, "crate::"); ok {
+			typeName = packageNamespace + "::" + rest
+		}
+		return typeName + "::default()"
+	case api.STRING_TYPE:
+		return strconv.Quote(language.SnippetStringValue(m, f))
+	case api.BYTES_TYPE:
+		return `b"example".to_vec()`
+	case api.BOOL_TYPE:
+		return "true"
+	case api.FLOAT_TYPE, api.DOUBLE_TYPE:
+		return "42.0_" + scalarFieldType(f)
+	default:
+		return "42_" + scalarFieldType(f)
+	}
+}
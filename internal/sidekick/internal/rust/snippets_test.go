@@ -0,0 +1,230 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rust
+
+import (
+	"encoding/json"
+	"os"
+	"path"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/googleapis/librarian/internal/sidekick/internal/api"
+	"github.com/googleapis/librarian/internal/sidekick/internal/config"
+	"github.com/googleapis/librarian/internal/sidekick/internal/language"
+)
+
+func snippetsModel(t *testing.T) *api.API {
+	t.Helper()
+	operation := &api.Message{
+		Name:    "Operation",
+		ID:      ".google.longrunning.Operation",
+		Package: "google.longrunning",
+	}
+	widget := &api.Message{
+		Name:    "Widget",
+		ID:      ".test.v1.Widget",
+		Package: "test.v1",
+	}
+	state := &api.Enum{
+		Name:    "State",
+		ID:      ".test.v1.State",
+		Package: "test.v1",
+		Values: []*api.EnumValue{
+			{Name: "STATE_UNSPECIFIED", ID: ".test.v1.State.STATE_UNSPECIFIED", Number: 0},
+			{Name: "ACTIVE", ID: ".test.v1.State.ACTIVE", Number: 1},
+		},
+	}
+	createRequest := &api.Message{
+		Name:    "CreateWidgetRequest",
+		ID:      ".test.v1.CreateWidgetRequest",
+		Package: "test.v1",
+		Fields: []*api.Field{
+			{Name: "parent", JSONName: "parent", ID: ".test.v1.CreateWidgetRequest.parent", Typez: api.STRING_TYPE},
+			{Name: "widget", JSONName: "widget", ID: ".test.v1.CreateWidgetRequest.widget", Typez: api.MESSAGE_TYPE, TypezID: widget.ID, Optional: true, Behavior: []api.FieldBehavior{api.FIELD_BEHAVIOR_REQUIRED}},
+			{Name: "tags", JSONName: "tags", ID: ".test.v1.CreateWidgetRequest.tags", Typez: api.STRING_TYPE, Repeated: true, Behavior: []api.FieldBehavior{api.FIELD_BEHAVIOR_REQUIRED}},
+			{Name: "state", JSONName: "state", ID: ".test.v1.CreateWidgetRequest.state", Typez: api.ENUM_TYPE, TypezID: state.ID, Behavior: []api.FieldBehavior{api.FIELD_BEHAVIOR_REQUIRED}},
+			{Name: "count", JSONName: "count", ID: ".test.v1.CreateWidgetRequest.count", Typez: api.INT64_TYPE, Behavior: []api.FieldBehavior{api.FIELD_BEHAVIOR_REQUIRED}},
+			{Name: "validate_only", JSONName: "validateOnly", ID: ".test.v1.CreateWidgetRequest.validate_only", Typez: api.BOOL_TYPE},
+		},
+	}
+	pageToken := &api.Field{Name: "page_token", JSONName: "pageToken", ID: ".test.v1.ListWidgetsRequest.page_token", Typez: api.STRING_TYPE}
+	listRequest := &api.Message{
+		Name:    "ListWidgetsRequest",
+		ID:      ".test.v1.ListWidgetsRequest",
+		Package: "test.v1",
+		Fields: []*api.Field{
+			{Name: "parent", JSONName: "parent", ID: ".test.v1.ListWidgetsRequest.parent", Typez: api.STRING_TYPE},
+			{Name: "page_size", JSONName: "pageSize", ID: ".test.v1.ListWidgetsRequest.page_size", Typez: api.INT32_TYPE},
+			pageToken,
+		},
+	}
+	nextPageToken := &api.Field{Name: "next_page_token", JSONName: "nextPageToken", ID: ".test.v1.ListWidgetsResponse.next_page_token", Typez: api.STRING_TYPE}
+	widgets := &api.Field{Name: "widgets", JSONName: "widgets", ID: ".test.v1.ListWidgetsResponse.widgets", Typez: api.MESSAGE_TYPE, TypezID: widget.ID, Repeated: true}
+	listResponse := &api.Message{
+		Name:       "ListWidgetsResponse",
+		ID:         ".test.v1.ListWidgetsResponse",
+		Package:    "test.v1",
+		Fields:     []*api.Field{widgets, nextPageToken},
+		Pagination: &api.PaginationInfo{NextPageToken: nextPageToken, PageableItem: widgets},
+	}
+	parentPath := func() *api.PathTemplate {
+		return api.NewPathTemplate().
+			WithLiteral("v1").
+			WithVariable(api.NewPathVariable("parent").WithLiteral("projects").WithMatch()).
+			WithLiteral("widgets")
+	}
+	create := &api.Method{
+		Name:          "CreateWidget",
+		ID:            ".test.v1.WidgetService.CreateWidget",
+		Documentation: "Creates a widget.",
+		InputTypeID:   createRequest.ID,
+		OutputTypeID:  ".google.longrunning.Operation",
+		PathInfo: &api.PathInfo{
+			Bindings:      []*api.PathBinding{{Verb: "POST", PathTemplate: parentPath()}},
+			BodyFieldPath: "widget",
+		},
+		OperationInfo: &api.OperationInfo{
+			MetadataTypeID: ".google.protobuf.Empty",
+			ResponseTypeID: widget.ID,
+		},
+	}
+	list := &api.Method{
+		Name:          "ListWidgets",
+		ID:            ".test.v1.WidgetService.ListWidgets",
+		Documentation: "Lists widgets.",
+		InputTypeID:   listRequest.ID,
+		OutputTypeID:  listResponse.ID,
+		PathInfo: &api.PathInfo{
+			Bindings: []*api.PathBinding{{Verb: "GET", PathTemplate: parentPath()}},
+		},
+		Pagination: pageToken,
+	}
+	service := &api.Service{
+		Name:        "WidgetService",
+		ID:          ".test.v1.WidgetService",
+		Package:     "test.v1",
+		DefaultHost: "widgets.googleapis.com",
+		Methods:     []*api.Method{create, list},
+	}
+	model := api.NewTestAPI(
+		[]*api.Message{operation, widget, createRequest, listRequest, listResponse},
+		[]*api.Enum{state},
+		[]*api.Service{service})
+	model.PackageName = "test.v1"
+	loadWellKnownTypes(model.State)
+	if err := api.CrossReference(model); err != nil {
+		t.Fatal(err)
+	}
+	return model
+}
+
+func TestSnippets(t *testing.T) {
+	model := snippetsModel(t)
+	codec, err := newCodec(true, map[string]string{
+		"package-name-override":         "google-cloud-test-v1",
+		language.GenerateSnippetsOption: "true",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	annotateModel(model, codec)
+	snippets := codec.snippets(model)
+	got := language.MapSlice(snippets, func(s language.Snippet) string { return s.OutputPath })
+	want := []string{
+		path.Join("examples", "widget_service_create_widget.rs"),
+		path.Join("examples", "widget_service_list_widgets.rs"),
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("mismatch in snippet files (-want, +got):\n%s", diff)
+	}
+
+	create := snippets[0].Context.(*snippetAnnotation)
+	wantFields := []*snippetField{
+		{SetterName: "set_parent", Value: `"projects/my-project"`},
+		{SetterName: "set_widget", Value: "google_cloud_test_v1::model::Widget::default()"},
+		{SetterName: "set_tags", Value: `["my-tags"]`},
+		{SetterName: "set_state", Value: "google_cloud_test_v1::model::State::default()"},
+		{SetterName: "set_count", Value: "42_i64"},
+	}
+	if diff := cmp.Diff(wantFields, create.RequiredFields); diff != "" {
+		t.Errorf("mismatch in required fields (-want, +got):\n%s", diff)
+	}
+	if !create.LongRunning || create.Paginated {
+		t.Errorf("mismatch in snippet for %s, got=%v", create.Method.Name, create)
+	}
+	list := snippets[1].Context.(*snippetAnnotation)
+	if list.LongRunning || !list.Paginated {
+		t.Errorf("mismatch in snippet for %s, got=%v", list.Method.Name, list)
+	}
+}
+
+func TestGenerateSnippets(t *testing.T) {
+	outDir := t.TempDir()
+	cfg := &config.Config{
+		Codec: map[string]string{
+			"package-name-override":         "google-cloud-test-v1",
+			language.GenerateSnippetsOption: "true",
+		},
+	}
+	if err := Generate(snippetsModel(t), outDir, cfg); err != nil {
+		t.Fatal(err)
+	}
+	contents, err := os.ReadFile(path.Join(outDir, "examples", "widget_service_create_widget.rs"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := string(contents)
+	for _, want := range []string{
+		"// [START widgets_v1_generated_WidgetService_CreateWidget]",
+		"use google_cloud_test_v1::client::WidgetService;",
+		`.set_parent("projects/my-project")`,
+		".poller()",
+		"// [END widgets_v1_generated_WidgetService_CreateWidget]",
+		"fn main() {",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("expected %q in the CreateWidget snippet, got=\n%s", want, got)
+		}
+	}
+	contents, err = os.ReadFile(path.Join(outDir, "examples", "widget_service_list_widgets.rs"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := ".by_item();"; !strings.Contains(string(contents), want) {
+		t.Errorf("expected %q in the ListWidgets snippet, got=\n%s", want, contents)
+	}
+
+	contents, err = os.ReadFile(path.Join(outDir, "examples", language.SnippetIndexFile))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var index language.SnippetIndex
+	if err := json.Unmarshal(contents, &index); err != nil {
+		t.Fatal(err)
+	}
+	gotFiles := language.MapSlice(index.Snippets, func(e language.SnippetIndexEntry) string { return e.File })
+	wantFiles := []string{"widget_service_create_widget.rs", "widget_service_list_widgets.rs"}
+	if diff := cmp.Diff(wantFiles, gotFiles); diff != "" {
+		t.Errorf("mismatch in snippet index (-want, +got):\n%s", diff)
+	}
+}
+
+func TestGenerateSnippetsBadOption(t *testing.T) {
+	if _, err := newCodec(true, map[string]string{language.GenerateSnippetsOption: "--invalid--"}); err == nil {
+		t.Errorf("expected an error with an invalid value for %q", language.GenerateSnippetsOption)
+	}
+}
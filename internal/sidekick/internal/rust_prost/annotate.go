@@ -25,10 +25,11 @@ import (
 )
 
 type modelAnnotations struct {
-	CopyrightYear string
-	BoilerPlate   []string
-	PackageName   string
-	Files         []string
+	CopyrightYear   string
+	CopyrightHolder string
+	BoilerPlate     []string
+	PackageName     string
+	Files           []string
 	// If not empty, a segment of code to post-process the code generated by
 	// Prost or Google-protobuf.
 	PostProcessProtos []string
@@ -51,9 +52,14 @@ func (codec *codec) annotateModel(model *api.API, cfg *config.Config) error {
 	for i, f := range files {
 		files[i] = strings.TrimPrefix(f, rootSource+"/")
 	}
+	header, err := license.NewHeaderFromOptions(cfg.Codec)
+	if err != nil {
+		return err
+	}
 	annotations := &modelAnnotations{
-		CopyrightYear: codec.GenerationYear,
-		BoilerPlate: append(license.LicenseHeaderBulk(),
+		CopyrightYear:   codec.GenerationYear,
+		CopyrightHolder: header.Holder,
+		BoilerPlate: append(header.Bulk,
 			"",
 			" Code generated by sidekick. DO NOT EDIT."),
 		PackageName:       rust.PackageName(model, codec.PackageName),
@@ -44,8 +44,9 @@ func TestModelAnnotations(t *testing.T) {
 		t.Fatal(err)
 	}
 	want := &modelAnnotations{
-		PackageName:   "google-cloud-workflows-v1",
-		CopyrightYear: "2035",
+		PackageName:     "google-cloud-workflows-v1",
+		CopyrightYear:   "2035",
+		CopyrightHolder: "Google LLC",
 		Files: []string{
 			"../../testdata/googleapis/google/type/f1.proto",
 			"../../testdata/googleapis/google/type/f2.proto",
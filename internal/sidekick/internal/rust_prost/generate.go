@@ -43,7 +43,9 @@ func Generate(model *api.API, outdir string, cfg *config.Config) error {
 	}
 
 	codec := newCodec(cfg)
-	codec.annotateModel(model, cfg)
+	if err := codec.annotateModel(model, cfg); err != nil {
+		return err
+	}
 	provider := templatesProvider()
 	generatedFiles := language.WalkTemplatesDir(templates, "templates/prost")
 	tmpDir, err := os.MkdirTemp("", "rust-prost-*")
@@ -51,7 +53,7 @@ func Generate(model *api.API, outdir string, cfg *config.Config) error {
 		return fmt.Errorf("cannot create temporary directory for rust+prost output: %w", err)
 	}
 	defer os.RemoveAll(tmpDir)
-	if err := language.GenerateFromModel(tmpDir, model, provider, generatedFiles); err != nil {
+	if err := language.GenerateFromModelWithOverrides(tmpDir, model, provider, generatedFiles, cfg.Codec); err != nil {
 		return err
 	}
 	rootName := cfg.Source[codec.RootName]
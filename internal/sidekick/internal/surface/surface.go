@@ -0,0 +1,455 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package surface creates a snapshot of the public API surface of a library.
+//
+// The snapshot is a stable, sorted, and human-readable text file listing the
+// services, methods, messages, fields, and enums in the model. Generating it
+// with each library turns API changes into small diffs, which are easy to
+// review. [Parse] reads the snapshot back, for example, to detect breaking
+// changes.
+//
+// The format is line-oriented. Top-level elements start at the beginning of
+// the line, their members are indented by two spaces. Optional attributes
+// follow the element, separated by commas in square brackets:
+//
+//	package google.cloud.secretmanager.v1
+//
+//	service google.cloud.secretmanager.v1.SecretManagerService
+//	  rpc GetSecret(google.cloud.secretmanager.v1.GetSecretRequest) returns (google.cloud.secretmanager.v1.Secret) [http=GET /v1/{name=projects/*/secrets/*}]
+//
+//	message google.cloud.secretmanager.v1.Secret
+//	  field name string [output_only, identifier]
+//	  field labels map<string, string>
+//
+//	enum google.cloud.secretmanager.v1.Secret.State
+//	  value STATE_UNSPECIFIED = 0
+//
+// Lines starting with `#` are comments.
+package surface
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/googleapis/librarian/internal/sidekick/internal/api"
+	"github.com/googleapis/librarian/internal/sidekick/internal/options"
+)
+
+// Option is the codec option with the name of the API surface file, relative
+// to the output directory. The file is not generated if the option is not
+// set.
+const Option = "api-surface"
+
+// Options describes the codec options for the API surface, common to all
+// codecs.
+var Options = []options.Option{
+	{
+		Name:        Option,
+		Type:        options.String,
+		Description: "The name of the API surface file, relative to the output directory. Not generated if empty.",
+	},
+}
+
+const header = "# API surface generated by sidekick. DO NOT EDIT."
+
+// Surface is the public API surface of a library.
+type Surface struct {
+	// The specification package name, e.g. `google.cloud.secretmanager.v1`.
+	Package  string
+	Services []*Service
+	Messages []*Message
+	Enums    []*Enum
+}
+
+// Service is a service in the API surface.
+type Service struct {
+	// The fully qualified name, without the leading dot.
+	ID         string
+	Attributes []string
+	Methods    []*Method
+}
+
+// Method is a method in the API surface.
+type Method struct {
+	Name string
+	// The fully qualified names of the request and response messages.
+	Input           string
+	Output          string
+	ClientStreaming bool
+	ServerStreaming bool
+	// The HTTP bindings, and traits such as `paginated` or `deprecated`.
+	Attributes []string
+}
+
+// Message is a message in the API surface.
+type Message struct {
+	// The fully qualified name, without the leading dot.
+	ID         string
+	Attributes []string
+	// The fields in the order they are declared.
+	Fields []*Field
+}
+
+// Field is a field in the API surface.
+type Field struct {
+	Name string
+	// The type, using the protobuf syntax, e.g. `repeated string`, or
+	// `map<string, google.cloud.secretmanager.v1.Secret>`.
+	Type string
+	// The field behaviors, the containing oneof, and `deprecated`.
+	Attributes []string
+}
+
+// Enum is an enum in the API surface.
+type Enum struct {
+	// The fully qualified name, without the leading dot.
+	ID         string
+	Attributes []string
+	Values     []*EnumValue
+}
+
+// EnumValue is a value in the API surface.
+type EnumValue struct {
+	Name       string
+	Number     int32
+	Attributes []string
+}
+
+// Write creates the API surface file for a model, if it is enabled in the
+// codec options.
+func Write(model *api.API, outdir string, options map[string]string) error {
+	filename, ok := options[Option]
+	if !ok || filename == "" {
+		return nil
+	}
+	destination := filepath.Join(outdir, filename)
+	if err := os.MkdirAll(filepath.Dir(destination), 0777); err != nil {
+		return err
+	}
+	return os.WriteFile(destination, []byte(New(model).String()), 0666)
+}
+
+// New returns the API surface of a model.
+//
+// The services, messages, and enums are sorted by ID, and the methods by
+// name. Fields and enum values keep their declaration order.
+func New(model *api.API) *Surface {
+	surface := &Surface{Package: model.PackageName}
+	for _, s := range model.Services {
+		surface.Services = append(surface.Services, newService(s))
+	}
+	var addMessage func(m *api.Message)
+	addEnum := func(e *api.Enum) {
+		surface.Enums = append(surface.Enums, newEnum(e))
+	}
+	addMessage = func(m *api.Message) {
+		if m.IsMap {
+			// Map entries are part of the field type.
+			return
+		}
+		surface.Messages = append(surface.Messages, newMessage(m, model.State))
+		for _, e := range m.Enums {
+			addEnum(e)
+		}
+		for _, child := range m.Messages {
+			addMessage(child)
+		}
+	}
+	for _, m := range model.Messages {
+		addMessage(m)
+	}
+	for _, e := range model.Enums {
+		addEnum(e)
+	}
+	slices.SortFunc(surface.Services, func(a, b *Service) int { return strings.Compare(a.ID, b.ID) })
+	slices.SortFunc(surface.Messages, func(a, b *Message) int { return strings.Compare(a.ID, b.ID) })
+	slices.SortFunc(surface.Enums, func(a, b *Enum) int { return strings.Compare(a.ID, b.ID) })
+	return surface
+}
+
+func newService(s *api.Service) *Service {
+	service := &Service{ID: trimID(s.ID)}
+	if s.Deprecated {
+		service.Attributes = append(service.Attributes, "deprecated")
+	}
+	for _, m := range s.Methods {
+		method := &Method{
+			Name:            m.Name,
+			Input:           trimID(m.InputTypeID),
+			Output:          trimID(m.OutputTypeID),
+			ClientStreaming: m.ClientSideStreaming,
+			ServerStreaming: m.ServerSideStreaming,
+		}
+		if m.PathInfo != nil {
+			for _, b := range m.PathInfo.Bindings {
+				if b.PathTemplate != nil {
+					method.Attributes = append(method.Attributes, fmt.Sprintf("http=%s %s", b.Verb, b.PathTemplate.TemplateAsString()))
+				}
+			}
+		}
+		if m.OperationInfo != nil {
+			method.Attributes = append(method.Attributes,
+				"lro-response="+trimID(m.OperationInfo.ResponseTypeID),
+				"lro-metadata="+trimID(m.OperationInfo.MetadataTypeID))
+		}
+		if m.Pagination != nil {
+			method.Attributes = append(method.Attributes, "paginated")
+		}
+		if p := m.RetryPolicy; p != nil {
+			if p.Timeout != 0 {
+				method.Attributes = append(method.Attributes, "timeout="+p.Timeout.String())
+			}
+			if len(p.RetryableCodes) != 0 {
+				method.Attributes = append(method.Attributes, "retry="+strings.Join(p.RetryableCodes, "|"))
+			}
+		}
+		if m.Deprecated {
+			method.Attributes = append(method.Attributes, "deprecated")
+		}
+		service.Methods = append(service.Methods, method)
+	}
+	slices.SortFunc(service.Methods, func(a, b *Method) int { return strings.Compare(a.Name, b.Name) })
+	return service
+}
+
+func newMessage(m *api.Message, state *api.APIState) *Message {
+	message := &Message{ID: trimID(m.ID)}
+	if m.Deprecated {
+		message.Attributes = append(message.Attributes, "deprecated")
+	}
+	for _, f := range m.Fields {
+		field := &Field{Name: f.Name, Type: fieldType(f, state)}
+		for _, b := range f.Behavior {
+			if name, ok := behaviorNames[b]; ok {
+				field.Attributes = append(field.Attributes, name)
+			}
+		}
+		if f.IsOneOf && f.Group != nil {
+			field.Attributes = append(field.Attributes, "oneof="+f.Group.Name)
+		}
+		if f.Deprecated {
+			field.Attributes = append(field.Attributes, "deprecated")
+		}
+		message.Fields = append(message.Fields, field)
+	}
+	return message
+}
+
+func newEnum(e *api.Enum) *Enum {
+	enum := &Enum{ID: trimID(e.ID)}
+	if e.Deprecated {
+		enum.Attributes = append(enum.Attributes, "deprecated")
+	}
+	for _, v := range e.Values {
+		value := &EnumValue{Name: v.Name, Number: v.Number}
+		if v.Deprecated {
+			value.Attributes = append(value.Attributes, "deprecated")
+		}
+		enum.Values = append(enum.Values, value)
+	}
+	return enum
+}
+
+var behaviorNames = map[api.FieldBehavior]string{
+	api.FIELD_BEHAVIOR_OPTIONAL:                    "optional",
+	api.FIELD_BEHAVIOR_REQUIRED:                    "required",
+	api.FIELD_BEHAVIOR_OUTPUT_ONLY:                 "output_only",
+	api.FIELD_BEHAVIOR_INPUT_ONLY:                  "input_only",
+	api.FIELD_BEHAVIOR_IMMUTABLE:                   "immutable",
+	api.FIELD_BEHAVIOR_UNORDERED_LIST:              "unordered_list",
+	api.FIELD_BEHAVIOR_UNORDERED_NON_EMPTY_DEFAULT: "non_empty_default",
+	api.FIELD_BEHAVIOR_IDENTIFIER:                  "identifier",
+}
+
+var scalarNames = map[api.Typez]string{
+	api.DOUBLE_TYPE:   "double",
+	api.FLOAT_TYPE:    "float",
+	api.INT64_TYPE:    "int64",
+	api.UINT64_TYPE:   "uint64",
+	api.INT32_TYPE:    "int32",
+	api.FIXED64_TYPE:  "fixed64",
+	api.FIXED32_TYPE:  "fixed32",
+	api.BOOL_TYPE:     "bool",
+	api.STRING_TYPE:   "string",
+	api.BYTES_TYPE:    "bytes",
+	api.UINT32_TYPE:   "uint32",
+	api.SFIXED32_TYPE: "sfixed32",
+	api.SFIXED64_TYPE: "sfixed64",
+	api.SINT32_TYPE:   "sint32",
+	api.SINT64_TYPE:   "sint64",
+}
+
+// fieldType returns the type of a field using the protobuf syntax.
+func fieldType(f *api.Field, state *api.APIState) string {
+	if f.Typez == api.MESSAGE_TYPE {
+		if entry, ok := state.MessageByID[f.TypezID]; ok && entry.IsMap && len(entry.Fields) == 2 {
+			return fmt.Sprintf("map<%s, %s>", singularType(entry.Fields[0]), singularType(entry.Fields[1]))
+		}
+	}
+	switch {
+	case f.Repeated:
+		return "repeated " + singularType(f)
+	case f.Optional && f.Typez != api.MESSAGE_TYPE:
+		return "optional " + singularType(f)
+	default:
+		return singularType(f)
+	}
+}
+
+func singularType(f *api.Field) string {
+	switch f.Typez {
+	case api.MESSAGE_TYPE, api.ENUM_TYPE, api.GROUP_TYPE:
+		return trimID(f.TypezID)
+	}
+	if name, ok := scalarNames[f.Typez]; ok {
+		return name
+	}
+	return fmt.Sprintf("unknown<%d>", f.Typez)
+}
+
+func trimID(id string) string {
+	return strings.TrimPrefix(id, ".")
+}
+
+// String formats the API surface.
+func (s *Surface) String() string {
+	var lines []string
+	lines = append(lines, header, "", "package "+s.Package)
+	for _, service := range s.Services {
+		lines = append(lines, "", "service "+service.ID+formatAttributes(service.Attributes))
+		for _, m := range service.Methods {
+			lines = append(lines, fmt.Sprintf("  rpc %s(%s) returns (%s)%s",
+				m.Name, streaming(m.ClientStreaming)+m.Input, streaming(m.ServerStreaming)+m.Output, formatAttributes(m.Attributes)))
+		}
+	}
+	for _, message := range s.Messages {
+		lines = append(lines, "", "message "+message.ID+formatAttributes(message.Attributes))
+		for _, f := range message.Fields {
+			lines = append(lines, fmt.Sprintf("  field %s %s%s", f.Name, f.Type, formatAttributes(f.Attributes)))
+		}
+	}
+	for _, enum := range s.Enums {
+		lines = append(lines, "", "enum "+enum.ID+formatAttributes(enum.Attributes))
+		for _, v := range enum.Values {
+			lines = append(lines, fmt.Sprintf("  value %s = %d%s", v.Name, v.Number, formatAttributes(v.Attributes)))
+		}
+	}
+	return strings.Join(lines, "\n") + "\n"
+}
+
+func streaming(enabled bool) string {
+	if enabled {
+		return "stream "
+	}
+	return ""
+}
+
+func formatAttributes(attributes []string) string {
+	if len(attributes) == 0 {
+		return ""
+	}
+	return " [" + strings.Join(attributes, ", ") + "]"
+}
+
+var (
+	rpcRegex   = regexp.MustCompile(`^rpc (\w+)\((stream )?([\w.]+)\) returns \((stream )?([\w.]+)\)$`)
+	fieldRegex = regexp.MustCompile(`^field (\w+) (\S.*)$`)
+	valueRegex = regexp.MustCompile(`^value (\w+) = (-?\d+)$`)
+)
+
+// Parse reads an API surface in the format created by [Surface.String].
+func Parse(contents string) (*Surface, error) {
+	surface := &Surface{}
+	var (
+		service *Service
+		message *Message
+		enum    *Enum
+	)
+	for index, line := range strings.Split(contents, "\n") {
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		member, isMember := strings.CutPrefix(line, "  ")
+		text, attributes := parseAttributes(member)
+		if !isMember {
+			service, message, enum = nil, nil, nil
+			keyword, id, _ := strings.Cut(text, " ")
+			switch keyword {
+			case "package":
+				surface.Package = id
+			case "service":
+				service = &Service{ID: id, Attributes: attributes}
+				surface.Services = append(surface.Services, service)
+			case "message":
+				message = &Message{ID: id, Attributes: attributes}
+				surface.Messages = append(surface.Messages, message)
+			case "enum":
+				enum = &Enum{ID: id, Attributes: attributes}
+				surface.Enums = append(surface.Enums, enum)
+			default:
+				return nil, fmt.Errorf("line %d: unknown element %q", index+1, keyword)
+			}
+			continue
+		}
+		switch {
+		case service != nil:
+			match := rpcRegex.FindStringSubmatch(text)
+			if match == nil {
+				return nil, fmt.Errorf("line %d: expected a method in service %s, got %q", index+1, service.ID, line)
+			}
+			service.Methods = append(service.Methods, &Method{
+				Name:            match[1],
+				Input:           match[3],
+				Output:          match[5],
+				ClientStreaming: match[2] != "",
+				ServerStreaming: match[4] != "",
+				Attributes:      attributes,
+			})
+		case message != nil:
+			match := fieldRegex.FindStringSubmatch(text)
+			if match == nil {
+				return nil, fmt.Errorf("line %d: expected a field in message %s, got %q", index+1, message.ID, line)
+			}
+			message.Fields = append(message.Fields, &Field{Name: match[1], Type: match[2], Attributes: attributes})
+		case enum != nil:
+			match := valueRegex.FindStringSubmatch(text)
+			if match == nil {
+				return nil, fmt.Errorf("line %d: expected a value in enum %s, got %q", index+1, enum.ID, line)
+			}
+			number, err := strconv.ParseInt(match[2], 10, 32)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: invalid enum value number: %w", index+1, err)
+			}
+			enum.Values = append(enum.Values, &EnumValue{Name: match[1], Number: int32(number), Attributes: attributes})
+		default:
+			return nil, fmt.Errorf("line %d: unexpected member outside of a service, message, or enum: %q", index+1, line)
+		}
+	}
+	return surface, nil
+}
+
+// parseAttributes splits the attributes from the rest of the line.
+func parseAttributes(line string) (string, []string) {
+	index := strings.LastIndex(line, " [")
+	if index == -1 || !strings.HasSuffix(line, "]") {
+		return line, nil
+	}
+	return line[:index], strings.Split(line[index+2:len(line)-1], ", ")
+}
@@ -0,0 +1,198 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package surface
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/googleapis/librarian/internal/sidekick/internal/api"
+)
+
+func testModel() *api.API {
+	state := &api.Enum{
+		Name:    "State",
+		ID:      ".test.v1.Widget.State",
+		Package: "test.v1",
+		Values: []*api.EnumValue{
+			{Name: "STATE_UNSPECIFIED", Number: 0},
+			{Name: "ACTIVE", Number: 1},
+			{Name: "RETIRED", Number: 2, Deprecated: true},
+		},
+	}
+	labels := &api.Message{
+		Name:    "LabelsEntry",
+		ID:      ".test.v1.Widget.LabelsEntry",
+		Package: "test.v1",
+		IsMap:   true,
+		Fields: []*api.Field{
+			{Name: "key", Typez: api.STRING_TYPE},
+			{Name: "value", Typez: api.INT64_TYPE},
+		},
+	}
+	group := &api.OneOf{Name: "expiration"}
+	widget := &api.Message{
+		Name:     "Widget",
+		ID:       ".test.v1.Widget",
+		Package:  "test.v1",
+		Enums:    []*api.Enum{state},
+		Messages: []*api.Message{labels},
+		Fields: []*api.Field{
+			{Name: "name", Typez: api.STRING_TYPE, Behavior: []api.FieldBehavior{api.FIELD_BEHAVIOR_IDENTIFIER}},
+			{Name: "labels", Typez: api.MESSAGE_TYPE, TypezID: labels.ID, Map: true},
+			{Name: "state", Typez: api.ENUM_TYPE, TypezID: state.ID, Behavior: []api.FieldBehavior{api.FIELD_BEHAVIOR_OUTPUT_ONLY}},
+			{Name: "tags", Typez: api.STRING_TYPE, Repeated: true},
+			{Name: "size", Typez: api.INT32_TYPE, Optional: true, Deprecated: true},
+			{Name: "ttl", Typez: api.MESSAGE_TYPE, TypezID: ".google.protobuf.Duration", Optional: true, IsOneOf: true, Group: group},
+		},
+	}
+	group.Fields = []*api.Field{widget.Fields[5]}
+	request := &api.Message{
+		Name:    "CreateWidgetRequest",
+		ID:      ".test.v1.CreateWidgetRequest",
+		Package: "test.v1",
+		Fields: []*api.Field{
+			{Name: "parent", Typez: api.STRING_TYPE, Behavior: []api.FieldBehavior{api.FIELD_BEHAVIOR_REQUIRED}},
+			{Name: "widget", Typez: api.MESSAGE_TYPE, TypezID: widget.ID, Optional: true, Behavior: []api.FieldBehavior{api.FIELD_BEHAVIOR_REQUIRED}},
+		},
+	}
+	service := &api.Service{
+		Name:    "WidgetService",
+		ID:      ".test.v1.WidgetService",
+		Package: "test.v1",
+		Methods: []*api.Method{
+			{
+				Name:                "WatchWidgets",
+				ID:                  ".test.v1.WidgetService.WatchWidgets",
+				InputTypeID:         ".google.protobuf.Empty",
+				OutputTypeID:        widget.ID,
+				ServerSideStreaming: true,
+				Deprecated:          true,
+			},
+			{
+				Name:         "CreateWidget",
+				ID:           ".test.v1.WidgetService.CreateWidget",
+				InputTypeID:  request.ID,
+				OutputTypeID: ".google.longrunning.Operation",
+				PathInfo: &api.PathInfo{
+					Bindings: []*api.PathBinding{
+						{
+							Verb: "POST",
+							PathTemplate: api.NewPathTemplate().
+								WithLiteral("v1").
+								WithVariable(api.NewPathVariable("parent").WithLiteral("projects").WithMatch()).
+								WithLiteral("widgets"),
+						},
+					},
+				},
+				OperationInfo: &api.OperationInfo{
+					ResponseTypeID: widget.ID,
+					MetadataTypeID: ".google.protobuf.Empty",
+				},
+				RetryPolicy: &api.RetryPolicy{
+					Timeout:        60 * time.Second,
+					RetryableCodes: []string{"UNAVAILABLE", "RESOURCE_EXHAUSTED"},
+				},
+			},
+		},
+	}
+	model := api.NewTestAPI([]*api.Message{widget, request}, []*api.Enum{}, []*api.Service{service})
+	model.PackageName = "test.v1"
+	model.State.MessageByID[labels.ID] = labels
+	return model
+}
+
+const wantSurface = `# API surface generated by sidekick. DO NOT EDIT.
+
+package test.v1
+
+service test.v1.WidgetService
+  rpc CreateWidget(test.v1.CreateWidgetRequest) returns (google.longrunning.Operation) [http=POST /v1/{parent=projects/*}/widgets, lro-response=test.v1.Widget, lro-metadata=google.protobuf.Empty, timeout=1m0s, retry=UNAVAILABLE|RESOURCE_EXHAUSTED]
+  rpc WatchWidgets(google.protobuf.Empty) returns (stream test.v1.Widget) [deprecated]
+
+message test.v1.CreateWidgetRequest
+  field parent string [required]
+  field widget test.v1.Widget [required]
+
+message test.v1.Widget
+  field name string [identifier]
+  field labels map<string, int64>
+  field state test.v1.Widget.State [output_only]
+  field tags repeated string
+  field size optional int32 [deprecated]
+  field ttl google.protobuf.Duration [oneof=expiration]
+
+enum test.v1.Widget.State
+  value STATE_UNSPECIFIED = 0
+  value ACTIVE = 1
+  value RETIRED = 2 [deprecated]
+`
+
+func TestString(t *testing.T) {
+	got := New(testModel()).String()
+	if diff := cmp.Diff(wantSurface, got); diff != "" {
+		t.Errorf("mismatch (-want, +got):\n%s", diff)
+	}
+}
+
+func TestParse(t *testing.T) {
+	want := New(testModel())
+	got, err := Parse(want.String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("mismatch (-want, +got):\n%s", diff)
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	for _, input := range []string{
+		"unknown test.v1.Widget\n",
+		"  field orphan string\n",
+		"service test.v1.WidgetService\n  field name string\n",
+		"message test.v1.Widget\n  rpc Get(A) returns (B)\n",
+		"enum test.v1.State\n  value ACTIVE = x\n",
+		"enum test.v1.State\n  value ACTIVE = 9999999999\n",
+	} {
+		if got, err := Parse(input); err == nil {
+			t.Errorf("expected an error parsing %q, got=%v", input, got)
+		}
+	}
+}
+
+func TestWrite(t *testing.T) {
+	outDir := t.TempDir()
+	if err := Write(testModel(), outDir, map[string]string{}); err != nil {
+		t.Fatal(err)
+	}
+	if entries, _ := os.ReadDir(outDir); len(entries) != 0 {
+		t.Errorf("expected no files without the %q option, got=%v", Option, entries)
+	}
+
+	if err := Write(testModel(), outDir, map[string]string{Option: "api/surface.txt"}); err != nil {
+		t.Fatal(err)
+	}
+	got, err := os.ReadFile(filepath.Join(outDir, "api", "surface.txt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(wantSurface, string(got)); diff != "" {
+		t.Errorf("mismatch (-want, +got):\n%s", diff)
+	}
+}
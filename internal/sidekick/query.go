@@ -0,0 +1,341 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sidekick
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/googleapis/librarian/internal/sidekick/internal/api"
+	"github.com/googleapis/librarian/internal/sidekick/internal/config"
+	"github.com/googleapis/librarian/internal/sidekick/internal/parser"
+)
+
+var (
+	flagQueryJSON bool
+	flagQueryID   string
+)
+
+var cmdQuery = newCommand(
+	"sidekick query",
+	"Answers questions about an API specification.",
+	`
+Loads an API specification and answers questions about it, for example, which methods are long-running operations
+or paginated, or what messages are needed by a method.
+
+The specification is loaded from the -specification-format, -specification-source, and -service-config flags. If
+these flags are not set, the command uses the .sidekick.toml file in the -output directory.
+
+The results are printed to stdout as text, one element per line, or as a JSON array with -json.
+`,
+	cmdSidekick,
+	nil).
+	addFlagBool(&flagQueryJSON, "json", false, "print the results as a JSON array")
+
+func init() {
+	newCommand(
+		"sidekick query methods",
+		"Lists the methods with their HTTP bindings, and whether they are LROs, paginated, streaming, or routed.",
+		`
+Lists the methods of all the services in the API. Each method is listed with its HTTP bindings and its traits:
+lro, paginated, client-streaming, server-streaming, routed, and deprecated.
+`,
+		cmdQuery,
+		queryMethods,
+	)
+	newCommand(
+		"sidekick query dependencies",
+		"Lists the transitive dependencies of an element.",
+		`
+Lists the IDs of all the elements required by the element given in -id, including the element itself. For
+example, the dependencies of a method include its service, and the request and response messages.
+`,
+		cmdQuery,
+		queryDependencies,
+	).
+		addFlagString(&flagQueryID, "id", "the fully-qualified ID of the element, e.g. .google.cloud.secretmanager.v1.Secret")
+	newCommand(
+		"sidekick query recursive",
+		"Lists the fields which recursively reference their containing message.",
+		`
+Lists the fields which, directly or indirectly, reference their containing message. Some languages require
+special handling for these fields.
+`,
+		cmdQuery,
+		queryRecursive,
+	)
+	newCommand(
+		"sidekick query deprecated",
+		"Lists the deprecated services, methods, messages, fields, enums, and enum values.",
+		`
+Lists the deprecated services, methods, messages, fields, enums, and enum values in the API.
+`,
+		cmdQuery,
+		queryDeprecated,
+	)
+}
+
+// queriedMethod describes a method in the results of `sidekick query methods`.
+type queriedMethod struct {
+	ID       string   `json:"id"`
+	Bindings []string `json:"bindings,omitempty"`
+	Traits   []string `json:"traits,omitempty"`
+}
+
+// queriedField describes a field in the results of `sidekick query recursive`.
+type queriedField struct {
+	ID     string `json:"id"`
+	TypeID string `json:"typeId"`
+}
+
+// queriedElement describes an element in the results of `sidekick query
+// deprecated`.
+type queriedElement struct {
+	ID   string `json:"id"`
+	Kind string `json:"kind"`
+}
+
+func queryMethods(rootConfig *config.Config, cmdLine *CommandLine) error {
+	model, err := loadQueryModel(rootConfig, cmdLine)
+	if err != nil {
+		return err
+	}
+	return writeQueryResults(os.Stdout, findMethods(model), flagQueryJSON, func(m queriedMethod) string {
+		line := m.ID
+		if len(m.Bindings) != 0 {
+			line += " " + strings.Join(m.Bindings, ", ")
+		}
+		if len(m.Traits) != 0 {
+			line += " [" + strings.Join(m.Traits, ", ") + "]"
+		}
+		return line
+	})
+}
+
+func queryDependencies(rootConfig *config.Config, cmdLine *CommandLine) error {
+	if flagQueryID == "" {
+		return fmt.Errorf("sidekick query dependencies requires an -id")
+	}
+	model, err := loadQueryModel(rootConfig, cmdLine)
+	if err != nil {
+		return err
+	}
+	dependencies, err := findDependencies(model, flagQueryID)
+	if err != nil {
+		return err
+	}
+	return writeQueryResults(os.Stdout, dependencies, flagQueryJSON, func(id string) string { return id })
+}
+
+func queryRecursive(rootConfig *config.Config, cmdLine *CommandLine) error {
+	model, err := loadQueryModel(rootConfig, cmdLine)
+	if err != nil {
+		return err
+	}
+	return writeQueryResults(os.Stdout, findRecursiveFields(model), flagQueryJSON, func(f queriedField) string {
+		return fmt.Sprintf("%s -> %s", f.ID, f.TypeID)
+	})
+}
+
+func queryDeprecated(rootConfig *config.Config, cmdLine *CommandLine) error {
+	model, err := loadQueryModel(rootConfig, cmdLine)
+	if err != nil {
+		return err
+	}
+	return writeQueryResults(os.Stdout, findDeprecated(model), flagQueryJSON, func(e queriedElement) string {
+		return fmt.Sprintf("%s %s", e.Kind, e.ID)
+	})
+}
+
+// loadQueryModel loads the specification given in the command line, or the
+// specification for the library in `cmdLine.Output`.
+func loadQueryModel(rootConfig *config.Config, cmdLine *CommandLine) (*api.API, error) {
+	override, err := overrideSources(rootConfig)
+	if err != nil {
+		return nil, err
+	}
+	if cmdLine.SpecificationSource == "" {
+		model, _, err := loadDir(override, cmdLine.Output)
+		return model, err
+	}
+	if cmdLine.SpecificationFormat == "" {
+		return nil, fmt.Errorf("must provide -specification-format with -specification-source")
+	}
+	override.General.SpecificationFormat = cmdLine.SpecificationFormat
+	override.General.SpecificationSource = cmdLine.SpecificationSource
+	override.General.ServiceConfig = cmdLine.ServiceConfig
+	override.General.GrpcServiceConfig = cmdLine.GrpcServiceConfig
+	return parser.CreateModel(override)
+}
+
+// writeQueryResults prints the results as a JSON array, or as text with one
+// line per result.
+func writeQueryResults[T any](w io.Writer, results []T, asJSON bool, text func(T) string) error {
+	if asJSON {
+		if results == nil {
+			results = []T{}
+		}
+		contents, err := json.MarshalIndent(results, "", "  ")
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintln(w, string(contents))
+		return err
+	}
+	for _, result := range results {
+		if _, err := fmt.Fprintln(w, text(result)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func findMethods(model *api.API) []queriedMethod {
+	var methods []queriedMethod
+	for _, s := range model.Services {
+		for _, m := range s.Methods {
+			methods = append(methods, queriedMethod{
+				ID:       m.ID,
+				Bindings: methodBindings(m),
+				Traits:   methodTraits(m),
+			})
+		}
+	}
+	sort.Slice(methods, func(i, j int) bool { return methods[i].ID < methods[j].ID })
+	return methods
+}
+
+func methodBindings(m *api.Method) []string {
+	if m.PathInfo == nil {
+		return nil
+	}
+	var bindings []string
+	for _, b := range m.PathInfo.Bindings {
+		if b.PathTemplate == nil {
+			continue
+		}
+		bindings = append(bindings, fmt.Sprintf("%s %s", b.Verb, b.PathTemplate.TemplateAsString()))
+	}
+	return bindings
+}
+
+func methodTraits(m *api.Method) []string {
+	var traits []string
+	if m.OperationInfo != nil {
+		traits = append(traits, "lro")
+	}
+	if m.Pagination != nil {
+		traits = append(traits, "paginated")
+	}
+	if m.ClientSideStreaming {
+		traits = append(traits, "client-streaming")
+	}
+	if m.ServerSideStreaming {
+		traits = append(traits, "server-streaming")
+	}
+	if m.HasRouting() {
+		traits = append(traits, "routed")
+	}
+	if m.Deprecated {
+		traits = append(traits, "deprecated")
+	}
+	return traits
+}
+
+func findDependencies(model *api.API, id string) ([]string, error) {
+	found, err := api.FindDependencies(model, []string{id})
+	if err != nil {
+		return nil, err
+	}
+	var ids []string
+	for id := range found {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids, nil
+}
+
+func findRecursiveFields(model *api.API) []queriedField {
+	var fields []queriedField
+	for _, m := range allMessages(model) {
+		for _, f := range m.Fields {
+			if f.Recursive {
+				fields = append(fields, queriedField{ID: m.ID + "." + f.Name, TypeID: f.TypezID})
+			}
+		}
+	}
+	sort.Slice(fields, func(i, j int) bool { return fields[i].ID < fields[j].ID })
+	return fields
+}
+
+func findDeprecated(model *api.API) []queriedElement {
+	var elements []queriedElement
+	add := func(deprecated bool, id, kind string) {
+		if deprecated {
+			elements = append(elements, queriedElement{ID: id, Kind: kind})
+		}
+	}
+	addEnum := func(e *api.Enum) {
+		add(e.Deprecated, e.ID, "enum")
+		for _, v := range e.Values {
+			add(v.Deprecated, e.ID+"."+v.Name, "enum-value")
+		}
+	}
+	for _, s := range model.Services {
+		add(s.Deprecated, s.ID, "service")
+		for _, m := range s.Methods {
+			add(m.Deprecated, m.ID, "method")
+		}
+	}
+	for _, m := range allMessages(model) {
+		add(m.Deprecated, m.ID, "message")
+		for _, f := range m.Fields {
+			add(f.Deprecated, m.ID+"."+f.Name, "field")
+		}
+		for _, e := range m.Enums {
+			addEnum(e)
+		}
+	}
+	for _, e := range model.Enums {
+		addEnum(e)
+	}
+	sort.SliceStable(elements, func(i, j int) bool { return elements[i].ID < elements[j].ID })
+	return elements
+}
+
+// allMessages returns the messages in the model, including nested messages.
+func allMessages(model *api.API) []*api.Message {
+	seen := map[string]bool{}
+	var messages []*api.Message
+	var add func(m *api.Message)
+	add = func(m *api.Message) {
+		if seen[m.ID] {
+			return
+		}
+		seen[m.ID] = true
+		messages = append(messages, m)
+		for _, child := range m.Messages {
+			add(child)
+		}
+	}
+	for _, m := range model.Messages {
+		add(m)
+	}
+	return messages
+}
@@ -0,0 +1,226 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sidekick
+
+import (
+	"bytes"
+	"path"
+	"slices"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/googleapis/librarian/internal/sidekick/internal/api"
+	"github.com/googleapis/librarian/internal/sidekick/internal/config"
+)
+
+func TestQueryMethods(t *testing.T) {
+	model, err := loadQueryModel(&config.Config{}, &CommandLine{
+		SpecificationFormat: "openapi",
+		SpecificationSource: specificationSource,
+		ServiceConfig:       path.Join(testdataDir, secretManagerServiceConfig),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	methods := findMethods(model)
+	idx := slices.IndexFunc(methods, func(m queriedMethod) bool {
+		return m.ID == ".google.cloud.secretmanager.v1.SecretManagerService.ListSecretVersions"
+	})
+	if idx == -1 {
+		t.Fatalf("missing ListSecretVersions in %v", methods)
+	}
+	want := queriedMethod{
+		ID:       ".google.cloud.secretmanager.v1.SecretManagerService.ListSecretVersions",
+		Bindings: []string{"GET /v1/projects/{project}/secrets/{secret}/versions"},
+		Traits:   []string{"paginated"},
+	}
+	if diff := cmp.Diff(want, methods[idx]); diff != "" {
+		t.Errorf("mismatch in findMethods (-want, +got)\n:%s", diff)
+	}
+}
+
+func TestLoadQueryModelMissingFormat(t *testing.T) {
+	if _, err := loadQueryModel(&config.Config{}, &CommandLine{SpecificationSource: specificationSource}); err == nil {
+		t.Errorf("expected an error without -specification-format")
+	}
+}
+
+func TestMethodTraits(t *testing.T) {
+	m := &api.Method{
+		ID:                  ".test.Service.Method",
+		OperationInfo:       &api.OperationInfo{},
+		Pagination:          &api.Field{},
+		ClientSideStreaming: true,
+		ServerSideStreaming: true,
+		Routing:             []*api.RoutingInfo{{Name: "name"}},
+		Deprecated:          true,
+	}
+	want := []string{"lro", "paginated", "client-streaming", "server-streaming", "routed", "deprecated"}
+	if diff := cmp.Diff(want, methodTraits(m)); diff != "" {
+		t.Errorf("mismatch in methodTraits (-want, +got)\n:%s", diff)
+	}
+}
+
+func TestMethodBindings(t *testing.T) {
+	m := &api.Method{
+		ID: ".test.Service.Method",
+		PathInfo: &api.PathInfo{
+			Bindings: []*api.PathBinding{
+				{
+					Verb: "GET",
+					PathTemplate: api.NewPathTemplate().
+						WithLiteral("v1").
+						WithVariableNamed("name"),
+				},
+				{
+					Verb: "POST",
+					PathTemplate: api.NewPathTemplate().
+						WithLiteral("v1").
+						WithVariable(api.NewPathVariable("secret", "name").
+							WithLiteral("projects").WithMatch().
+							WithLiteral("secrets").WithMatch()).
+						WithVerb("access"),
+				},
+				{
+					Verb: "DELETE",
+					PathTemplate: api.NewPathTemplate().
+						WithLiteral("v1").
+						WithVariable(api.NewPathVariable("name").WithMatchRecursive()),
+				},
+				{Verb: "PATCH"},
+			},
+		},
+	}
+	want := []string{
+		"GET /v1/{name}",
+		"POST /v1/{secret.name=projects/*/secrets/*}:access",
+		"DELETE /v1/{name=**}",
+	}
+	if diff := cmp.Diff(want, methodBindings(m)); diff != "" {
+		t.Errorf("mismatch in methodBindings (-want, +got)\n:%s", diff)
+	}
+	if got := methodBindings(&api.Method{ID: ".test.Service.NoPath"}); got != nil {
+		t.Errorf("methodBindings() = %v, want nil for a method without path info", got)
+	}
+}
+
+func TestFindDependencies(t *testing.T) {
+	request := &api.Message{Name: "Request", ID: ".test.Request", Package: "test"}
+	response := &api.Message{Name: "Response", ID: ".test.Response", Package: "test"}
+	unused := &api.Message{Name: "Unused", ID: ".test.Unused", Package: "test"}
+	service := &api.Service{
+		Name:    "Service",
+		ID:      ".test.Service",
+		Package: "test",
+		Methods: []*api.Method{
+			{Name: "Method", ID: ".test.Service.Method", InputTypeID: ".test.Request", OutputTypeID: ".test.Response"},
+		},
+	}
+	model := api.NewTestAPI([]*api.Message{request, response, unused}, []*api.Enum{}, []*api.Service{service})
+	api.CrossReference(model)
+	got, err := findDependencies(model, ".test.Service.Method")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{".test.Request", ".test.Response", ".test.Service", ".test.Service.Method"}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("mismatch in findDependencies (-want, +got)\n:%s", diff)
+	}
+	if _, err := findDependencies(model, ".test.Missing"); err == nil {
+		t.Errorf("expected an error for an unknown ID")
+	}
+}
+
+func TestFindRecursiveAndDeprecated(t *testing.T) {
+	tree := &api.Message{
+		Name:       "Tree",
+		ID:         ".test.Tree",
+		Package:    "test",
+		Deprecated: true,
+		Fields: []*api.Field{
+			{Name: "children", Typez: api.MESSAGE_TYPE, TypezID: ".test.Tree", Repeated: true},
+			{Name: "label", Typez: api.STRING_TYPE, Deprecated: true},
+		},
+	}
+	color := &api.Enum{
+		Name:    "Color",
+		ID:      ".test.Color",
+		Package: "test",
+		Values: []*api.EnumValue{
+			{Name: "RED", Number: 0},
+			{Name: "BLUE", Number: 1, Deprecated: true},
+		},
+	}
+	service := &api.Service{
+		Name:    "Service",
+		ID:      ".test.Service",
+		Package: "test",
+		Methods: []*api.Method{
+			{Name: "Method", ID: ".test.Service.Method", Deprecated: true},
+		},
+	}
+	model := api.NewTestAPI([]*api.Message{tree}, []*api.Enum{color}, []*api.Service{service})
+	api.LabelRecursiveFields(model)
+
+	wantRecursive := []queriedField{{ID: ".test.Tree.children", TypeID: ".test.Tree"}}
+	if diff := cmp.Diff(wantRecursive, findRecursiveFields(model)); diff != "" {
+		t.Errorf("mismatch in findRecursiveFields (-want, +got)\n:%s", diff)
+	}
+	wantDeprecated := []queriedElement{
+		{ID: ".test.Color.BLUE", Kind: "enum-value"},
+		{ID: ".test.Service.Method", Kind: "method"},
+		{ID: ".test.Tree", Kind: "message"},
+		{ID: ".test.Tree.label", Kind: "field"},
+	}
+	if diff := cmp.Diff(wantDeprecated, findDeprecated(model)); diff != "" {
+		t.Errorf("mismatch in findDeprecated (-want, +got)\n:%s", diff)
+	}
+}
+
+func TestWriteQueryResults(t *testing.T) {
+	results := []queriedElement{{ID: ".test.Tree", Kind: "message"}}
+	text := func(e queriedElement) string { return e.Kind + " " + e.ID }
+
+	var output bytes.Buffer
+	if err := writeQueryResults(&output, results, false, text); err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff("message .test.Tree\n", output.String()); diff != "" {
+		t.Errorf("mismatch in text output (-want, +got)\n:%s", diff)
+	}
+
+	output.Reset()
+	if err := writeQueryResults(&output, results, true, text); err != nil {
+		t.Fatal(err)
+	}
+	want := `[
+  {
+    "id": ".test.Tree",
+    "kind": "message"
+  }
+]
+`
+	if diff := cmp.Diff(want, output.String()); diff != "" {
+		t.Errorf("mismatch in JSON output (-want, +got)\n:%s", diff)
+	}
+
+	output.Reset()
+	if err := writeQueryResults(&output, []queriedElement(nil), true, text); err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff("[]\n", output.String()); diff != "" {
+		t.Errorf("mismatch in empty JSON output (-want, +got)\n:%s", diff)
+	}
+}
@@ -23,9 +23,13 @@ import (
 	"github.com/googleapis/librarian/internal/sidekick/internal/config"
 	"github.com/googleapis/librarian/internal/sidekick/internal/dart"
 	"github.com/googleapis/librarian/internal/sidekick/internal/gcloud"
+	"github.com/googleapis/librarian/internal/sidekick/internal/golang"
+	"github.com/googleapis/librarian/internal/sidekick/internal/mock_server"
+	"github.com/googleapis/librarian/internal/sidekick/internal/options"
 	"github.com/googleapis/librarian/internal/sidekick/internal/parser"
 	"github.com/googleapis/librarian/internal/sidekick/internal/rust"
 	"github.com/googleapis/librarian/internal/sidekick/internal/rust_prost"
+	"github.com/googleapis/librarian/internal/sidekick/internal/surface"
 )
 
 func init() {
@@ -73,10 +77,21 @@ func refreshDir(rootConfig *config.Config, cmdLine *CommandLine, output string)
 	if err != nil {
 		return err
 	}
+	if err := validateCodecOptions(config); err != nil {
+		return fmt.Errorf("invalid configuration in %s: %w", output, err)
+	}
 	if cmdLine.DryRun {
 		return nil
 	}
 
+	if err := generateLanguage(rootConfig, model, config, output); err != nil {
+		return err
+	}
+	return surface.Write(model, output, config.Codec)
+}
+
+// generateLanguage runs the codec selected in the configuration.
+func generateLanguage(rootConfig *config.Config, model *api.API, config *config.Config, output string) error {
 	switch config.General.Language {
 	case "rust":
 		return rust.Generate(model, output, config)
@@ -97,11 +112,55 @@ func refreshDir(rootConfig *config.Config, cmdLine *CommandLine, output string)
 		return rust_prost.Generate(model, output, config)
 	case "dart":
 		return dart.Generate(model, output, config)
+	case "go":
+		return golang.Generate(model, output, config)
 	case "sample":
 		return codec_sample.Generate(model, output, config)
 	case "gcloud":
 		return gcloud.Generate(model, output, config)
+	case "mock-server":
+		return mock_server.Generate(model, output, config)
 	default:
 		return fmt.Errorf("unknown language: %s", config.General.Language)
 	}
 }
+
+// codecOptions returns the codec options supported by `language`.
+func codecOptions(language string) ([]options.Option, error) {
+	switch language {
+	case "rust", "rust_storage":
+		return rust.CodecOptions, nil
+	case "rust+prost":
+		return rust_prost.CodecOptions, nil
+	case "dart":
+		return dart.CodecOptions, nil
+	case "go":
+		return golang.CodecOptions, nil
+	case "sample":
+		return codec_sample.CodecOptions, nil
+	case "gcloud":
+		return gcloud.CodecOptions, nil
+	case "mock-server":
+		return mock_server.CodecOptions, nil
+	default:
+		return nil, fmt.Errorf("unknown language: %s", language)
+	}
+}
+
+// validateCodecOptions checks the codec options against the options declared
+// by the codec.
+//
+// Options inherited from the top-level `.sidekick.toml` file may be intended
+// for other languages, these are only validated if the codec declares them.
+// Options set in the command line are always validated.
+func validateCodecOptions(cfg *config.Config) error {
+	schema, err := codecOptions(cfg.General.Language)
+	if err != nil {
+		return err
+	}
+	rootConfig, err := config.LoadRootConfig(".sidekick.toml")
+	if err != nil {
+		return err
+	}
+	return options.Validate(cfg.General.Language, schema, cfg.Codec, rootConfig.Codec)
+}
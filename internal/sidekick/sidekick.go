@@ -33,6 +33,7 @@ var cmdSidekick = newCommand(
 	addFlagString(&format, "specification-format", "the specification format. Protobuf or OpenAPI v3.").
 	addFlagString(&source, "specification-source", "the path to the input data").
 	addFlagString(&serviceConfig, "service-config", "path to service config").
+	addFlagString(&grpcConfig, "grpc-service-config", "path to the gRPC service config with the retry and timeout policies").
 	addFlagString(&output, "output", "the path within project-root to put generated files").
 	addFlagString(&flagLanguage, "language", "the generated language").
 	addFlagBool(&dryrun, "dry-run", false, "do a dry-run: load the configuration, but do not perform any changes.").
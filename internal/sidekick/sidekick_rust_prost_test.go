@@ -40,8 +40,7 @@ func TestRustProstFromProtobuf(t *testing.T) {
 		Language:      "rust+prost",
 		Output:        outDir,
 		Codec: map[string]string{
-			"copyright-year":      "2025",
-			"not-for-publication": "true",
+			"copyright-year": "2025",
 		},
 	}
 	cmdGenerate, _, _ := cmdSidekick.lookup([]string{"generate"})
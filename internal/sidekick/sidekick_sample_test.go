@@ -39,8 +39,7 @@ func TestSampleFromProtobuf(t *testing.T) {
 		Language:      "sample",
 		Output:        outDir,
 		Codec: map[string]string{
-			"copyright-year":      "2025",
-			"not-for-publication": "true",
+			"copyright-year": "2025",
 		},
 	}
 	cmdGenerate, _, _ := cmdSidekick.lookup([]string{"generate"})
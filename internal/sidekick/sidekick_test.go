@@ -28,11 +28,12 @@ const (
 )
 
 var (
-	testdataDir, _             = filepath.Abs("testdata")
-	googleapisRoot             = fmt.Sprintf("%s/googleapis", testdataDir)
-	outputDir                  = fmt.Sprintf("%s/test-only", testdataDir)
-	secretManagerServiceConfig = "googleapis/google/cloud/secretmanager/v1/secretmanager_v1.yaml"
-	specificationSource        = fmt.Sprintf("%s/openapi/secretmanager_openapi_v1.json", testdataDir)
+	testdataDir, _                 = filepath.Abs("testdata")
+	googleapisRoot                 = fmt.Sprintf("%s/googleapis", testdataDir)
+	outputDir                      = fmt.Sprintf("%s/test-only", testdataDir)
+	secretManagerServiceConfig     = "googleapis/google/cloud/secretmanager/v1/secretmanager_v1.yaml"
+	secretManagerGrpcServiceConfig = "googleapis/google/cloud/secretmanager/v1/secretmanager_grpc_service_config.json"
+	specificationSource            = fmt.Sprintf("%s/openapi/secretmanager_openapi_v1.json", testdataDir)
 )
 
 func requireCommand(t *testing.T, command string) {